@@ -1,12 +1,15 @@
 package assert
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // **************************************************
@@ -64,6 +67,15 @@ func AssertNonZeroFloat32(value float32) error {
 	return nil
 }
 
+// AssertNonZero checks if a value is not the zero value of its type
+func AssertNonZero[T comparable](value T) error {
+	var zero T
+	if value == zero {
+		return fmt.Errorf("%T cannot be zero", value)
+	}
+	return nil
+}
+
 // AssertPositiveInt checks if an integer is positive
 func AssertPositiveInt(value int) error {
 	if value <= 0 {
@@ -120,9 +132,10 @@ func AssertNonEmptyInterface(value any) error {
 	return nil
 }
 
-// AssertNonEmptyPointer checks if a pointer is not nil
+// AssertNonEmptyPointer checks if a pointer is not nil, including a typed
+// nil pointer such as (*T)(nil)
 func AssertNonEmptyPointer(value any) error {
-	if value == nil {
+	if isNil(value) {
 		return errors.New("pointer cannot be nil")
 	}
 	return nil
@@ -143,8 +156,10 @@ func AssertNonEmptyTime(value time.Time) error {
 // --------------------------------------------------
 // **************************************************
 
-// AssertInRange checks if a number is within a specific range (inclusive)
-func AssertInRange(value, min, max float64) error {
+// AssertInRange checks if a value is within a specific range (inclusive).
+// Values are compared in their own type, so large int64 and uint64 values
+// are compared exactly.
+func AssertInRange[T cmp.Ordered](value, min, max T) error {
 	if value < min || value > max {
 		return fmt.Errorf("value %v must be between %v and %v", value, min, max)
 	}
@@ -211,6 +226,22 @@ func AssertMaxLength(value interface{}, maxLength int) error {
 	return nil
 }
 
+// AssertMinLen checks if a slice has at least minLength elements
+func AssertMinLen[T any](value []T, minLength int) error {
+	if len(value) < minLength {
+		return fmt.Errorf("length %d must be at least %d", len(value), minLength)
+	}
+	return nil
+}
+
+// AssertMaxLen checks if a slice has at most maxLength elements
+func AssertMaxLen[T any](value []T, maxLength int) error {
+	if len(value) > maxLength {
+		return fmt.Errorf("length %d must be at most %d", len(value), maxLength)
+	}
+	return nil
+}
+
 // AssertMinValue checks if a numeric value is at least the minimum
 func AssertMinValue(value, minValue float64) error {
 	if value < minValue {
@@ -278,7 +309,7 @@ func AssertValidJSON(jsonStr string) error {
 // **************************************************
 
 // AssertContains checks if a slice contains a specific value
-func AssertContains(slice []interface{}, value interface{}) error {
+func AssertContains[T comparable](slice []T, value T) error {
 	for _, item := range slice {
 		if item == value {
 			return nil
@@ -476,3 +507,53 @@ func AssertContainsString(value, substring string) error {
 	}
 	return nil
 }
+
+// AssertAlphanumeric checks if a string contains only letters and digits,
+// in any script
+func AssertAlphanumeric(value string) error {
+	for i, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return fmt.Errorf("string contains non-alphanumeric character %q at byte %d", r, i)
+		}
+	}
+	return nil
+}
+
+// AssertASCIIOnly checks if a string contains only ASCII characters
+func AssertASCIIOnly(value string) error {
+	for i, r := range value {
+		if r > unicode.MaxASCII {
+			return fmt.Errorf("string contains non-ASCII character %q at byte %d", r, i)
+		}
+	}
+	return nil
+}
+
+// AssertNoControlChars checks if a string contains no control characters
+// other than tab, newline and carriage return, the same characters
+// sanitize.RemoveControlChars keeps
+func AssertNoControlChars(value string) error {
+	for i, r := range value {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return fmt.Errorf("string contains control character %U at byte %d", r, i)
+		}
+	}
+	return nil
+}
+
+// AssertUTF8Valid checks if a string is valid UTF-8
+func AssertUTF8Valid(value string) error {
+	if !utf8.ValidString(value) {
+		return errors.New("string is not valid UTF-8")
+	}
+	return nil
+}
+
+// AssertMaxRunes checks if a string has at most maxRunes characters, unlike
+// AssertMaxLength which counts bytes
+func AssertMaxRunes(value string, maxRunes int) error {
+	if count := utf8.RuneCountInString(value); count > maxRunes {
+		return fmt.Errorf("length %d characters must be at most %d", count, maxRunes)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package assertt adapts the assert package to tests, so the rules used for
+// validation in production can drive unit tests without duplication.
+//
+//	func TestCreateUser(t *testing.T) {
+//		user := createUser(t)
+//		assertt.ValidUUID(t, user.ID)
+//		assertt.DeepEqual(t, user.Roles, []string{"member"})
+//	}
+package assertt
+
+import (
+	"cmp"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arbenlabs/stoner/assert"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Adapters
+// --------------------------------------------------
+// **************************************************
+
+// Require stops the test if an assertion returned an error
+func Require(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+// Check marks the test as failed if an assertion returned an error, but
+// lets it continue
+func Check(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+// NoError stops the test if err is not nil
+func NoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Error stops the test if err is nil
+func Error(t testing.TB, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+}
+
+// **************************************************
+// --------------------------------------------------
+// Assertions
+// Each assertion stops the test with the assertion's message.
+// --------------------------------------------------
+// **************************************************
+
+// True stops the test if value is false
+func True(t testing.TB, value bool) {
+	t.Helper()
+	Require(t, assert.AssertTrue(value))
+}
+
+// False stops the test if value is true
+func False(t testing.TB, value bool) {
+	t.Helper()
+	Require(t, assert.AssertFalse(value))
+}
+
+// Equal stops the test if actual and expected are not equal
+func Equal[T comparable](t testing.TB, actual, expected T) {
+	t.Helper()
+	Require(t, assert.AssertEqual(actual, expected))
+}
+
+// NotEqual stops the test if actual and expected are equal
+func NotEqual[T comparable](t testing.TB, actual, expected T) {
+	t.Helper()
+	Require(t, assert.AssertNotEqual(actual, expected))
+}
+
+// DeepEqual stops the test if actual and expected are not deeply equal,
+// reporting where they differ
+func DeepEqual(t testing.TB, actual, expected any) {
+	t.Helper()
+	Require(t, assert.AssertDeepEqual(actual, expected))
+}
+
+// NotNil stops the test if value is nil or a typed nil
+func NotNil(t testing.TB, value any) {
+	t.Helper()
+	Require(t, assert.AssertNotNil(value))
+}
+
+// NonZero stops the test if value is the zero value of its type
+func NonZero[T comparable](t testing.TB, value T) {
+	t.Helper()
+	Require(t, assert.AssertNonZero(value))
+}
+
+// NonEmptyString stops the test if value is empty
+func NonEmptyString(t testing.TB, value string) {
+	t.Helper()
+	Require(t, assert.AssertNonEmptyString(value))
+}
+
+// InRange stops the test if value is outside min and max (inclusive)
+func InRange[T cmp.Ordered](t testing.TB, value, min, max T) {
+	t.Helper()
+	Require(t, assert.AssertInRange(value, min, max))
+}
+
+// Contains stops the test if slice does not contain value
+func Contains[T comparable](t testing.TB, slice []T, value T) {
+	t.Helper()
+	Require(t, assert.AssertContains(slice, value))
+}
+
+// MinLen stops the test if slice has fewer than minLength elements
+func MinLen[T any](t testing.TB, slice []T, minLength int) {
+	t.Helper()
+	Require(t, assert.AssertMinLen(slice, minLength))
+}
+
+// ContainsString stops the test if value does not contain substring
+func ContainsString(t testing.TB, value, substring string) {
+	t.Helper()
+	Require(t, assert.AssertContainsString(value, substring))
+}
+
+// Matches stops the test if value does not match pattern
+func Matches(t testing.TB, value, pattern string) {
+	t.Helper()
+	Require(t, assert.AssertMatches(value, pattern))
+}
+
+// ValidEmail stops the test if email is not a valid email
+func ValidEmail(t testing.TB, email string) {
+	t.Helper()
+	Require(t, assert.AssertValidEmail(email))
+}
+
+// ValidURL stops the test if url is not a valid URL
+func ValidURL(t testing.TB, url string) {
+	t.Helper()
+	Require(t, assert.AssertValidURL(url))
+}
+
+// ValidUUID stops the test if uuid is not a valid UUID
+func ValidUUID(t testing.TB, uuid string) {
+	t.Helper()
+	Require(t, assert.AssertValidUUID(uuid))
+}
+
+// ValidJSON stops the test if jsonStr is not valid JSON
+func ValidJSON(t testing.TB, jsonStr string) {
+	t.Helper()
+	Require(t, assert.AssertValidJSON(jsonStr))
+}
+
+// WithinDuration stops the test if actual is not within duration of expected
+func WithinDuration(t testing.TB, actual, expected time.Time, duration time.Duration) {
+	t.Helper()
+	Require(t, assert.AssertWithinDuration(actual, expected, duration))
+}
+
+// Valid stops the test if v fails assert.ValidateStruct
+func Valid(t testing.TB, v any) {
+	t.Helper()
+	Require(t, assert.ValidateStruct(v))
+}
+
+// Invalid stops the test unless v fails assert.ValidateStruct on the given
+// fields, e.g. Invalid(t, req, "Email", "Items[0].Name")
+func Invalid(t testing.TB, v any, fields ...string) {
+	t.Helper()
+	err := assert.ValidateStruct(v)
+	var validationErrs assert.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	for _, field := range fields {
+		found := false
+		for _, fieldErr := range validationErrs {
+			found = found || fieldErr.Field == field
+		}
+		if !found {
+			t.Fatalf("expected a validation error on %s, got %v", field, validationErrs)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package assert
+
+import (
+	"reflect"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Validation Collector
+// The collector gathers the results of several assertions into one
+// ValidationErrors, for values that are not validated with struct tags.
+// --------------------------------------------------
+// **************************************************
+
+// Collector accumulates field errors, e.g.
+//
+//	c := assert.NewCollector()
+//	c.Check("name", assert.CodeRequired, assert.AssertNonEmptyString(name))
+//	c.Rules("sku", sku, "required,sku")
+//	if err := c.Err(); err != nil {
+//		return err
+//	}
+type Collector struct {
+	errs ValidationErrors
+	err  error
+}
+
+// NewCollector creates a new collector
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Check records the result of an assertion under a field and code
+func (c *Collector) Check(field, code string, err error) *Collector {
+	if err != nil {
+		c.errs = append(c.errs, FieldError{Field: field, Code: code, Message: err.Error()})
+	}
+	return c
+}
+
+// Rules validates a value with comma-separated rules in the same syntax as
+// `validate` tags, including registered custom rules. Structs and slices
+// of structs are validated recursively, as with ValidateStruct.
+func (c *Collector) Rules(field string, value any, rules string) *Collector {
+	if c.err != nil {
+		return c
+	}
+	var ruleList []string
+	if rules != "" {
+		ruleList = strings.Split(rules, ",")
+	}
+	// Validate through an interface value so a nil value is handled like a nil field
+	c.err = validateValue(reflect.ValueOf(&value).Elem(), reflect.Value{}, field, ruleList, &c.errs)
+	return c
+}
+
+// Errors returns the collected field errors
+func (c *Collector) Errors() ValidationErrors {
+	return c.errs
+}
+
+// Err returns the first rule configuration error, the collected field
+// errors as ValidationErrors, or nil when every check passed
+func (c *Collector) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	if len(c.errs) > 0 {
+		return c.errs
+	}
+	return nil
+}
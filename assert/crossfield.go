@@ -0,0 +1,182 @@
+package assert
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Cross-Field Rules
+// Cross-field rules compare a struct field with another field of the same
+// struct, e.g. a password confirmation or a date range.
+// --------------------------------------------------
+// **************************************************
+
+// Cross-field validation error codes
+const (
+	CodeRequiredIf = "required_if"
+	CodeEqField    = "eqfield"
+	CodeNeField    = "nefield"
+	CodeGtField    = "gtfield"
+	CodeGteField   = "gtefield"
+	CodeLtField    = "ltfield"
+	CodeLteField   = "ltefield"
+)
+
+// errUnexportedField is reported when a rule refers to an unexported field,
+// whose value cannot be read
+var errUnexportedField = errors.New("field is unexported")
+
+// crossFieldRules are the rules that read another field. Rules marked true
+// are also checked for zero values.
+var crossFieldRules = map[string]bool{
+	CodeRequiredIf: true, CodeEqField: true, CodeNeField: false,
+	CodeGtField: false, CodeGteField: false, CodeLtField: false, CodeLteField: false,
+}
+
+// applyCrossFieldRule checks a rule against another field of parent. It
+// returns the field error, or a configuration error when the other field
+// cannot be found or compared.
+func applyCrossFieldRule(value, parent reflect.Value, name, param string) (error, error) {
+	if !parent.IsValid() {
+		return nil, fmt.Errorf("rule %s can only be used in struct tags", name)
+	}
+
+	otherName, expected, _ := strings.Cut(strings.TrimSpace(param), " ")
+	other, err := fieldByPath(parent, otherName)
+	if errors.Is(err, errUnexportedField) {
+		return fmt.Errorf("cannot compare with %s: %w", otherName, errUnexportedField), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+
+	switch name {
+	case CodeRequiredIf:
+		if !otherEquals(other, strings.Fields(expected)) {
+			return nil, nil
+		}
+		if err := applyRule(value, CodeRequired); err != nil {
+			return fmt.Errorf("value is required when %s is %s", otherName, fmt.Sprint(indirect(other).Interface())), nil
+		}
+		return nil, nil
+	case CodeEqField:
+		if !reflect.DeepEqual(indirectInterface(value), indirectInterface(other)) {
+			return fmt.Errorf("value must equal %s", otherName), nil
+		}
+		return nil, nil
+	case CodeNeField:
+		if reflect.DeepEqual(indirectInterface(value), indirectInterface(other)) {
+			return fmt.Errorf("value must not equal %s", otherName), nil
+		}
+		return nil, nil
+	}
+
+	// Ordering rules are skipped while the other field is empty
+	if indirect(other).IsZero() {
+		return nil, nil
+	}
+	order, err := compareValues(indirect(value), indirect(other))
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+
+	switch {
+	case name == CodeGtField && order <= 0:
+		return fmt.Errorf("value must be greater than %s", otherName), nil
+	case name == CodeGteField && order < 0:
+		return fmt.Errorf("value must be greater than or equal to %s", otherName), nil
+	case name == CodeLtField && order >= 0:
+		return fmt.Errorf("value must be less than %s", otherName), nil
+	case name == CodeLteField && order > 0:
+		return fmt.Errorf("value must be less than or equal to %s", otherName), nil
+	}
+	return nil, nil
+}
+
+// fieldByPath finds a field by name, or a dotted path such as "Period.Start"
+func fieldByPath(parent reflect.Value, path string) (reflect.Value, error) {
+	if path == "" {
+		return reflect.Value{}, errors.New("missing field name")
+	}
+	value := parent
+	var err error
+	for _, name := range strings.Split(path, ".") {
+		value = indirect(value)
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("field %s not found", path)
+		}
+		field, ok := value.Type().FieldByName(name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("field %s not found", path)
+		}
+		if !field.IsExported() {
+			return reflect.Value{}, fmt.Errorf("%w: %s", errUnexportedField, path)
+		}
+		if value, err = value.FieldByIndexErr(field.Index); err != nil {
+			return reflect.Value{}, fmt.Errorf("field %s not found: %w", path, err)
+		}
+	}
+	return value, nil
+}
+
+// otherEquals checks if a field's value matches one of the expected values
+func otherEquals(other reflect.Value, expected []string) bool {
+	other = indirect(other)
+	if other.Kind() == reflect.Pointer || other.Kind() == reflect.Interface {
+		return false // nil
+	}
+	actual := fmt.Sprint(other.Interface())
+	for _, value := range expected {
+		if actual == value {
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues orders two values of the same kind: times, numbers or strings
+func compareValues(a, b reflect.Value) (int, error) {
+	if a.Kind() == reflect.Pointer || b.Kind() == reflect.Pointer {
+		return 0, nil // nil values are not ordered
+	}
+	if timeA, ok := a.Interface().(time.Time); ok {
+		if timeB, ok := b.Interface().(time.Time); ok {
+			return timeA.Compare(timeB), nil
+		}
+	} else if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint()), nil
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float()), nil
+		case reflect.String:
+			return cmp.Compare(a.String(), b.String()), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", a.Type(), b.Type())
+}
+
+// indirect dereferences pointers and interfaces, stopping at nil
+func indirect(value reflect.Value) reflect.Value {
+	for (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value
+}
+
+// indirectInterface returns the dereferenced value, or nil for a nil pointer
+func indirectInterface(value reflect.Value) any {
+	value = indirect(value)
+	if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil() {
+		return nil
+	}
+	return value.Interface()
+}
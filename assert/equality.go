@@ -0,0 +1,166 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Equality and Nil Assertions
+// Assertions that inspect values with reflection, so they see inside
+// structs, collections and interfaces.
+// --------------------------------------------------
+// **************************************************
+
+// maxDiffs bounds the number of differences reported by AssertDeepEqual
+const maxDiffs = 10
+
+// AssertDeepEqual checks if two values are deeply equal, as reflect.DeepEqual.
+// The error lists where the values differ, e.g.
+// `Items[1].Price: expected 10, got 12`.
+func AssertDeepEqual(actual, expected any) error {
+	if reflect.DeepEqual(actual, expected) {
+		return nil
+	}
+
+	var diffs diffList
+	deepDiff("", reflect.ValueOf(actual), reflect.ValueOf(expected), &diffs)
+	if diffs.total == 0 {
+		// Differences DeepEqual sees but the diff does not, such as NaN
+		diffs.add(fmt.Sprintf("expected %#v, got %#v", expected, actual))
+	}
+	lines := diffs.lines
+	if diffs.total > len(lines) {
+		lines = append(lines, fmt.Sprintf("... and %d more", diffs.total-len(lines)))
+	}
+	return fmt.Errorf("values are not equal:\n  %s", strings.Join(lines, "\n  "))
+}
+
+// AssertNotNil checks if a value is not nil, including typed nil pointers,
+// maps, slices, channels and functions stored in an interface
+func AssertNotNil(value any) error {
+	if isNil(value) {
+		return errors.New("value cannot be nil")
+	}
+	return nil
+}
+
+// isNil checks for an untyped nil or a nil value of a nillable kind
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// diffList keeps the first maxDiffs differences and counts all of them
+type diffList struct {
+	lines []string
+	total int
+}
+
+// add records a difference
+func (d *diffList) add(line string) {
+	d.total++
+	if len(d.lines) < maxDiffs {
+		d.lines = append(d.lines, line)
+	}
+}
+
+// deepDiff records the paths where actual and expected differ
+func deepDiff(path string, actual, expected reflect.Value, diffs *diffList) {
+	label := path
+	if label == "" {
+		label = "value"
+	}
+	if !actual.IsValid() || !expected.IsValid() {
+		diffs.add(fmt.Sprintf("%s: expected %s, got %s", label, describe(expected), describe(actual)))
+		return
+	}
+	if actual.Type() != expected.Type() {
+		diffs.add(fmt.Sprintf("%s: expected %s(%s), got %s(%s)",
+			label, expected.Type(), describe(expected), actual.Type(), describe(actual)))
+		return
+	}
+
+	switch actual.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if actual.IsNil() || expected.IsNil() {
+			if actual.IsNil() != expected.IsNil() {
+				diffs.add(fmt.Sprintf("%s: expected %s, got %s", label, describe(expected), describe(actual)))
+			}
+			return
+		}
+		deepDiff(path, actual.Elem(), expected.Elem(), diffs)
+	case reflect.Struct:
+		for i := 0; i < actual.NumField(); i++ {
+			deepDiff(joinPath(path, actual.Type().Field(i).Name), actual.Field(i), expected.Field(i), diffs)
+		}
+	case reflect.Slice, reflect.Array:
+		if actual.Kind() == reflect.Slice && actual.IsNil() != expected.IsNil() {
+			diffs.add(fmt.Sprintf("%s: expected %s, got %s", label, describe(expected), describe(actual)))
+			return
+		}
+		if actual.Len() != expected.Len() {
+			diffs.add(fmt.Sprintf("%s: expected length %d, got %d", label, expected.Len(), actual.Len()))
+		}
+		for i := 0; i < min(actual.Len(), expected.Len()); i++ {
+			deepDiff(fmt.Sprintf("%s[%d]", path, i), actual.Index(i), expected.Index(i), diffs)
+		}
+	case reflect.Map:
+		if actual.IsNil() != expected.IsNil() {
+			diffs.add(fmt.Sprintf("%s: expected %s, got %s", label, describe(expected), describe(actual)))
+			return
+		}
+		for _, key := range expected.MapKeys() {
+			keyPath := fmt.Sprintf("%s[%#v]", path, key.Interface())
+			if value := actual.MapIndex(key); value.IsValid() {
+				deepDiff(keyPath, value, expected.MapIndex(key), diffs)
+			} else {
+				diffs.add(fmt.Sprintf("%s: missing, expected %s", keyPath, describe(expected.MapIndex(key))))
+			}
+		}
+		for _, key := range actual.MapKeys() {
+			if !expected.MapIndex(key).IsValid() {
+				keyPath := fmt.Sprintf("%s[%#v]", path, key.Interface())
+				diffs.add(fmt.Sprintf("%s: unexpected %s", keyPath, describe(actual.MapIndex(key))))
+			}
+		}
+	default:
+		if !actual.CanInterface() {
+			// Unexported fields can only be compared through their formatting
+			if describe(actual) != describe(expected) {
+				diffs.add(fmt.Sprintf("%s: expected %s, got %s", label, describe(expected), describe(actual)))
+			}
+			return
+		}
+		if !reflect.DeepEqual(actual.Interface(), expected.Interface()) {
+			diffs.add(fmt.Sprintf("%s: expected %s, got %s", label, describe(expected), describe(actual)))
+		}
+	}
+}
+
+// describe formats a value for a diff line
+func describe(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	// fmt prints the value held by a reflect.Value, including unexported fields
+	return fmt.Sprintf("%#v", v)
+}
+
+// joinPath appends a field name to a diff path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
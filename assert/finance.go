@@ -0,0 +1,237 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Financial and Document Assertions
+// Assertions for payment cards, bank accounts, and ISO currency and
+// country codes.
+// --------------------------------------------------
+// **************************************************
+
+// CardBrand is a payment card network
+type CardBrand string
+
+// Payment card brands detected by DetectCardBrand
+const (
+	CardBrandUnknown    CardBrand = "unknown"
+	CardBrandVisa       CardBrand = "visa"
+	CardBrandMastercard CardBrand = "mastercard"
+	CardBrandAmex       CardBrand = "amex"
+	CardBrandDiscover   CardBrand = "discover"
+	CardBrandDiners     CardBrand = "diners"
+	CardBrandJCB        CardBrand = "jcb"
+	CardBrandUnionPay   CardBrand = "unionpay"
+	CardBrandMaestro    CardBrand = "maestro"
+)
+
+// cardRange maps a range of leading digits to a brand and its valid lengths
+type cardRange struct {
+	brand   CardBrand
+	low     int
+	high    int
+	lengths []int
+}
+
+// cardRanges are checked in order, so narrower ranges come first
+var cardRanges = []cardRange{
+	{CardBrandAmex, 34, 34, []int{15}},
+	{CardBrandAmex, 37, 37, []int{15}},
+	{CardBrandDiners, 300, 305, []int{14, 16, 17, 18, 19}},
+	{CardBrandDiners, 36, 36, []int{14, 15, 16, 17, 18, 19}},
+	{CardBrandDiners, 38, 39, []int{14, 15, 16, 17, 18, 19}},
+	{CardBrandJCB, 3528, 3589, []int{16, 17, 18, 19}},
+	{CardBrandVisa, 4, 4, []int{13, 16, 19}},
+	{CardBrandMastercard, 51, 55, []int{16}},
+	{CardBrandMastercard, 2221, 2720, []int{16}},
+	{CardBrandDiscover, 6011, 6011, []int{16, 17, 18, 19}},
+	{CardBrandDiscover, 644, 649, []int{16, 17, 18, 19}},
+	{CardBrandDiscover, 65, 65, []int{16, 17, 18, 19}},
+	{CardBrandUnionPay, 62, 62, []int{16, 17, 18, 19}},
+	{CardBrandMaestro, 50, 50, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+	{CardBrandMaestro, 56, 69, []int{12, 13, 14, 15, 16, 17, 18, 19}},
+}
+
+// ibanLengths is the IBAN length for each country in the IBAN registry
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HN": 28, "HR": 21,
+	"HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30,
+	"KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20,
+	"MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23,
+	"PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22,
+	"RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26,
+	"UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// currencyCodes are the active ISO 4217 currency codes, including funds
+// and precious metal codes
+var currencyCodes = toSet(strings.Fields(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND
+	BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU
+	CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP
+	GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES
+	KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD
+	MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR
+	PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD
+	SHP SLE SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD
+	TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF XAG XAU XBA
+	XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT XSU XTS XUA XXX YER ZAR ZMW ZWG
+	ZWL
+`))
+
+// countryCodes are the ISO 3166-1 alpha-2 country codes
+var countryCodes = toSet(strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI
+	BJ BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN
+	CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK
+	FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+	HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN
+	KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK
+	ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP
+	NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF
+	TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI
+	VN VU WF WS YE YT ZA ZM ZW
+`))
+
+// DetectCardBrand returns the brand of a card number from its leading
+// digits. Spaces and dashes are ignored.
+func DetectCardBrand(number string) CardBrand {
+	if r, ok := findCardRange(cardDigits(number)); ok {
+		return r.brand
+	}
+	return CardBrandUnknown
+}
+
+// AssertValidCreditCard checks if a string is a valid payment card number:
+// 12 to 19 digits passing the Luhn checksum, with a length valid for its
+// brand when the brand is recognized. Spaces and dashes are ignored.
+func AssertValidCreditCard(number string) error {
+	digits := cardDigits(number)
+	if len(digits) < 12 || len(digits) > 19 || strings.Trim(digits, "0123456789") != "" {
+		return errors.New("invalid card number format")
+	}
+	if !luhnValid(digits) {
+		return errors.New("invalid card number checksum")
+	}
+	if r, ok := findCardRange(digits); ok && !slices.Contains(r.lengths, len(digits)) {
+		return fmt.Errorf("invalid card number length %d for %s", len(digits), r.brand)
+	}
+	return nil
+}
+
+// AssertValidIBAN checks if a string is a valid IBAN: a known country, the
+// country's length, and the ISO 7064 mod 97 check digits. Spaces are ignored.
+func AssertValidIBAN(iban string) error {
+	normalized := strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+	if len(normalized) < 5 {
+		return fmt.Errorf("invalid IBAN format: %s", iban)
+	}
+
+	length, ok := ibanLengths[normalized[:2]]
+	if !ok {
+		return fmt.Errorf("invalid IBAN country code: %s", normalized[:2])
+	}
+	if len(normalized) != length {
+		return fmt.Errorf("invalid IBAN length %d for %s, expected %d", len(normalized), normalized[:2], length)
+	}
+
+	// Move the country code and check digits to the end and convert letters
+	// to numbers, A=10 through Z=35
+	var numeric strings.Builder
+	for _, r := range normalized[4:] + normalized[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return fmt.Errorf("invalid IBAN format: %s", iban)
+		}
+	}
+
+	value, _ := new(big.Int).SetString(numeric.String(), 10)
+	if new(big.Int).Mod(value, big.NewInt(97)).Int64() != 1 {
+		return fmt.Errorf("invalid IBAN checksum: %s", iban)
+	}
+	return nil
+}
+
+// AssertValidCurrencyCode checks if a string is an active ISO 4217
+// currency code, such as "USD"
+func AssertValidCurrencyCode(code string) error {
+	if !currencyCodes[code] {
+		return fmt.Errorf("invalid ISO 4217 currency code: %s", code)
+	}
+	return nil
+}
+
+// AssertValidCountryCode checks if a string is an ISO 3166-1 alpha-2
+// country code, such as "US"
+func AssertValidCountryCode(code string) error {
+	if !countryCodes[code] {
+		return fmt.Errorf("invalid ISO 3166-1 country code: %s", code)
+	}
+	return nil
+}
+
+// findCardRange finds the brand range matching the leading digits
+func findCardRange(digits string) (cardRange, bool) {
+	for _, r := range cardRanges {
+		width := len(strconv.Itoa(r.low))
+		if len(digits) < width {
+			continue
+		}
+		prefix, err := strconv.Atoi(digits[:width])
+		if err == nil && prefix >= r.low && prefix <= r.high {
+			return r, true
+		}
+	}
+	return cardRange{}, false
+}
+
+// cardDigits removes spaces and dashes from a card number
+func cardDigits(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(number)
+}
+
+// luhnValid checks the Luhn checksum of a string of digits
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// toSet builds a lookup set from a list of values
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,37 @@
+package assert
+
+import (
+	"fmt"
+	"math"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Geographic Assertions
+// Assertions for WGS 84 latitude and longitude in decimal degrees.
+// --------------------------------------------------
+// **************************************************
+
+// AssertValidLatitude checks if a value is a latitude between -90 and 90
+func AssertValidLatitude(lat float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("invalid latitude %v: must be between -90 and 90", lat)
+	}
+	return nil
+}
+
+// AssertValidLongitude checks if a value is a longitude between -180 and 180
+func AssertValidLongitude(lng float64) error {
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return fmt.Errorf("invalid longitude %v: must be between -180 and 180", lng)
+	}
+	return nil
+}
+
+// AssertValidCoordinates checks if a latitude and longitude pair is valid
+func AssertValidCoordinates(lat, lng float64) error {
+	if err := AssertValidLatitude(lat); err != nil {
+		return err
+	}
+	return AssertValidLongitude(lng)
+}
@@ -0,0 +1,87 @@
+package assert
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Must Assertions
+// Must variants panic instead of returning an error, for invariants checked
+// at construction or startup where returning an error is impractical.
+// They return the checked value so they can be used inline.
+// --------------------------------------------------
+// **************************************************
+
+// Must returns v, or panics if err is not nil, e.g.
+// `u := assert.Must(url.Parse(rawURL))`
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(fmt.Errorf("assertion failed: %w", err))
+	}
+	return v
+}
+
+// MustPass panics if an assertion returned an error, e.g.
+// `assert.MustPass(assert.ValidateStruct(cfg))`
+func MustPass(err error) {
+	if err != nil {
+		panic(fmt.Errorf("assertion failed: %w", err))
+	}
+}
+
+// MustNonEmptyString returns value, or panics if it is empty
+func MustNonEmptyString(value string) string {
+	return Must(value, AssertNonEmptyString(value))
+}
+
+// MustNonZero returns value, or panics if it is the zero value of its type
+func MustNonZero[T comparable](value T) T {
+	return Must(value, AssertNonZero(value))
+}
+
+// MustInRange returns value, or panics if it is outside min and max (inclusive)
+func MustInRange[T cmp.Ordered](value, min, max T) T {
+	return Must(value, AssertInRange(value, min, max))
+}
+
+// MustNotNil returns value, or panics if it is nil or a typed nil
+func MustNotNil[T any](value T) T {
+	return Must(value, AssertNotNil(value))
+}
+
+// MustValidEmail returns email, or panics if it is not a valid email
+func MustValidEmail(email string) string {
+	return Must(email, AssertValidEmail(email))
+}
+
+// MustValidURL returns url, or panics if it is not a valid URL
+func MustValidURL(url string) string {
+	return Must(url, AssertValidURL(url))
+}
+
+// MustValidUUID returns uuid, or panics if it is not a valid UUID
+func MustValidUUID(uuid string) string {
+	return Must(uuid, AssertValidUUID(uuid))
+}
+
+// MustValidJSON returns jsonStr, or panics if it is not valid JSON
+func MustValidJSON(jsonStr string) string {
+	return Must(jsonStr, AssertValidJSON(jsonStr))
+}
+
+// MustValidHostname returns hostname, or panics if it is not a valid hostname
+func MustValidHostname(hostname string) string {
+	return Must(hostname, AssertValidHostname(hostname))
+}
+
+// MustValidPort returns port, or panics if it is not a valid port
+func MustValidPort(port int) int {
+	return Must(port, AssertValidPort(port))
+}
+
+// MustValidateStruct returns v, or panics if it fails ValidateStruct
+func MustValidateStruct[T any](v T) T {
+	return Must(v, ValidateStruct(v))
+}
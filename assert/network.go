@@ -0,0 +1,108 @@
+package assert
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Network and Identifier Assertions
+// Assertions for network addresses and identifier formats.
+// --------------------------------------------------
+// **************************************************
+
+var (
+	hostnameLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	semverRegex        = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+// AssertValidIP checks if a string is a valid IPv4 or IPv6 address
+func AssertValidIP(ip string) error {
+	if _, err := netip.ParseAddr(ip); err != nil {
+		return fmt.Errorf("invalid IP address: %s", ip)
+	}
+	return nil
+}
+
+// AssertValidIPv4 checks if a string is a valid IPv4 address
+func AssertValidIPv4(ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || !addr.Is4() {
+		return fmt.Errorf("invalid IPv4 address: %s", ip)
+	}
+	return nil
+}
+
+// AssertValidIPv6 checks if a string is a valid IPv6 address
+func AssertValidIPv6(ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || !addr.Is6() {
+		return fmt.Errorf("invalid IPv6 address: %s", ip)
+	}
+	return nil
+}
+
+// AssertValidCIDR checks if a string is a valid CIDR network, e.g. "10.0.0.0/8"
+func AssertValidCIDR(cidr string) error {
+	if _, err := netip.ParsePrefix(cidr); err != nil {
+		return fmt.Errorf("invalid CIDR notation: %s", cidr)
+	}
+	return nil
+}
+
+// AssertValidPort checks if a number is a valid TCP or UDP port (1-65535)
+func AssertValidPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d must be between 1 and 65535", port)
+	}
+	return nil
+}
+
+// AssertValidPortString checks if a string is a valid TCP or UDP port number
+func AssertValidPortString(port string) error {
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("invalid port: %s", port)
+	}
+	return AssertValidPort(value)
+}
+
+// AssertValidHostname checks if a string is a valid RFC 1123 hostname.
+// A single trailing dot, as in a fully qualified name, is allowed.
+func AssertValidHostname(hostname string) error {
+	name := strings.TrimSuffix(hostname, ".")
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("invalid hostname: %s", hostname)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !hostnameLabelRegex.MatchString(label) {
+			return fmt.Errorf("invalid hostname: %s", hostname)
+		}
+	}
+	return nil
+}
+
+// AssertValidMAC checks if a string is a valid MAC address, such as
+// "00:1a:2b:3c:4d:5e", "00-1A-2B-3C-4D-5E" or "001a.2b3c.4d5e"
+func AssertValidMAC(mac string) error {
+	if _, err := net.ParseMAC(mac); err != nil {
+		return fmt.Errorf("invalid MAC address: %s", mac)
+	}
+	return nil
+}
+
+// AssertValidSemver checks if a string is a valid semantic version
+// (semver 2.0.0), with an optional "v" prefix, e.g. "v1.4.0-rc.1+build.5"
+func AssertValidSemver(version string) error {
+	if !semverRegex.MatchString(version) {
+		return fmt.Errorf("invalid semantic version: %s", version)
+	}
+	return nil
+}
@@ -0,0 +1,466 @@
+package assert
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Struct Validation
+// Struct validation applies the assertions above declaratively, using
+// `validate` struct tags.
+// --------------------------------------------------
+// **************************************************
+
+// ValidateTag is the struct tag read by ValidateStruct
+const ValidateTag = "validate"
+
+// validationRules are the rule names ValidateStruct understands
+var validationRules = map[string]bool{
+	CodeRequired: true, CodeEmail: true, CodeURL: true, CodeUUID: true,
+	CodeJSON: true, CodeMin: true, CodeMax: true, CodeOneOf: true,
+	CodeIP: true, CodeIPv4: true, CodeIPv6: true, CodeCIDR: true,
+	CodePort: true, CodeHostname: true, CodeMAC: true, CodeSemver: true,
+	CodeCreditCard: true, CodeIBAN: true, CodeCurrency: true, CodeCountry: true,
+	CodeLatitude: true, CodeLongitude: true,
+}
+
+// ValidatorFunc is a custom validation rule. It receives the field value,
+// with pointers dereferenced, and the rule parameter, e.g. "GB" for
+// `validate:"iban=GB"`.
+type ValidatorFunc func(value any, param string) error
+
+var (
+	customValidatorsMu sync.RWMutex
+	customValidators   = make(map[string]ValidatorFunc)
+)
+
+// RegisterValidator registers a custom rule for ValidateStruct tags and
+// Collector.Rules, e.g. RegisterValidator("sku", validateSKU). Registering
+// a name again replaces the rule; built-in rules cannot be replaced.
+func RegisterValidator(name string, fn ValidatorFunc) error {
+	if name == "" || name == "dive" || strings.ContainsAny(name, "=, \t") {
+		return fmt.Errorf("invalid validator name %q", name)
+	}
+	if _, crossField := crossFieldRules[name]; validationRules[name] || crossField {
+		return fmt.Errorf("cannot replace built-in validator %q", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("validator %q has no function", name)
+	}
+
+	customValidatorsMu.Lock()
+	defer customValidatorsMu.Unlock()
+	customValidators[name] = fn
+	return nil
+}
+
+// customValidator looks up a registered rule
+func customValidator(name string) (ValidatorFunc, bool) {
+	customValidatorsMu.RLock()
+	defer customValidatorsMu.RUnlock()
+	fn, ok := customValidators[name]
+	return fn, ok
+}
+
+// Validation error codes, one per rule
+const (
+	CodeRequired   = "required"
+	CodeEmail      = "email"
+	CodeURL        = "url"
+	CodeUUID       = "uuid"
+	CodeJSON       = "json"
+	CodeMin        = "min"
+	CodeMax        = "max"
+	CodeOneOf      = "oneof"
+	CodeIP         = "ip"
+	CodeIPv4       = "ipv4"
+	CodeIPv6       = "ipv6"
+	CodeCIDR       = "cidr"
+	CodePort       = "port"
+	CodeHostname   = "hostname"
+	CodeMAC        = "mac"
+	CodeSemver     = "semver"
+	CodeCreditCard = "creditcard"
+	CodeIBAN       = "iban"
+	CodeCurrency   = "currency"
+	CodeCountry    = "country"
+	CodeLatitude   = "latitude"
+	CodeLongitude  = "longitude"
+)
+
+// FieldError is a failed validation rule on a struct field
+type FieldError struct {
+	// Field is the path to the field, e.g. "Address.City" or "Items[2].Name"
+	Field string `json:"field"`
+	// Code identifies the rule that failed, e.g. CodeRequired or CodeMin
+	Code string `json:"code"`
+	// Message describes the failure
+	Message string `json:"message"`
+	// Param is the rule parameter, e.g. "3" for min=3
+	Param string `json:"param,omitempty"`
+}
+
+// Error returns the error message
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every failed rule of a struct
+type ValidationErrors []FieldError
+
+// Error returns the error message
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ProblemDetails is an RFC 7807 problem body, extended with the field errors
+// of a failed validation
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// ProblemContentType is the media type of a JSON problem body
+const ProblemContentType = "application/problem+json"
+
+// Problem returns the validation failures as a 422 problem body
+func (e ValidationErrors) Problem() ProblemDetails {
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  "Validation failed",
+		Status: http.StatusUnprocessableEntity,
+		Detail: fmt.Sprintf("%d field(s) failed validation", len(e)),
+		Errors: e,
+	}
+}
+
+// ProblemJSON serializes the validation failures as a JSON problem body
+func (e ValidationErrors) ProblemJSON() ([]byte, error) {
+	return json.Marshal(e.Problem())
+}
+
+// ValidateStruct validates a struct, or a pointer to one, using the rules in
+// its `validate` tags, e.g.
+//
+//	type CreateUserRequest struct {
+//		Name    string   `validate:"required,min=3,max=50"`
+//		Email   string   `validate:"required,email"`
+//		OrgID   string   `validate:"uuid"`
+//		Tags    []string `validate:"max=10,dive,min=2"`
+//		Address Address  `validate:"required"`
+//	}
+//
+// Built-in rules are required, email, url, uuid, json, ip, ipv4, ipv6,
+// cidr, port, hostname, mac, semver, creditcard, iban, currency, country,
+// latitude, longitude, min=N, max=N and oneof=a b c; more can be added with RegisterValidator.
+// Cross-field rules compare with another field of the same struct:
+// required_if=Field v1 v2, eqfield=Field, nefield=Field, and gtfield,
+// gtefield, ltfield and ltefield, which order numbers, strings and times.
+// min and max compare the length of strings, slices and maps and the value
+// of numbers. Rules other than required are skipped for zero values. Rules
+// after dive apply to each element of a slice or map.
+//
+// Nested structs, pointers to structs and slices of structs are validated
+// recursively. All failures are returned together as ValidationErrors, which
+// can be sent to clients with ValidationErrors.Problem; an unknown rule is
+// reported as a plain error.
+func ValidateStruct(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return errors.New("cannot validate a nil pointer")
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot validate %T: not a struct", v)
+	}
+
+	var errs ValidationErrors
+	if err := validateStruct(value, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateStruct validates the exported fields of a struct value
+func validateStruct(value reflect.Value, path string, errs *ValidationErrors) error {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		var rules []string
+		if tag := field.Tag.Get(ValidateTag); tag != "" && tag != "-" {
+			rules = strings.Split(tag, ",")
+		}
+		if err := validateValue(value.Field(i), value, fieldPath, rules, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValue applies rules to a value, then validates its contents. parent is
+// the struct holding the value, used by cross-field rules; it is invalid
+// outside a struct.
+func validateValue(value, parent reflect.Value, path string, rules []string, errs *ValidationErrors) error {
+	var elementRules []string
+	for i, rule := range rules {
+		if rule == "dive" {
+			rules, elementRules = rules[:i], rules[i+1:]
+			break
+		}
+	}
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		name = strings.TrimSpace(name)
+		checkZero, crossField := crossFieldRules[name]
+		if _, custom := customValidator(name); !validationRules[name] && !crossField && !custom {
+			return fmt.Errorf("unknown validation rule %q on %s", rule, path)
+		}
+		if value.IsZero() && name != CodeRequired && !checkZero {
+			continue
+		}
+
+		var err error
+		if crossField {
+			var configErr error
+			if err, configErr = applyCrossFieldRule(value, parent, name, param); configErr != nil {
+				return fmt.Errorf("%s: %w", path, configErr)
+			}
+		} else {
+			err = applyRule(value, rule)
+		}
+		if err != nil {
+			*errs = append(*errs, FieldError{
+				Field:   path,
+				Code:    name,
+				Message: err.Error(),
+				Param:   param,
+			})
+		}
+	}
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return validateStruct(value, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(value.Index(i), parent, fmt.Sprintf("%s[%d]", path, i), elementRules, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := validateValue(iter.Value(), parent, fmt.Sprintf("%s[%v]", path, iter.Key()), elementRules, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyRule checks a single known rule
+func applyRule(value reflect.Value, rule string) error {
+	name, param, _ := strings.Cut(rule, "=")
+	name = strings.TrimSpace(name)
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			if name == CodeRequired {
+				return errors.New("value is required")
+			}
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch name {
+	case CodeRequired:
+		switch value.Kind() {
+		case reflect.String:
+			return AssertNonEmptyString(value.String())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			if value.Len() == 0 {
+				return fmt.Errorf("%s cannot be empty", value.Kind())
+			}
+		default:
+			if value.IsZero() {
+				return errors.New("value is required")
+			}
+		}
+		return nil
+	case CodeEmail:
+		return withString(value, AssertValidEmail)
+	case CodeURL:
+		return withString(value, AssertValidURL)
+	case CodeUUID:
+		return withString(value, AssertValidUUID)
+	case CodeJSON:
+		return withString(value, AssertValidJSON)
+	case CodeIP:
+		return withString(value, AssertValidIP)
+	case CodeIPv4:
+		return withString(value, AssertValidIPv4)
+	case CodeIPv6:
+		return withString(value, AssertValidIPv6)
+	case CodeCIDR:
+		return withString(value, AssertValidCIDR)
+	case CodeHostname:
+		return withString(value, AssertValidHostname)
+	case CodeMAC:
+		return withString(value, AssertValidMAC)
+	case CodeSemver:
+		return withString(value, AssertValidSemver)
+	case CodeCreditCard:
+		return withString(value, AssertValidCreditCard)
+	case CodeIBAN:
+		return withString(value, AssertValidIBAN)
+	case CodeCurrency:
+		return withString(value, AssertValidCurrencyCode)
+	case CodeCountry:
+		return withString(value, AssertValidCountryCode)
+	case CodeLatitude:
+		return withFloat(value, AssertValidLatitude)
+	case CodeLongitude:
+		return withFloat(value, AssertValidLongitude)
+	case CodePort:
+		switch value.Kind() {
+		case reflect.String:
+			return AssertValidPortString(value.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return AssertInRange(value.Int(), 1, 65535)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return AssertInRange(value.Uint(), 1, 65535)
+		}
+		return fmt.Errorf("%s value cannot be validated as a port", value.Kind())
+	case CodeMin, CodeMax:
+		return applyBound(value, name, param)
+	case CodeOneOf:
+		options := strings.Fields(param)
+		actual := fmt.Sprint(value.Interface())
+		for _, option := range options {
+			if actual == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %v must be one of %s", actual, strings.Join(options, ", "))
+	}
+
+	if fn, ok := customValidator(name); ok {
+		return fn(value.Interface(), param)
+	}
+	return nil
+}
+
+// withString applies a string assertion to a string value
+func withString(value reflect.Value, assertion func(string) error) error {
+	if value.Kind() != reflect.String {
+		return fmt.Errorf("%s value cannot be validated as a string", value.Kind())
+	}
+	return assertion(value.String())
+}
+
+// withFloat applies a float assertion to a numeric value
+func withFloat(value reflect.Value, assertion func(float64) error) error {
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return assertion(value.Float())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return assertion(float64(value.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return assertion(float64(value.Uint()))
+	}
+	return fmt.Errorf("%s value cannot be validated as a number", value.Kind())
+}
+
+// applyBound checks a min or max rule against a length or a number
+func applyBound(value reflect.Value, name, param string) error {
+	switch value.Kind() {
+	case reflect.String:
+		limit, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Errorf("invalid %s length %q", name, param)
+		}
+		if name == CodeMin {
+			return AssertMinLength(value.String(), limit)
+		}
+		return AssertMaxLength(value.String(), limit)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		limit, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Errorf("invalid %s length %q", name, param)
+		}
+		return assertBound(name, value.Len(), limit, "length %d must be at %s %d")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		limit, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q", name, param)
+		}
+		return assertBound(name, value.Int(), limit, "value %v must be at %s %v")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		limit, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q", name, param)
+		}
+		return assertBound(name, value.Uint(), limit, "value %v must be at %s %v")
+	case reflect.Float32, reflect.Float64:
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q", name, param)
+		}
+		if name == CodeMin {
+			return AssertMinValue(value.Float(), limit)
+		}
+		return AssertMaxValue(value.Float(), limit)
+	}
+	return fmt.Errorf("%s value does not support %s", value.Kind(), name)
+}
+
+// assertBound compares a value against a min or max limit in its own type
+func assertBound[T cmp.Ordered](name string, actual, limit T, format string) error {
+	if name == CodeMin && actual < limit {
+		return fmt.Errorf(format, actual, "least", limit)
+	}
+	if name == CodeMax && actual > limit {
+		return fmt.Errorf(format, actual, "most", limit)
+	}
+	return nil
+}
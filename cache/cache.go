@@ -0,0 +1,548 @@
+// Package cache provides a generic in-process cache with per-entry
+// expiration, bounded size with LRU or LFU eviction, and loader
+// deduplication.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// **************************************************
+// Cache
+// Cache holds up to MaxEntries values, expiring each one after its
+// TTL and evicting by recency or frequency of use when full.
+// **************************************************
+
+// Policy selects which entry is evicted when the cache is full
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry, and among those the
+	// least recently used one
+	LFU
+)
+
+// String returns the name of the policy
+func (p Policy) String() string {
+	switch p {
+	case LRU:
+		return "lru"
+	case LFU:
+		return "lfu"
+	default:
+		return fmt.Sprintf("policy(%d)", int(p))
+	}
+}
+
+// EvictionReason describes why an entry left the cache
+type EvictionReason int
+
+const (
+	// EvictedCapacity means the entry was evicted to make room for another
+	EvictedCapacity EvictionReason = iota
+	// EvictedExpired means the entry's TTL elapsed
+	EvictedExpired
+	// EvictedDeleted means the entry was removed by Delete or Clear
+	EvictedDeleted
+)
+
+// String returns the name of the eviction reason
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	case EvictedDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("reason(%d)", int(r))
+	}
+}
+
+// Hooks are optional callbacks for exporting cache metrics. They are called
+// synchronously, outside the cache lock, and must be safe for concurrent use.
+type Hooks[K comparable, V any] struct {
+	OnHit   func(key K)
+	OnMiss  func(key K)
+	OnEvict func(key K, value V, reason EvictionReason)
+	OnLoad  func(key K, duration time.Duration, err error)
+}
+
+// Config configures a Cache
+type Config[K comparable, V any] struct {
+	MaxEntries      int           // maximum number of entries; 0 means unbounded
+	TTL             time.Duration // default time to live; 0 means entries do not expire
+	Policy          Policy        // eviction policy when MaxEntries is reached
+	CleanupInterval time.Duration // interval for removing expired entries in the background; 0 disables it
+	Hooks           Hooks[K, V]
+}
+
+// Stats is a snapshot of the cache counters
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Loads     uint64
+	Evictions uint64
+	Entries   int
+}
+
+// HitRatio returns the fraction of lookups that were hits
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// entry is a cached value and its bookkeeping
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	frequency int
+	element   *list.Element
+}
+
+// eviction is an entry removed under the lock, reported after unlocking
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// Cache is a concurrency-safe in-process cache
+type Cache[K comparable, V any] struct {
+	config Config[K, V]
+
+	mu      sync.Mutex
+	entries map[K]*entry[K, V]
+	recency *list.List         // LRU order, most recent at the front
+	buckets map[int]*list.List // LFU lists by frequency, most recent at the front
+	minFreq int
+
+	flights map[K]*flight[V]
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	loads     atomic.Uint64
+	evictions atomic.Uint64
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a new cache. When CleanupInterval is set, call Close to stop
+// the background cleanup.
+func New[K comparable, V any](config Config[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		config:  config,
+		entries: make(map[K]*entry[K, V]),
+		recency: list.New(),
+		buckets: make(map[int]*list.List),
+		flights: make(map[K]*flight[V]),
+		stop:    make(chan struct{}),
+	}
+
+	if config.CleanupInterval > 0 {
+		go c.cleanup(config.CleanupInterval)
+	}
+	return c
+}
+
+// Get returns the value for key and whether it was found and unexpired
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && c.expired(e, time.Now()) {
+		c.remove(e)
+		c.mu.Unlock()
+		c.report([]eviction[K, V]{{key: e.key, value: e.value, reason: EvictedExpired}})
+		ok = false
+	} else if ok {
+		c.touch(e)
+		value := e.value
+		c.mu.Unlock()
+
+		c.hits.Add(1)
+		if c.config.Hooks.OnHit != nil {
+			c.config.Hooks.OnHit(key)
+		}
+		return value, true
+	} else {
+		c.mu.Unlock()
+	}
+
+	c.misses.Add(1)
+	if c.config.Hooks.OnMiss != nil {
+		c.config.Hooks.OnMiss(key)
+	}
+	var zero V
+	return zero, false
+}
+
+// Peek returns the value for key without updating its recency or frequency
+// or the hit and miss counters
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || c.expired(e, time.Now()) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key with the default TTL
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.config.TTL)
+}
+
+// SetWithTTL stores value for key, expiring it after ttl. A ttl of 0 means
+// the entry does not expire.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		e.value = value
+		e.expiresAt = expiresAt
+		c.touch(e)
+		c.mu.Unlock()
+		return
+	}
+
+	var evicted []eviction[K, V]
+	if c.config.MaxEntries > 0 && len(c.entries) >= c.config.MaxEntries {
+		if victim := c.victim(); victim != nil {
+			c.remove(victim)
+			evicted = append(evicted, eviction[K, V]{key: victim.key, value: victim.value, reason: EvictedCapacity})
+		}
+	}
+
+	e := &entry[K, V]{key: key, value: value, expiresAt: expiresAt}
+	c.entries[key] = e
+	c.insert(e)
+	c.mu.Unlock()
+
+	c.report(evicted)
+}
+
+// Delete removes key and reports whether it was present
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		c.remove(e)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.report([]eviction[K, V]{{key: e.key, value: e.value, reason: EvictedDeleted}})
+	}
+	return ok
+}
+
+// Clear removes all entries
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	evicted := make([]eviction[K, V], 0, len(c.entries))
+	for _, e := range c.entries {
+		evicted = append(evicted, eviction[K, V]{key: e.key, value: e.value, reason: EvictedDeleted})
+	}
+	c.entries = make(map[K]*entry[K, V])
+	c.recency.Init()
+	c.buckets = make(map[int]*list.List)
+	c.minFreq = 0
+	c.mu.Unlock()
+
+	c.report(evicted)
+}
+
+// DeleteExpired removes all expired entries and returns how many were removed
+func (c *Cache[K, V]) DeleteExpired() int {
+	now := time.Now()
+
+	c.mu.Lock()
+	var evicted []eviction[K, V]
+	for _, e := range c.entries {
+		if c.expired(e, now) {
+			c.remove(e)
+			evicted = append(evicted, eviction[K, V]{key: e.key, value: e.value, reason: EvictedExpired})
+		}
+	}
+	c.mu.Unlock()
+
+	c.report(evicted)
+	return len(evicted)
+}
+
+// Len returns the number of entries, including expired entries not yet removed
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Keys returns the keys of all unexpired entries
+func (c *Cache[K, V]) Keys() []K {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, len(c.entries))
+	for key, e := range c.entries {
+		if !c.expired(e, now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Loads:     c.loads.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   c.Len(),
+	}
+}
+
+// Close stops the background cleanup. The cache remains usable.
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() { close(c.stop) })
+}
+
+// cleanup removes expired entries every interval until Close is called
+func (c *Cache[K, V]) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.DeleteExpired()
+		}
+	}
+}
+
+// expired checks if an entry's TTL has elapsed
+func (c *Cache[K, V]) expired(e *entry[K, V], now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// report counts evictions and calls the eviction hook
+func (c *Cache[K, V]) report(evicted []eviction[K, V]) {
+	for _, ev := range evicted {
+		if ev.reason != EvictedDeleted {
+			c.evictions.Add(1)
+		}
+		if c.config.Hooks.OnEvict != nil {
+			c.config.Hooks.OnEvict(ev.key, ev.value, ev.reason)
+		}
+	}
+}
+
+// **************************************************
+// Eviction
+// LRU keeps a single recency list. LFU keeps one recency list per
+// use count so that insertion, access and eviction are all O(1).
+// **************************************************
+
+// insert adds a new entry to the eviction structures
+func (c *Cache[K, V]) insert(e *entry[K, V]) {
+	if c.config.Policy == LFU {
+		e.frequency = 1
+		e.element = c.bucket(1).PushFront(e)
+		c.minFreq = 1
+		return
+	}
+	e.element = c.recency.PushFront(e)
+}
+
+// touch records a use of an entry
+func (c *Cache[K, V]) touch(e *entry[K, V]) {
+	if c.config.Policy != LFU {
+		c.recency.MoveToFront(e.element)
+		return
+	}
+
+	current := c.buckets[e.frequency]
+	current.Remove(e.element)
+	if current.Len() == 0 {
+		delete(c.buckets, e.frequency)
+		if c.minFreq == e.frequency {
+			c.minFreq++
+		}
+	}
+	e.frequency++
+	e.element = c.bucket(e.frequency).PushFront(e)
+}
+
+// remove deletes an entry from the map and the eviction structures
+func (c *Cache[K, V]) remove(e *entry[K, V]) {
+	delete(c.entries, e.key)
+
+	if c.config.Policy != LFU {
+		c.recency.Remove(e.element)
+		return
+	}
+
+	current := c.buckets[e.frequency]
+	current.Remove(e.element)
+	if current.Len() == 0 {
+		delete(c.buckets, e.frequency)
+		if c.minFreq == e.frequency {
+			c.minFreq = c.lowestFrequency()
+		}
+	}
+}
+
+// victim returns the entry to evict under the configured policy
+func (c *Cache[K, V]) victim() *entry[K, V] {
+	var tail *list.Element
+	if c.config.Policy == LFU {
+		if bucket, ok := c.buckets[c.minFreq]; ok {
+			tail = bucket.Back()
+		}
+	} else {
+		tail = c.recency.Back()
+	}
+	if tail == nil {
+		return nil
+	}
+	return tail.Value.(*entry[K, V])
+}
+
+// bucket returns the LFU list for a frequency, creating it if needed
+func (c *Cache[K, V]) bucket(frequency int) *list.List {
+	b, ok := c.buckets[frequency]
+	if !ok {
+		b = list.New()
+		c.buckets[frequency] = b
+	}
+	return b
+}
+
+// lowestFrequency finds the smallest frequency with entries. It is only
+// needed after removing the last entry of the minimum bucket.
+func (c *Cache[K, V]) lowestFrequency() int {
+	lowest := 0
+	for frequency := range c.buckets {
+		if lowest == 0 || frequency < lowest {
+			lowest = frequency
+		}
+	}
+	return lowest
+}
+
+// **************************************************
+// Loading
+// GetOrLoad fills misses with a loader, sharing one call between
+// concurrent callers of the same key.
+// **************************************************
+
+// Loader loads the value for a key on a cache miss
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// flight is an in-progress load shared by concurrent callers
+type flight[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// GetOrLoad returns the cached value for key, or calls load and caches its
+// result with the default TTL. Concurrent calls for the same key wait for a
+// single load. Errors are returned to every waiting caller and not cached,
+// except a cancellation of the loading caller's ctx: waiters whose own ctx
+// is still live load again.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, load Loader[K, V]) (V, error) {
+	return c.GetOrLoadWithTTL(ctx, key, c.config.TTL, load)
+}
+
+// GetOrLoadWithTTL is like GetOrLoad but caches the loaded value for ttl
+func (c *Cache[K, V]) GetOrLoadWithTTL(ctx context.Context, key K, ttl time.Duration, load Loader[K, V]) (V, error) {
+	for {
+		if value, ok := c.Get(key); ok {
+			return value, nil
+		}
+
+		c.mu.Lock()
+		f, inFlight := c.flights[key]
+		if !inFlight {
+			f = &flight[V]{done: make(chan struct{})}
+			c.flights[key] = f
+		}
+		c.mu.Unlock()
+
+		if !inFlight {
+			return c.load(ctx, key, ttl, load, f)
+		}
+
+		select {
+		case <-f.done:
+			// The leader's cancellation is not ours: load again
+			if isContextError(f.err) && ctx.Err() == nil {
+				continue
+			}
+			return f.value, f.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// load calls load for key as the leader of flight f and shares its result
+// with the waiting callers
+func (c *Cache[K, V]) load(ctx context.Context, key K, ttl time.Duration, load Loader[K, V], f *flight[V]) (V, error) {
+	start := time.Now()
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				f.err = fmt.Errorf("cache: loader panicked: %v", r)
+			}
+		}()
+		f.value, f.err = load(ctx, key)
+	}()
+
+	c.loads.Add(1)
+	if c.config.Hooks.OnLoad != nil {
+		c.config.Hooks.OnLoad(key, time.Since(start), f.err)
+	}
+	if f.err == nil {
+		c.SetWithTTL(key, f.value, ttl)
+	}
+
+	c.mu.Lock()
+	delete(c.flights, key)
+	c.mu.Unlock()
+	close(f.done)
+
+	return f.value, f.err
+}
+
+// isContextError checks if err comes from a canceled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
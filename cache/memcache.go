@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// **************************************************
+// Memcached
+// MemcacheStore implements Store and Counter on top of a small
+// client interface that wraps any Memcached library.
+// **************************************************
+
+// memcacheMaxKeyLength is the longest key Memcached accepts
+const memcacheMaxKeyLength = 250
+
+// memcacheMaxRelative is the longest expiration Memcached treats as relative;
+// longer expirations must be sent as Unix timestamps
+const memcacheMaxRelative = 30 * 24 * time.Hour
+
+// MemcacheClient is the subset of a Memcached client used by MemcacheStore.
+// Expirations are in Memcached's format: 0 for none, seconds up to 30 days,
+// or a Unix timestamp.
+type MemcacheClient interface {
+	Get(key string) (value []byte, found bool, err error)
+	Set(key string, value []byte, expiration int32) error
+	Add(key string, value []byte, expiration int32) (stored bool, err error)
+	Delete(key string) error
+	Increment(key string, delta uint64) (value uint64, found bool, err error)
+}
+
+// MemcacheStore is a Store and Counter backed by Memcached
+type MemcacheStore struct {
+	client MemcacheClient
+	prefix string
+}
+
+// NewMemcacheStore creates a new Memcached store. The prefix is prepended to
+// every key.
+func NewMemcacheStore(client MemcacheClient, prefix string) *MemcacheStore {
+	return &MemcacheStore{client: client, prefix: prefix}
+}
+
+// Get returns the value of key, or ErrNotFound
+func (s *MemcacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, found, err := s.client.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("cache: memcache get failed: %w", err)
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// Set stores value for key
+func (s *MemcacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Set(key, value, memcacheExpiration(ttl)); err != nil {
+		return fmt.Errorf("cache: memcache set failed: %w", err)
+	}
+	return nil
+}
+
+// SetNX stores value for key if the key does not exist
+func (s *MemcacheStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	stored, err := s.client.Add(key, value, memcacheExpiration(ttl))
+	if err != nil {
+		return false, fmt.Errorf("cache: memcache add failed: %w", err)
+	}
+	return stored, nil
+}
+
+// Delete removes key
+func (s *MemcacheStore) Delete(ctx context.Context, key string) error {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Delete(key); err != nil {
+		return fmt.Errorf("cache: memcache delete failed: %w", err)
+	}
+	return nil
+}
+
+// Increment adds delta to the integer value of key. Memcached counters are
+// unsigned, so delta must not be negative.
+func (s *MemcacheStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if delta < 0 {
+		return 0, fmt.Errorf("cache: memcache counters cannot be decremented")
+	}
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	// Increment does not create keys, so a miss is followed by an Add; if
+	// another instance wins the Add, increment its value instead
+	for attempt := 0; attempt < 2; attempt++ {
+		value, found, err := s.client.Increment(key, uint64(delta))
+		if err != nil {
+			return 0, fmt.Errorf("cache: memcache incr failed: %w", err)
+		}
+		if found {
+			return int64(value), nil
+		}
+
+		stored, err := s.client.Add(key, []byte(strconv.FormatInt(delta, 10)), memcacheExpiration(ttl))
+		if err != nil {
+			return 0, fmt.Errorf("cache: memcache add failed: %w", err)
+		}
+		if stored {
+			return delta, nil
+		}
+	}
+	return 0, fmt.Errorf("cache: memcache incr of %q lost a race twice", key)
+}
+
+// key applies the prefix and checks the key against Memcached's limits
+func (s *MemcacheStore) key(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	key = s.prefix + key
+	if key == "" || len(key) > memcacheMaxKeyLength {
+		return "", fmt.Errorf("%w: memcache keys must be 1 to %d bytes", ErrInvalidKey, memcacheMaxKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return "", fmt.Errorf("%w: memcache keys cannot contain spaces or control characters", ErrInvalidKey)
+		}
+	}
+	return key, nil
+}
+
+// memcacheExpiration converts a TTL to Memcached's expiration format
+func memcacheExpiration(ttl time.Duration) int32 {
+	if ttl <= 0 {
+		return 0
+	}
+	if ttl > memcacheMaxRelative {
+		return int32(time.Now().Add(ttl).Unix())
+	}
+	return int32((ttl + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// **************************************************
+// Redis
+// RedisStore implements Store and Counter on top of any Redis
+// client through a single generic command function, so this
+// package does not depend on a particular client library.
+// **************************************************
+
+// RedisDoer runs a Redis command. A missing key must be reported as a nil
+// reply with a nil error; string replies may be returned as string or
+// []byte and integer replies as int64.
+type RedisDoer interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// RedisDoFunc adapts a function to RedisDoer. With go-redis:
+//
+//	cache.RedisDoFunc(func(ctx context.Context, args ...any) (any, error) {
+//		reply, err := rdb.Do(ctx, args...).Result()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, nil
+//		}
+//		return reply, err
+//	})
+type RedisDoFunc func(ctx context.Context, args ...any) (any, error)
+
+// Do calls f
+func (f RedisDoFunc) Do(ctx context.Context, args ...any) (any, error) {
+	return f(ctx, args...)
+}
+
+// redisIncrementScript increments a key and sets its expiry only when the
+// increment created it
+const redisIncrementScript = `local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if v == tonumber(ARGV[1]) and tonumber(ARGV[2]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return v`
+
+// RedisStore is a Store and Counter backed by Redis
+type RedisStore struct {
+	client RedisDoer
+	prefix string
+}
+
+// NewRedisStore creates a new Redis store. The prefix is prepended to every
+// key, e.g. "myapp:sessions:".
+func NewRedisStore(client RedisDoer, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Get returns the value of key, or ErrNotFound
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := s.client.Do(ctx, "GET", s.prefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("cache: redis GET failed: %w", err)
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+
+	switch v := reply.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("cache: unexpected redis reply %T", reply)
+	}
+}
+
+// Set stores value for key
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := append([]any{"SET", s.prefix + key, value}, redisExpiry(ttl)...)
+	if _, err := s.client.Do(ctx, args...); err != nil {
+		return fmt.Errorf("cache: redis SET failed: %w", err)
+	}
+	return nil
+}
+
+// SetNX stores value for key if the key does not exist
+func (s *RedisStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	args := append([]any{"SET", s.prefix + key, value, "NX"}, redisExpiry(ttl)...)
+	reply, err := s.client.Do(ctx, args...)
+	if err != nil {
+		return false, fmt.Errorf("cache: redis SET NX failed: %w", err)
+	}
+	return reply != nil, nil
+}
+
+// Delete removes key
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.client.Do(ctx, "DEL", s.prefix+key); err != nil {
+		return fmt.Errorf("cache: redis DEL failed: %w", err)
+	}
+	return nil
+}
+
+// Increment adds delta to the integer value of key
+func (s *RedisStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	reply, err := s.client.Do(ctx, "EVAL", redisIncrementScript, 1, s.prefix+key, delta, ttl.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("cache: redis INCRBY failed: %w", err)
+	}
+
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("cache: unexpected redis reply %T", reply)
+	}
+}
+
+// redisExpiry returns the SET arguments for a TTL
+func redisExpiry(ttl time.Duration) []any {
+	if ttl <= 0 {
+		return nil
+	}
+	ms := ttl.Milliseconds()
+	if ms == 0 {
+		ms = 1
+	}
+	return []any{"PX", ms}
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Store
+// A Store is a byte-oriented key/value backend that several
+// instances of a service can share, such as Redis or Memcached.
+// MemoryStore implements it in process for single instances and
+// tests.
+// **************************************************
+
+var (
+	// ErrNotFound is returned by Store.Get when the key does not exist or has expired
+	ErrNotFound = errors.New("cache: key not found")
+	// ErrNotInteger is returned by Increment when the stored value is not an integer
+	ErrNotInteger = errors.New("cache: value is not an integer")
+	// ErrInvalidKey is returned when a key cannot be stored by the backend
+	ErrInvalidKey = errors.New("cache: invalid key")
+)
+
+// Store is a shared key/value store with per-key expiration. A ttl of 0
+// means the key does not expire.
+type Store interface {
+	// Get returns the value of key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value for key, replacing any existing value and TTL
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// SetNX stores value for key only if the key does not exist, and reports
+	// whether it was stored. It is atomic, so it can be used for locks and
+	// idempotency keys.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Counter is implemented by stores that can atomically increment integer
+// values, as needed by shared rate limiters
+type Counter interface {
+	// Increment adds delta to the integer value of key and returns the new
+	// value. A missing key starts at 0 and is given ttl; the TTL of an
+	// existing key is left unchanged, so a counter covers a fixed window.
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// storeItem is a value held by MemoryStore
+type storeItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore is an in-process Store and Counter backed by a Cache
+type MemoryStore struct {
+	mu    sync.Mutex // serializes writes so SetNX and Increment are atomic
+	cache *Cache[string, storeItem]
+}
+
+// NewMemoryStore creates a new in-process store holding up to maxEntries
+// keys with LRU eviction; 0 means unbounded. Call Close to stop its
+// background cleanup.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		cache: New(Config[string, storeItem]{
+			MaxEntries:      maxEntries,
+			Policy:          LRU,
+			CleanupInterval: time.Minute,
+		}),
+	}
+}
+
+// Get returns the value of key, or ErrNotFound
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	item, ok := s.cache.Get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneBytes(item.value), nil
+}
+
+// Set stores value for key
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, cloneBytes(value), ttl)
+	return nil
+}
+
+// SetNX stores value for key if the key does not exist
+func (s *MemoryStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.cache.Peek(key); ok {
+		return false, nil
+	}
+	s.set(key, cloneBytes(value), ttl)
+	return true, nil
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache.Delete(key)
+	return nil
+}
+
+// Increment adds delta to the integer value of key
+func (s *MemoryStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.cache.Peek(key)
+	if !ok {
+		s.set(key, []byte(strconv.FormatInt(delta, 10)), ttl)
+		return delta, nil
+	}
+
+	current, err := strconv.ParseInt(string(item.value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrNotInteger, key)
+	}
+	current += delta
+
+	remaining := time.Duration(0)
+	if !item.expiresAt.IsZero() {
+		remaining = time.Until(item.expiresAt)
+		if remaining <= 0 {
+			remaining = time.Nanosecond
+		}
+	}
+	s.set(key, []byte(strconv.FormatInt(current, 10)), remaining)
+	return current, nil
+}
+
+// Close stops the background cleanup
+func (s *MemoryStore) Close() {
+	s.cache.Close()
+}
+
+// set stores an item; the caller holds s.mu
+func (s *MemoryStore) set(key string, value []byte, ttl time.Duration) {
+	item := storeItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	s.cache.SetWithTTL(key, item, ttl)
+}
+
+// cloneBytes copies b so callers cannot modify stored values
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return append([]byte(nil), b...)
+}
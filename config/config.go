@@ -0,0 +1,439 @@
+// Package config populates configuration structs from defaults, YAML and
+// JSON files, .env files and environment variables, then validates them
+// with the assert package.
+//
+//	type Config struct {
+//		Host     string        `env:"HOST" default:"localhost" yaml:"host"`
+//		Port     int           `env:"PORT" default:"8080" validate:"port" yaml:"port"`
+//		Timeout  time.Duration `env:"TIMEOUT" default:"30s" yaml:"timeout"`
+//		Origins  []string      `env:"ALLOWED_ORIGINS" yaml:"origins"`
+//		APIKey   string        `env:"API_KEY" required:"true"`
+//		Database DBConfig      `env:"DB" yaml:"database"`
+//	}
+//
+//	var cfg Config
+//	err := config.NewLoader().WithPrefix("APP").WithFile("config.yaml").WithEnvFile(".env").Load(&cfg)
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arbenlabs/stoner/assert"
+
+	"gopkg.in/yaml.v3"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Loader
+// --------------------------------------------------
+// **************************************************
+
+// Struct tags read by the loader
+const (
+	// EnvTag names the environment variable of a field. On a struct field
+	// it is a prefix for the nested fields, e.g. `env:"DB"` gives DB_HOST.
+	EnvTag = "env"
+	// DefaultTag is the value used when no source sets the field
+	DefaultTag = "default"
+	// RequiredTag marks a field that must be set by some source
+	RequiredTag = "required"
+	// SeparatorTag sets the separator for slice and map values, "," by default
+	SeparatorTag = "separator"
+)
+
+// ErrMissingRequired is returned when required fields are not set
+var ErrMissingRequired = errors.New("missing required configuration")
+
+// Loader loads configuration into a struct. Sources are applied in order of
+// increasing precedence: `default` tags, files, .env files, then the
+// environment.
+type Loader struct {
+	prefix   string
+	files    []string
+	envFiles []string
+	lookup   func(string) (string, bool)
+}
+
+// NewLoader creates a new loader reading the process environment
+func NewLoader() *Loader {
+	return &Loader{lookup: os.LookupEnv}
+}
+
+// WithPrefix prefixes every environment variable name, e.g. "APP" reads
+// APP_PORT for `env:"PORT"`
+func (l *Loader) WithPrefix(prefix string) *Loader {
+	l.prefix = strings.TrimSuffix(prefix, "_")
+	return l
+}
+
+// WithFile adds a YAML (.yaml, .yml) or JSON (.json) file. Files are
+// applied in the order they are added, so later files override earlier ones.
+func (l *Loader) WithFile(path string) *Loader {
+	l.files = append(l.files, path)
+	return l
+}
+
+// WithEnvFile adds a .env file. Variables set in the environment take
+// precedence over .env files, and earlier .env files over later ones.
+// Missing .env files are ignored.
+func (l *Loader) WithEnvFile(path string) *Loader {
+	l.envFiles = append(l.envFiles, path)
+	return l
+}
+
+// WithLookup replaces the environment lookup, e.g. for tests or secrets
+func (l *Loader) WithLookup(lookup func(string) (string, bool)) *Loader {
+	l.lookup = lookup
+	return l
+}
+
+// Load populates dst, which must be a pointer to a struct, then validates
+// it with assert.ValidateStruct
+func (l *Loader) Load(dst any) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: cannot load into %T: not a pointer to a struct", dst)
+	}
+
+	if err := applyDefaults(target.Elem(), ""); err != nil {
+		return err
+	}
+
+	for _, path := range l.files {
+		if err := loadFile(path, dst); err != nil {
+			return err
+		}
+	}
+
+	envFile := make(map[string]string)
+	for i := len(l.envFiles) - 1; i >= 0; i-- {
+		values, err := ReadEnvFile(l.envFiles[i])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for key, value := range values {
+			envFile[key] = value
+		}
+	}
+	lookup := func(name string) (string, bool) {
+		if value, ok := l.lookup(name); ok {
+			return value, true
+		}
+		value, ok := envFile[name]
+		return value, ok
+	}
+
+	if err := applyEnv(target.Elem(), l.prefix, "", lookup); err != nil {
+		return err
+	}
+
+	var missing []string
+	checkRequired(target.Elem(), l.prefix, "", &missing)
+	if len(missing) > 0 {
+		return fmt.Errorf("config: %w: %s", ErrMissingRequired, strings.Join(missing, ", "))
+	}
+
+	if err := assert.ValidateStruct(dst); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	return nil
+}
+
+// Load populates dst from the environment and `default` tags
+func Load(dst any) error {
+	return NewLoader().Load(dst)
+}
+
+// **************************************************
+// --------------------------------------------------
+// Sources
+// --------------------------------------------------
+// **************************************************
+
+// loadFile decodes a YAML or JSON file into dst
+func loadFile(path string, dst any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, dst)
+	case ".json":
+		err = json.Unmarshal(data, dst)
+	default:
+		return fmt.Errorf("config: unsupported file type %q", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config: failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyDefaults sets zero fields from their `default` tags
+func applyDefaults(value reflect.Value, path string) error {
+	return walkFields(value, path, func(field reflect.Value, info reflect.StructField, fieldPath string) error {
+		def, ok := info.Tag.Lookup(DefaultTag)
+		if !ok || !field.IsZero() {
+			return nil
+		}
+		if err := setValue(field, def, separator(info)); err != nil {
+			return fmt.Errorf("config: invalid default for %s: %w", fieldPath, err)
+		}
+		return nil
+	})
+}
+
+// applyEnv sets fields with an `env` tag from the environment
+func applyEnv(value reflect.Value, prefix, path string, lookup func(string) (string, bool)) error {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		info := structType.Field(i)
+		if !info.IsExported() {
+			continue
+		}
+		field := value.Field(i)
+		name := envName(prefix, info)
+		fieldPath := joinPath(path, info.Name)
+
+		if isNested(field) {
+			nested := nestedStruct(field)
+			if err := applyEnv(nested, name, fieldPath, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, ok := info.Tag.Lookup(EnvTag); !ok {
+			continue
+		}
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(field, raw, separator(info)); err != nil {
+			return fmt.Errorf("config: invalid value for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkRequired records fields tagged `required:"true"` that are still zero
+func checkRequired(value reflect.Value, prefix, path string, missing *[]string) {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		info := structType.Field(i)
+		if !info.IsExported() {
+			continue
+		}
+		field := value.Field(i)
+		name := envName(prefix, info)
+		fieldPath := joinPath(path, info.Name)
+
+		if required, _ := strconv.ParseBool(info.Tag.Get(RequiredTag)); required && field.IsZero() {
+			if _, ok := info.Tag.Lookup(EnvTag); ok {
+				*missing = append(*missing, name)
+			} else {
+				*missing = append(*missing, fieldPath)
+			}
+			continue
+		}
+		if isNested(field) && !(field.Kind() == reflect.Pointer && field.IsNil()) {
+			checkRequired(nestedStruct(field), name, fieldPath, missing)
+		}
+	}
+}
+
+// walkFields calls fn for every non-struct field, recursing into nested structs
+func walkFields(value reflect.Value, path string, fn func(reflect.Value, reflect.StructField, string) error) error {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		info := structType.Field(i)
+		if !info.IsExported() {
+			continue
+		}
+		field := value.Field(i)
+		fieldPath := joinPath(path, info.Name)
+		if isNested(field) {
+			if err := walkFields(nestedStruct(field), fieldPath, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field, info, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// **************************************************
+// --------------------------------------------------
+// Conversion
+// --------------------------------------------------
+// **************************************************
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setValue converts raw into the field's type: strings, bools, numbers,
+// durations, slices and maps of those, and encoding.TextUnmarshaler
+func setValue(field reflect.Value, raw, sep string) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setValue(field.Elem(), raw, sep)
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Slice:
+		parts := splitList(raw, sep)
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), part, sep); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(field.Type())
+		for _, pair := range splitList(raw, sep) {
+			k, v, ok := strings.Cut(pair, ":")
+			if !ok {
+				return fmt.Errorf("invalid map entry %q, expected key:value", pair)
+			}
+			key := reflect.New(field.Type().Key()).Elem()
+			if err := setValue(key, strings.TrimSpace(k), sep); err != nil {
+				return err
+			}
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setValue(elem, strings.TrimSpace(v), sep); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		field.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// splitList splits a list value, trimming items and dropping empty ones
+func splitList(raw, sep string) []string {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(raw, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// separator returns the list separator of a field
+func separator(info reflect.StructField) string {
+	if sep := info.Tag.Get(SeparatorTag); sep != "" {
+		return sep
+	}
+	return ","
+}
+
+// envName returns the environment variable name of a field
+func envName(prefix string, info reflect.StructField) string {
+	name := info.Tag.Get(EnvTag)
+	if name == "" {
+		return prefix
+	}
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// isNested checks if a field is a struct to recurse into, rather than a
+// value such as time.Time that is set as a whole
+func isNested(field reflect.Value) bool {
+	fieldType := field.Type()
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Struct {
+		return false
+	}
+	return !reflect.PointerTo(fieldType).Implements(textUnmarshalerType)
+}
+
+// nestedStruct returns the struct value of a nested field, allocating nil pointers
+func nestedStruct(field reflect.Value) reflect.Value {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return field.Elem()
+	}
+	return field
+}
+
+// joinPath appends a field name to a field path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
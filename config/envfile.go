@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// .env Files
+// --------------------------------------------------
+// **************************************************
+
+// ReadEnvFile parses a .env file of KEY=VALUE lines. Blank lines, comments
+// starting with # and an optional "export " prefix are allowed. Values may
+// be single quoted (literal) or double quoted (with \n, \t, \" and \\
+// escapes); unquoted values end at " #".
+func ReadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("config: %s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("config: %s:%d: %w", path, lineNumber, err)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("config: failed to read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// parseEnvValue unquotes a .env value
+func parseEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch quote := raw[0]; quote {
+	case '\'', '"':
+		end := strings.LastIndexByte(raw, quote)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected text after quoted value")
+		}
+		value := raw[1:end]
+		if quote == '"' {
+			value = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(value)
+		}
+		return value, nil
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw), nil
+}
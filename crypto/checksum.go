@@ -0,0 +1,162 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Checksums and stream hashing
+
+// HashAlgorithm identifies a digest algorithm supported by the checksum helpers
+type HashAlgorithm string
+
+const (
+	AlgorithmSHA256     HashAlgorithm = "sha256"
+	AlgorithmSHA512     HashAlgorithm = "sha512"
+	AlgorithmBLAKE2b256 HashAlgorithm = "blake2b-256"
+	AlgorithmBLAKE2b512 HashAlgorithm = "blake2b-512"
+)
+
+// newBLAKE2b256 returns an unkeyed BLAKE2b-256 hash
+func newBLAKE2b256() hash.Hash {
+	// New256 only fails for keys longer than 64 bytes
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
+// newBLAKE2b512 returns an unkeyed BLAKE2b-512 hash
+func newBLAKE2b512() hash.Hash {
+	// New512 only fails for keys longer than 64 bytes
+	h, _ := blake2b.New512(nil)
+	return h
+}
+
+// hashConstructor returns the hash constructor for an algorithm
+func hashConstructor(algorithm HashAlgorithm) (func() hash.Hash, error) {
+	switch algorithm {
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	case AlgorithmBLAKE2b256:
+		return newBLAKE2b256, nil
+	case AlgorithmBLAKE2b512:
+		return newBLAKE2b512, nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+}
+
+// HashReader hashes everything read from r and returns the hex-encoded digest
+func HashReader(h func() hash.Hash, r io.Reader) (string, error) {
+	hasher := h()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return "", fmt.Errorf("failed to hash reader: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// HashFile hashes the contents of a file with the given algorithm
+func HashFile(algorithm HashAlgorithm, path string) (string, error) {
+	h, err := hashConstructor(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return HashReader(h, file)
+}
+
+// FileChecksumSHA256 returns the hex-encoded SHA256 checksum of a file
+func FileChecksumSHA256(path string) (string, error) {
+	return HashFile(AlgorithmSHA256, path)
+}
+
+// FileChecksumSHA512 returns the hex-encoded SHA512 checksum of a file
+func FileChecksumSHA512(path string) (string, error) {
+	return HashFile(AlgorithmSHA512, path)
+}
+
+// FileChecksumBLAKE2b returns the hex-encoded BLAKE2b-256 checksum of a file
+func FileChecksumBLAKE2b(path string) (string, error) {
+	return HashFile(AlgorithmBLAKE2b256, path)
+}
+
+// VerifyFileChecksum checks a file against an expected hex-encoded checksum
+func VerifyFileChecksum(algorithm HashAlgorithm, path, expected string) (bool, error) {
+	actual, err := HashFile(algorithm, path)
+	if err != nil {
+		return false, err
+	}
+	return SecureCompare(actual, expected), nil
+}
+
+// MultiHash computes several digests in a single pass over the data.
+// It implements io.Writer, so it can be used with io.Copy or io.TeeReader.
+type MultiHash struct {
+	algorithms []HashAlgorithm
+	hashes     []hash.Hash
+	writer     io.Writer
+}
+
+// NewMultiHash creates a new multi-hash for the given algorithms
+func NewMultiHash(algorithms ...HashAlgorithm) (*MultiHash, error) {
+	if len(algorithms) == 0 {
+		return nil, fmt.Errorf("at least one hash algorithm is required")
+	}
+
+	hashes := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, algorithm := range algorithms {
+		h, err := hashConstructor(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = h()
+		writers[i] = hashes[i]
+	}
+
+	return &MultiHash{
+		algorithms: algorithms,
+		hashes:     hashes,
+		writer:     io.MultiWriter(writers...),
+	}, nil
+}
+
+// Write writes data to every underlying hash
+func (m *MultiHash) Write(p []byte) (int, error) {
+	return m.writer.Write(p)
+}
+
+// ReadFrom hashes everything read from r
+func (m *MultiHash) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(m.writer, r)
+}
+
+// Sums returns the hex-encoded digests keyed by algorithm
+func (m *MultiHash) Sums() map[HashAlgorithm]string {
+	sums := make(map[HashAlgorithm]string, len(m.hashes))
+	for i, h := range m.hashes {
+		sums[m.algorithms[i]] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums
+}
+
+// Reset resets every underlying hash
+func (m *MultiHash) Reset() {
+	for _, h := range m.hashes {
+		h.Reset()
+	}
+}
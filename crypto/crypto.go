@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/crypto/scrypt"
@@ -168,14 +169,87 @@ func GenerateRandomBytes(length int) ([]byte, error) {
 // GenerateRandomString generates a random string of specified length
 func GenerateRandomString(length int) (string, error) {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	bytes, err := GenerateRandomBytes(length)
+	return randomFromCharset(charset, length)
+}
+
+// RandomInt returns a uniformly distributed random integer in [min, max]
+func RandomInt(min, max int) (int, error) {
+	if max < min {
+		return 0, fmt.Errorf("invalid range: max %d is less than min %d", max, min)
+	}
+
+	span := new(big.Int).Sub(big.NewInt(int64(max)), big.NewInt(int64(min)))
+	span.Add(span, big.NewInt(1))
+
+	n, err := rand.Int(rand.Reader, span)
 	if err != nil {
-		return "", err
+		return 0, fmt.Errorf("failed to generate random int: %w", err)
+	}
+
+	return int(n.Int64()) + min, nil
+}
+
+// RandomChoice returns a uniformly chosen element of items
+func RandomChoice[T any](items []T) (T, error) {
+	var zero T
+	if len(items) == 0 {
+		return zero, errors.New("cannot choose from an empty slice")
+	}
+
+	i, err := RandomInt(0, len(items)-1)
+	if err != nil {
+		return zero, err
+	}
+
+	return items[i], nil
+}
+
+// Shuffle shuffles items in place using the Fisher-Yates algorithm
+func Shuffle[T any](items []T) error {
+	for i := len(items) - 1; i > 0; i-- {
+		j, err := RandomInt(0, i)
+		if err != nil {
+			return err
+		}
+		items[i], items[j] = items[j], items[i]
+	}
+	return nil
+}
+
+// RandomDigits generates a string of n random decimal digits, e.g. for OTP codes
+func RandomDigits(n int) (string, error) {
+	return randomFromCharset("0123456789", n)
+}
+
+// randomFromCharset builds a random string from charset using rejection sampling
+// so that every character is equally likely regardless of the charset length
+func randomFromCharset(charset string, length int) (string, error) {
+	if length < 0 {
+		return "", fmt.Errorf("invalid length: %d", length)
+	}
+	if len(charset) == 0 || len(charset) > 256 {
+		return "", fmt.Errorf("invalid charset length: %d", len(charset))
 	}
 
-	result := make([]byte, length)
-	for i := range result {
-		result[i] = charset[bytes[i]%byte(len(charset))]
+	// Largest multiple of len(charset) that fits in a byte; values at or above
+	// it are discarded to avoid modulo bias
+	limit := 256 - (256 % len(charset))
+
+	result := make([]byte, 0, length)
+	buf := make([]byte, length+length/4+1)
+	for len(result) < length {
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return "", fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			result = append(result, charset[int(b)%len(charset)])
+			if len(result) == length {
+				break
+			}
+		}
 	}
 
 	return string(result), nil
@@ -0,0 +1,165 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Deterministic (convergent) encryption
+//
+// Deterministic encryption always produces the same ciphertext for the same key,
+// plaintext and associated data. This makes equality lookups on encrypted columns
+// possible, e.g. finding a user by encrypted email:
+//
+//	token, _ := crypto.EncryptDeterministicString(key, email, []byte("users.email"))
+//	user, _ := gq.GetRecordByField[User](db, "email_encrypted", token)
+//
+// Tradeoffs:
+//   - Equal plaintexts are visible as equal ciphertexts, so an attacker with read
+//     access to the column learns value frequencies and which rows share a value.
+//   - Low-entropy fields (booleans, enums, small numeric ranges) can be recovered
+//     by frequency analysis or by encrypting guesses; only use this mode for
+//     high-entropy identifiers such as emails or external account numbers.
+//   - Range queries, prefix matching and sorting remain impossible.
+//   - Use a distinct associated data value per column so identical values in
+//     different columns do not produce linkable ciphertexts.
+//
+// Prefer EncryptAES for everything that does not need to be searched.
+//
+// The construction is SIV-like: the GCM nonce is the truncated HMAC-SHA256 of the
+// associated data and plaintext under a derived key, so the nonce only repeats
+// when the input repeats. Subkeys for the nonce and the cipher are derived from
+// the caller's key with HMAC-SHA256.
+
+// deterministicNonceLabel and deterministicCipherLabel separate the derived subkeys
+const (
+	deterministicNonceLabel  = "stoner/crypto/deterministic/nonce"
+	deterministicCipherLabel = "stoner/crypto/deterministic/cipher"
+)
+
+// deriveDeterministicKeys derives the nonce and cipher subkeys from a 32 byte key
+func deriveDeterministicKeys(key []byte) ([]byte, []byte, error) {
+	if len(key) != 32 {
+		return nil, nil, fmt.Errorf("invalid key length %d: deterministic encryption requires 32 bytes", len(key))
+	}
+
+	nonceMAC := hmac.New(sha256.New, key)
+	nonceMAC.Write([]byte(deterministicNonceLabel))
+
+	cipherMAC := hmac.New(sha256.New, key)
+	cipherMAC.Write([]byte(deterministicCipherLabel))
+
+	return nonceMAC.Sum(nil), cipherMAC.Sum(nil), nil
+}
+
+// deterministicNonce computes the synthetic nonce for plaintext and associated data
+func deterministicNonce(nonceKey, plaintext, associatedData []byte, size int) []byte {
+	h := hmac.New(sha256.New, nonceKey)
+
+	// Length-prefix the associated data so (ad, pt) pairs cannot collide
+	var adLen [8]byte
+	for i := range adLen {
+		adLen[i] = byte(uint64(len(associatedData)) >> (56 - 8*i))
+	}
+	h.Write(adLen[:])
+	h.Write(associatedData)
+	h.Write(plaintext)
+
+	return h.Sum(nil)[:size]
+}
+
+// newDeterministicGCM creates the AES-GCM cipher for the derived cipher key
+func newDeterministicGCM(cipherKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// EncryptDeterministic encrypts data so that the same key, data and associated
+// data always yield the same ciphertext. The key must be 32 bytes. See the
+// notes at the top of this file for the security tradeoffs of this mode.
+func EncryptDeterministic(key, data, associatedData []byte) ([]byte, error) {
+	nonceKey, cipherKey, err := deriveDeterministicKeys(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newDeterministicGCM(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := deterministicNonce(nonceKey, data, associatedData, gcm.NonceSize())
+	return gcm.Seal(nonce, nonce, data, associatedData), nil
+}
+
+// DecryptDeterministic decrypts data produced by EncryptDeterministic
+func DecryptDeterministic(key, data, associatedData []byte) ([]byte, error) {
+	nonceKey, cipherKey, err := deriveDeterministicKeys(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newDeterministicGCM(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce := data[:gcm.NonceSize()]
+	ciphertext := data[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	// The nonce must be the one derived from the plaintext, otherwise the
+	// ciphertext was not produced by EncryptDeterministic
+	expected := deterministicNonce(nonceKey, plaintext, associatedData, gcm.NonceSize())
+	if !hmac.Equal(nonce, expected) {
+		return nil, errors.New("failed to decrypt: synthetic nonce mismatch")
+	}
+
+	return plaintext, nil
+}
+
+// EncryptDeterministicString deterministically encrypts a string and returns
+// URL-safe base64, suitable for storing in and querying an indexed column
+func EncryptDeterministicString(key []byte, value string, associatedData []byte) (string, error) {
+	ciphertext, err := EncryptDeterministic(key, []byte(value), associatedData)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptDeterministicString decrypts a value produced by EncryptDeterministicString
+func DecryptDeterministicString(key []byte, value string, associatedData []byte) (string, error) {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	plaintext, err := DecryptDeterministic(key, ciphertext, associatedData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,180 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Key providers
+
+// ErrKeyNotFound is returned when a key provider has no key for the requested ID
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyProvider abstracts where keys live and how they are used, so a local key
+// store can later be swapped for a cloud KMS or HSM without touching call sites.
+// Implementations that never expose raw key material may return an error from GetKey.
+type KeyProvider interface {
+	// GetKey returns the raw key material for a key ID
+	GetKey(ctx context.Context, keyID string) ([]byte, error)
+	// Encrypt encrypts plaintext with the key identified by keyID
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+	// Decrypt decrypts ciphertext with the key identified by keyID
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+	// Sign returns a hex-encoded HMAC-SHA256 signature of data using keyID
+	Sign(ctx context.Context, keyID string, data []byte) (string, error)
+}
+
+// LocalKeyProvider is an in-memory KeyProvider backed by AES-GCM and HMAC
+type LocalKeyProvider struct {
+	mu   sync.RWMutex
+	keys map[string][]byte
+}
+
+// NewLocalKeyProvider creates a new local key provider
+func NewLocalKeyProvider() *LocalKeyProvider {
+	return &LocalKeyProvider{
+		keys: make(map[string][]byte),
+	}
+}
+
+// AddKey registers a key under an ID. AES keys must be 16, 24 or 32 bytes.
+func (p *LocalKeyProvider) AddKey(keyID string, key []byte) error {
+	if keyID == "" {
+		return errors.New("key ID cannot be empty")
+	}
+
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return fmt.Errorf("invalid key length %d: must be 16, 24 or 32 bytes", len(key))
+	}
+
+	stored := make([]byte, len(key))
+	copy(stored, key)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[keyID] = stored
+
+	return nil
+}
+
+// GenerateKey creates and registers a new random 256-bit key
+func (p *LocalKeyProvider) GenerateKey(keyID string) error {
+	key, err := GenerateRandomBytes(32)
+	if err != nil {
+		return err
+	}
+	return p.AddKey(keyID, key)
+}
+
+// RemoveKey removes a key from the provider
+func (p *LocalKeyProvider) RemoveKey(keyID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.keys, keyID)
+}
+
+// GetKey returns a copy of the key registered under keyID
+func (p *LocalKeyProvider) GetKey(ctx context.Context, keyID string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	key, ok := p.keys[keyID]
+	p.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+
+	result := make([]byte, len(key))
+	copy(result, key)
+	return result, nil
+}
+
+// Encrypt encrypts plaintext with AES-GCM using the key registered under keyID
+func (p *LocalKeyProvider) Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	key, err := p.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptAES(key, plaintext)
+}
+
+// Decrypt decrypts AES-GCM ciphertext using the key registered under keyID
+func (p *LocalKeyProvider) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	key, err := p.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptAES(key, ciphertext)
+}
+
+// Sign signs data with HMAC-SHA256 using the key registered under keyID
+func (p *LocalKeyProvider) Sign(ctx context.Context, keyID string, data []byte) (string, error) {
+	key, err := p.GetKey(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+	return SignHMAC(key, data), nil
+}
+
+// Verify checks a signature produced by Sign using any KeyProvider
+func Verify(ctx context.Context, provider KeyProvider, keyID string, data []byte, signature string) (bool, error) {
+	expected, err := provider.Sign(ctx, keyID, data)
+	if err != nil {
+		return false, err
+	}
+	return SecureCompare(expected, signature), nil
+}
+
+// EnvelopeCiphertext is the result of envelope encryption: data encrypted with a
+// fresh data key, and that data key encrypted with a provider-managed key
+type EnvelopeCiphertext struct {
+	KeyID        string `json:"key_id"`
+	EncryptedKey []byte `json:"encrypted_key"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// EnvelopeEncrypt encrypts plaintext with a random data key and wraps the data
+// key with the provider key identified by keyID
+func EnvelopeEncrypt(ctx context.Context, provider KeyProvider, keyID string, plaintext []byte) (*EnvelopeCiphertext, error) {
+	dataKey, err := GenerateRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := EncryptAES(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := provider.Encrypt(ctx, keyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return &EnvelopeCiphertext{
+		KeyID:        keyID,
+		EncryptedKey: encryptedKey,
+		Ciphertext:   ciphertext,
+	}, nil
+}
+
+// EnvelopeDecrypt unwraps the data key with the provider and decrypts the payload
+func EnvelopeDecrypt(ctx context.Context, provider KeyProvider, envelope *EnvelopeCiphertext) ([]byte, error) {
+	if envelope == nil {
+		return nil, errors.New("envelope cannot be nil")
+	}
+
+	dataKey, err := provider.Decrypt(ctx, envelope.KeyID, envelope.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	return DecryptAES(dataKey, envelope.Ciphertext)
+}
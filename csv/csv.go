@@ -0,0 +1,290 @@
+// Package csv encodes and decodes slices of structs to and from CSV, one
+// row at a time, using `csv` struct tags. Exported cells are sanitized
+// against spreadsheet formula injection by default.
+package csv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// **************************************************
+// Fields
+// Struct fields map to columns through the `csv` tag:
+//
+//	Name    string    `csv:"name"`
+//	Created time.Time `csv:"created,format=2006-01-02"`
+//	Secret  string    `csv:"-"`
+//
+// Untagged exported fields use their Go name; embedded structs
+// without a tag are flattened.
+// **************************************************
+
+const (
+	// Tag is the struct tag holding the column name and options
+	Tag = "csv"
+	// formatOption sets the time layout of a time.Time column
+	formatOption = "format="
+)
+
+var (
+	// ErrUnsupportedType is returned for record or field types that cannot
+	// be mapped to CSV
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrUnknownColumn is returned by a strict decoder for header columns
+	// without a matching field
+	ErrUnknownColumn = errors.New("unknown column")
+	// ErrMissingColumn is returned when a row has fewer cells than expected
+	ErrMissingColumn = errors.New("missing column")
+)
+
+// utf8BOM marks UTF-8 output so Excel detects the encoding
+const utf8BOM = "\uFEFF"
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// field is one column of a record type
+type field struct {
+	name   string
+	index  []int
+	layout string
+}
+
+// ParseError reports a cell that could not be decoded
+type ParseError struct {
+	Line   int    // line of the row in the input, starting at 1
+	Column string // column name
+	Err    error
+}
+
+// Error returns the error message
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("csv: line %d, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Header returns the column names of T in order
+func Header[T any]() ([]string, error) {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	return header, nil
+}
+
+// fieldsOf returns the columns of a struct type, or a pointer to one
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrUnsupportedType, t)
+	}
+
+	var fields []field
+	collectFields(t, nil, &fields)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: %s has no columns", ErrUnsupportedType, t)
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if seen[f.name] {
+			return nil, fmt.Errorf("%w: duplicate column %q in %s", ErrUnsupportedType, f.name, t)
+		}
+		seen[f.name] = true
+	}
+	return fields, nil
+}
+
+// collectFields appends the columns of t, flattening untagged embedded structs
+func collectFields(t reflect.Type, parent []int, fields *[]field) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup(Tag)
+		if tag == "-" {
+			continue
+		}
+
+		index := append(append([]int(nil), parent...), i)
+		if sf.Anonymous && !hasTag {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectFields(embedded, index, fields)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		f := field{name: name, index: index}
+		for _, option := range strings.Split(options, ",") {
+			if layout, ok := strings.CutPrefix(option, formatOption); ok {
+				f.layout = layout
+			}
+		}
+		*fields = append(*fields, f)
+	}
+}
+
+// fieldByIndex returns a field of v, allocating nil embedded pointers when
+// alloc is set; without alloc a nil pointer yields an invalid value
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// formatValue converts a field value to a cell
+func formatValue(v reflect.Value, layout string) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout), nil
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+}
+
+// parseValue sets a field value from a cell. Empty cells leave the zero
+// value, and a nil pointer for pointer fields.
+func parseValue(v reflect.Value, raw, layout string) error {
+	if raw == "" {
+		v.SetZero()
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package csv
+
+import (
+	"bufio"
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// **************************************************
+// Decoder
+// Decoder reads one record per row, matching header columns to
+// fields by name, so imports can be validated and stored as they
+// are read.
+// **************************************************
+
+// DecoderConfig configures a Decoder
+type DecoderConfig struct {
+	Comma                  rune // field delimiter; 0 uses ','
+	NoHeader               bool // the input has no header; columns follow field order
+	DisallowUnknownColumns bool // fail on header columns without a matching field
+	TrimSpace              bool // trim surrounding whitespace from cells
+	KeepFormulaEscape      bool // keep the quote SanitizeForCSV adds before formula characters
+}
+
+// Decoder reads records of type T from CSV rows
+type Decoder[T any] struct {
+	reader  *stdcsv.Reader
+	config  DecoderConfig
+	fields  []field
+	columns []*field // field for each input column; nil for ignored columns
+	started bool
+}
+
+// NewDecoder creates a decoder reading from r. T must be a struct or a
+// pointer to a struct.
+func NewDecoder[T any](r io.Reader, config DecoderConfig) (*Decoder[T], error) {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip a UTF-8 byte order mark, as written by Excel
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && string(bom) == utf8BOM {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+
+	reader := stdcsv.NewReader(buffered)
+	if config.Comma != 0 {
+		reader.Comma = config.Comma
+	}
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	return &Decoder[T]{reader: reader, config: config, fields: fields}, nil
+}
+
+// Decode reads the next record. It returns io.EOF when there are no more rows.
+func (d *Decoder[T]) Decode() (T, error) {
+	var record T
+
+	if !d.started {
+		if err := d.readHeader(); err != nil {
+			return record, err
+		}
+	}
+
+	row, err := d.reader.Read()
+	if err != nil {
+		return record, err
+	}
+	line, _ := d.reader.FieldPos(0)
+
+	v := reflect.ValueOf(&record).Elem()
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	for i := len(row); i < len(d.columns); i++ {
+		if d.columns[i] != nil {
+			return record, &ParseError{Line: line, Column: d.columns[i].name, Err: ErrMissingColumn}
+		}
+	}
+	for i, cell := range row {
+		if i >= len(d.columns) || d.columns[i] == nil {
+			continue
+		}
+		f := d.columns[i]
+		if d.config.TrimSpace {
+			cell = strings.TrimSpace(cell)
+		}
+		if !d.config.KeepFormulaEscape {
+			cell = unescapeFormula(cell)
+		}
+		if err := parseValue(fieldByIndex(v, f.index, true), cell, f.layout); err != nil {
+			return record, &ParseError{Line: line, Column: f.name, Err: err}
+		}
+	}
+	return record, nil
+}
+
+// DecodeAll reads every remaining record
+func (d *Decoder[T]) DecodeAll() ([]T, error) {
+	var records []T
+	for {
+		record, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// readHeader maps input columns to fields
+func (d *Decoder[T]) readHeader() error {
+	d.started = true
+
+	if d.config.NoHeader {
+		d.columns = make([]*field, len(d.fields))
+		for i := range d.fields {
+			d.columns[i] = &d.fields[i]
+		}
+		return nil
+	}
+
+	header, err := d.reader.Read()
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]*field, len(d.fields))
+	for i := range d.fields {
+		byName[d.fields[i].name] = &d.fields[i]
+	}
+
+	d.columns = make([]*field, len(header))
+	for i, name := range header {
+		name = unescapeFormula(strings.TrimSpace(name))
+		f, ok := byName[name]
+		if !ok && d.config.DisallowUnknownColumns {
+			return fmt.Errorf("%w: %q", ErrUnknownColumn, name)
+		}
+		d.columns[i] = f
+	}
+	return nil
+}
+
+// unescapeFormula removes the quote sanitize.SanitizeForCSV puts before
+// values starting with a formula character
+func unescapeFormula(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
+
+// Unmarshal reads every record from r, which must start with a header row
+func Unmarshal[T any](r io.Reader) ([]T, error) {
+	decoder, err := NewDecoder[T](r, DecoderConfig{})
+	if err != nil {
+		return nil, err
+	}
+	return decoder.DecodeAll()
+}
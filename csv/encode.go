@@ -0,0 +1,147 @@
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/arbenlabs/stoner/sanitize"
+)
+
+// **************************************************
+// Encoder
+// Encoder writes one record per row, so exports can stream
+// straight from the database to the response without holding
+// every record in memory.
+// **************************************************
+
+// EncoderConfig configures an Encoder
+type EncoderConfig struct {
+	Comma           rune // field delimiter; 0 uses ','
+	UseCRLF         bool // end rows with \r\n
+	NoHeader        bool // do not write a header row
+	BOM             bool // write a UTF-8 byte order mark so Excel detects the encoding
+	DisableSanitize bool // write cells as-is, without formula injection protection
+}
+
+// Encoder writes records of type T as CSV rows
+type Encoder[T any] struct {
+	writer        *stdcsv.Writer
+	out           io.Writer
+	config        EncoderConfig
+	fields        []field
+	row           []string
+	headerWritten bool
+}
+
+// NewEncoder creates an encoder writing to w. T must be a struct or a
+// pointer to a struct.
+func NewEncoder[T any](w io.Writer, config EncoderConfig) (*Encoder[T], error) {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	writer := stdcsv.NewWriter(w)
+	if config.Comma != 0 {
+		writer.Comma = config.Comma
+	}
+	writer.UseCRLF = config.UseCRLF
+
+	return &Encoder[T]{
+		writer: writer,
+		out:    w,
+		config: config,
+		fields: fields,
+		row:    make([]string, len(fields)),
+	}, nil
+}
+
+// WriteHeader writes the BOM and header row if they have not been written.
+// Encode calls it before the first record; call it directly to produce a
+// header for an empty export.
+func (e *Encoder[T]) WriteHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+
+	if e.config.BOM {
+		if _, err := io.WriteString(e.out, utf8BOM); err != nil {
+			return err
+		}
+	}
+	if e.config.NoHeader {
+		return nil
+	}
+	for i, f := range e.fields {
+		e.row[i] = e.sanitize(f.name)
+	}
+	return e.writer.Write(e.row)
+}
+
+// Encode writes one record. Rows are buffered; call Flush when done.
+func (e *Encoder[T]) Encode(record T) error {
+	if err := e.WriteHeader(); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(&record).Elem()
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fmt.Errorf("csv: cannot encode nil %T", record)
+		}
+		v = v.Elem()
+	}
+
+	for i, f := range e.fields {
+		fv := fieldByIndex(v, f.index, false)
+		if !fv.IsValid() {
+			e.row[i] = ""
+			continue
+		}
+		cell, err := formatValue(fv, f.layout)
+		if err != nil {
+			return fmt.Errorf("csv: column %q: %w", f.name, err)
+		}
+		e.row[i] = e.sanitize(cell)
+	}
+	return e.writer.Write(e.row)
+}
+
+// EncodeAll writes every record and flushes
+func (e *Encoder[T]) EncodeAll(records []T) error {
+	if err := e.WriteHeader(); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := e.Encode(record); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+// Flush writes buffered rows to the underlying writer
+func (e *Encoder[T]) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// sanitize protects a cell against formula injection unless disabled
+func (e *Encoder[T]) sanitize(cell string) string {
+	if e.config.DisableSanitize {
+		return cell
+	}
+	return sanitize.SanitizeForCSV(cell)
+}
+
+// Marshal writes records to w as CSV with a header and sanitized cells
+func Marshal[T any](w io.Writer, records []T) error {
+	encoder, err := NewEncoder[T](w, EncoderConfig{})
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeAll(records)
+}
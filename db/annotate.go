@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/arbenlabs/stoner/logger"
+)
+
+// AnnotateQuery prepends a comment naming the application, route and trace
+// of ctx to query, e.g. /* app=checkout route=POST:/orders trace=abc123 */,
+// so load in pg_stat_statements and slow query logs can be attributed.
+// Route and trace come from the logger context keys (logger.RouteKey,
+// logger.TraceIDKey and logger.RequestIDKey); parts without a value are
+// left out, and query is returned as is when none has one.
+func AnnotateQuery(ctx context.Context, app, query string) string {
+	var parts []string
+	add := func(key, value string) {
+		if value != "" {
+			parts = append(parts, key+"="+annotationValue(value))
+		}
+	}
+
+	add("app", app)
+	if ctx != nil {
+		route, _ := ctx.Value(logger.RouteKey).(string)
+		add("route", route)
+		trace, _ := ctx.Value(logger.TraceIDKey).(string)
+		add("trace", trace)
+		requestID, _ := ctx.Value(logger.RequestIDKey).(string)
+		add("request_id", requestID)
+	}
+
+	if len(parts) == 0 {
+		return query
+	}
+	return "/* " + strings.Join(parts, " ") + " */ " + query
+}
+
+// annotationValue replaces the characters that could end the comment or
+// split a part, keeping those found in routes and IDs
+func annotationValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("._-:/{}@", r):
+			return r
+		}
+		return '_'
+	}, value)
+}
+
+// annotate annotates query when Config.AnnotateQueries is set
+func (c *Connection) annotate(ctx context.Context, query string) string {
+	if c.Config == nil || !c.Config.AnnotateQueries {
+		return query
+	}
+	return AnnotateQuery(ctx, c.Config.Application, query)
+}
+
+// ExecContext executes a query, annotated when Config.AnnotateQueries is set
+func (c *Connection) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.DB.ExecContext(ctx, c.annotate(ctx, query), args...)
+}
+
+// QueryContext executes a query and returns rows, annotated when
+// Config.AnnotateQueries is set
+func (c *Connection) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DB.QueryContext(ctx, c.annotate(ctx, query), args...)
+}
+
+// QueryRowContext executes a query and returns a single row, annotated
+// when Config.AnnotateQueries is set
+func (c *Connection) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.DB.QueryRowContext(ctx, c.annotate(ctx, query), args...)
+}
@@ -1,9 +1,13 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
+
+	"github.com/arbenlabs/stoner/pagination"
+	"github.com/arbenlabs/stoner/retry"
 )
 
 // Config represents database configuration
@@ -18,6 +22,18 @@ type Config struct {
 	MaxIdleConns int
 	MaxLifetime  time.Duration
 	MaxIdleTime  time.Duration
+
+	// Application names the service in query annotations; with
+	// AnnotateQueries the Connection's query methods, its transactions and
+	// WithRetries prepend them. SQL generated by GORM in the gq package is
+	// not annotated.
+	Application     string
+	AnnotateQueries bool
+
+	// RetryPolicy and TransientErrors configure WithRetries; zero values
+	// use DefaultRetryPolicy and DefaultTransientErrors
+	RetryPolicy     retry.Policy
+	TransientErrors *TransientErrors
 }
 
 // Connection represents a database connection
@@ -85,17 +101,26 @@ func (c *Connection) Stats() sql.DBStats {
 
 // Transaction represents a database transaction
 type Transaction struct {
-	tx *sql.Tx
+	tx   *sql.Tx
+	ctx  context.Context
+	conn *Connection
 }
 
 // BeginTransaction begins a new transaction
 func (c *Connection) BeginTransaction() (*Transaction, error) {
-	tx, err := c.DB.Begin()
+	return c.BeginTx(context.Background())
+}
+
+// BeginTx begins a new transaction whose queries run with ctx and are
+// annotated from it. The transaction is rolled back if ctx is canceled
+// before it is committed.
+func (c *Connection) BeginTx(ctx context.Context) (*Transaction, error) {
+	tx, err := c.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	return &Transaction{tx: tx}, nil
+	return &Transaction{tx: tx, ctx: ctx, conn: c}, nil
 }
 
 // Commit commits the transaction
@@ -110,17 +135,17 @@ func (t *Transaction) Rollback() error {
 
 // Exec executes a query within the transaction
 func (t *Transaction) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return t.tx.Exec(query, args...)
+	return t.tx.ExecContext(t.ctx, t.conn.annotate(t.ctx, query), args...)
 }
 
 // Query executes a query and returns rows within the transaction
 func (t *Transaction) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	return t.tx.Query(query, args...)
+	return t.tx.QueryContext(t.ctx, t.conn.annotate(t.ctx, query), args...)
 }
 
 // QueryRow executes a query and returns a single row within the transaction
 func (t *Transaction) QueryRow(query string, args ...interface{}) *sql.Row {
-	return t.tx.QueryRow(query, args...)
+	return t.tx.QueryRowContext(t.ctx, t.conn.annotate(t.ctx, query), args...)
 }
 
 // QueryBuilder represents a query builder
@@ -180,6 +205,13 @@ func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
 	return qb
 }
 
+// Paginate sets the LIMIT and OFFSET clauses for an offset page request
+func (qb *QueryBuilder) Paginate(req pagination.PageRequest) *QueryBuilder {
+	qb.limitValue = req.Limit()
+	qb.offsetValue = req.Offset()
+	return qb
+}
+
 // Build builds the final query
 func (qb *QueryBuilder) Build() (string, []interface{}) {
 	query := "SELECT "
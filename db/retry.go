@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arbenlabs/stoner/retry"
+)
+
+// TransientErrors classifies the errors WithRetries retries: failures of
+// the connection or server, not of the statement
+type TransientErrors struct {
+	SQLStates []string // SQLSTATE codes, or two-character classes such as "08"
+	Messages  []string // substrings of the error message, matched case-insensitively
+	Errors    []error  // errors matched with errors.Is
+}
+
+// DefaultTransientErrors returns the errors seen during restarts and
+// failovers: dropped and refused connections, too many connections, a
+// server shutting down or starting up, and writes reaching a primary that
+// was demoted to a read-only replica
+func DefaultTransientErrors() TransientErrors {
+	return TransientErrors{
+		SQLStates: []string{
+			"08",    // connection exception
+			"53300", // too_many_connections
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03", // cannot_connect_now
+			"25006", // read_only_sql_transaction
+		},
+		Messages: []string{
+			"connection reset by peer",
+			"connection refused",
+			"broken pipe",
+			"too many connections",
+			"the database system is shutting down",
+			"the database system is starting up",
+		},
+		Errors: []error{
+			driver.ErrBadConn,
+			io.ErrUnexpectedEOF,
+			syscall.ECONNRESET,
+			syscall.ECONNREFUSED,
+			syscall.EPIPE,
+		},
+	}
+}
+
+// sqlStateError is implemented by driver errors that carry a SQLSTATE code,
+// such as those of pgx and lib/pq
+type sqlStateError interface {
+	SQLState() string
+}
+
+// IsTransient checks if err is one of the transient errors
+func (t TransientErrors) IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		for _, code := range t.SQLStates {
+			if state == code || (len(code) == 2 && strings.HasPrefix(state, code)) {
+				return true
+			}
+		}
+	}
+
+	for _, target := range t.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, substr := range t.Messages {
+		if strings.Contains(message, strings.ToLower(substr)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTransientError checks if err is one of DefaultTransientErrors
+func IsTransientError(err error) bool {
+	return DefaultTransientErrors().IsTransient(err)
+}
+
+// DefaultRetryPolicy is the policy used by WithRetries when the config has
+// none: up to 6 attempts over at most 30s, enough to ride out a failover
+func DefaultRetryPolicy() retry.Policy {
+	policy := retry.DefaultPolicy()
+	policy.MaxAttempts = 6
+	policy.InitialInterval = 200 * time.Millisecond
+	policy.MaxInterval = 5 * time.Second
+	policy.MaxElapsedTime = 30 * time.Second
+	policy.Jitter = 0.3
+	return policy
+}
+
+// WithRetries calls fn with the connection, retrying it with backoff while
+// it fails with a transient error. Queries fn runs through the connection's
+// methods or BeginTx are annotated like any others. fn may run several
+// times, so it must be safe to repeat: a read, an idempotent write, or a
+// whole transaction.
+// Config.RetryPolicy and Config.TransientErrors override the defaults; the
+// policy's Retryable function, when set, replaces the classification.
+func (c *Connection) WithRetries(ctx context.Context, fn func(ctx context.Context, conn *Connection) error) error {
+	policy := DefaultRetryPolicy()
+	transient := DefaultTransientErrors()
+	if c.Config != nil {
+		if p := c.Config.RetryPolicy; p.MaxAttempts != 0 || p.InitialInterval != 0 || p.MaxElapsedTime != 0 {
+			policy = p
+		}
+		if c.Config.TransientErrors != nil {
+			transient = *c.Config.TransientErrors
+		}
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = transient.IsTransient
+	}
+
+	return retry.Do(ctx, policy, func(ctx context.Context) error {
+		return fn(ctx, c)
+	})
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ScriptError is returned by ExecScript when a statement fails
+type ScriptError struct {
+	Statement int    // 1-based index of the failed statement
+	Line      int    // line of the script where the statement starts
+	SQL       string // the failed statement
+	Err       error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("script statement %d (line %d) failed: %v", e.Statement, e.Line, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// ExecScript executes a script of semicolon-separated statements, such as
+// a seed file or a SQL-file migration, one statement at a time. All
+// statements run on the same connection, so SET and BEGIN ... COMMIT in
+// the script behave as in psql. Execution stops at the first failing
+// statement, which is returned in a *ScriptError.
+func (c *Connection) ExecScript(ctx context.Context, sqlText string) error {
+	conn, err := c.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	for i, statement := range splitScript(sqlText) {
+		if _, err := conn.ExecContext(ctx, c.annotate(ctx, statement.sql)); err != nil {
+			return &ScriptError{Statement: i + 1, Line: statement.line, SQL: statement.sql, Err: err}
+		}
+	}
+	return nil
+}
+
+// SplitStatements splits a script into its statements, without the
+// separating semicolons. Semicolons inside quoted strings and identifiers,
+// comments and Postgres dollar-quoted bodies ($$ ... $$, $fn$ ... $fn$)
+// do not end a statement. Statements holding only comments are dropped.
+func SplitStatements(sqlText string) []string {
+	statements := splitScript(sqlText)
+	sqls := make([]string, len(statements))
+	for i, statement := range statements {
+		sqls[i] = statement.sql
+	}
+	return sqls
+}
+
+// scriptStatement is a statement of a script and the line it starts on
+type scriptStatement struct {
+	sql  string
+	line int
+}
+
+// splitScript scans a script, tracking quotes, comments and dollar quotes
+func splitScript(sqlText string) []scriptStatement {
+	var statements []scriptStatement
+	start, line, startLine := 0, 1, 1
+	hasCode := false // the current statement has more than comments and space
+
+	flush := func(end int) {
+		if hasCode {
+			statements = append(statements, scriptStatement{
+				sql:  strings.TrimSpace(sqlText[start:end]),
+				line: startLine,
+			})
+		}
+		hasCode = false
+	}
+
+	for i := 0; i < len(sqlText); i++ {
+		ch := sqlText[i]
+		if !hasCode && ch != ' ' && ch != '\t' && ch != '\r' && ch != '\n' && ch != ';' && !isCommentStart(sqlText, i) {
+			hasCode = true
+			startLine = line
+		}
+
+		switch {
+		case ch == '\n':
+			line++
+		case ch == ';':
+			flush(i)
+			start = i + 1
+		case ch == '\'' || ch == '"':
+			// E'...' strings escape quotes with backslashes
+			backslash := ch == '\'' && i > 0 && (sqlText[i-1] == 'E' || sqlText[i-1] == 'e') &&
+				(i == 1 || !isIdentChar(sqlText[i-2]))
+			i = skipQuoted(sqlText, i, ch, backslash, &line)
+		case ch == '-' && i+1 < len(sqlText) && sqlText[i+1] == '-':
+			for i < len(sqlText) && sqlText[i] != '\n' {
+				i++
+			}
+			if i < len(sqlText) {
+				line++
+			}
+		case ch == '/' && i+1 < len(sqlText) && sqlText[i+1] == '*':
+			i = skipBlockComment(sqlText, i, &line)
+		case ch == '$':
+			if tag, ok := dollarTag(sqlText, i); ok {
+				end := strings.Index(sqlText[i+len(tag):], tag)
+				if end < 0 {
+					end = len(sqlText) - i - len(tag) // Unterminated: the rest is the body
+				}
+				body := sqlText[i : i+len(tag)+end]
+				line += strings.Count(body, "\n")
+				i += len(tag) + end + len(tag) - 1
+			}
+		}
+	}
+	flush(len(sqlText))
+	return statements
+}
+
+// isCommentStart checks if a comment starts at i
+func isCommentStart(s string, i int) bool {
+	return i+1 < len(s) && ((s[i] == '-' && s[i+1] == '-') || (s[i] == '/' && s[i+1] == '*'))
+}
+
+// isIdentChar checks if ch can be part of an unquoted identifier
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// skipQuoted returns the index of the quote closing the string opened at i.
+// A doubled quote is an escaped quote.
+func skipQuoted(s string, i int, quote byte, backslash bool, line *int) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\n':
+			*line++
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(s) - 1
+}
+
+// skipBlockComment returns the index of the end of the comment opened at
+// i. Postgres block comments nest.
+func skipBlockComment(s string, i int, line *int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == '\n':
+			*line++
+		case s[i] == '/' && i+1 < len(s) && s[i+1] == '*':
+			depth++
+			i++
+		case s[i] == '*' && i+1 < len(s) && s[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
+
+// dollarTag returns the dollar-quote tag starting at i, such as $$ or
+// $body$. Positional parameters such as $1 are not tags.
+func dollarTag(s string, i int) (string, bool) {
+	if i > 0 && isIdentChar(s[i-1]) {
+		return "", false // e.g. a $ inside an identifier
+	}
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '$':
+			return s[i : j+1], true
+		case j == i+1 && s[j] >= '0' && s[j] <= '9':
+			return "", false
+		case !isIdentChar(s[j]):
+			return "", false
+		}
+	}
+	return "", false
+}
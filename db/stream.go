@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrStopStream can be returned by a stream callback to stop reading rows
+// without failing the stream
+var ErrStopStream = errors.New("stop stream")
+
+// StreamOption configures Stream
+type StreamOption func(*streamOptions)
+
+// streamOptions holds the options of Stream
+type streamOptions struct {
+	checkpointEvery int64
+	checkpoint      func(ctx context.Context, rows int64) error
+}
+
+// WithCheckpoint calls fn after every `every` rows and once after the last
+// row with the number of rows read so far, e.g. to flush an export file
+// and record progress. An error from fn stops the stream and is returned.
+func WithCheckpoint(every int, fn func(ctx context.Context, rows int64) error) StreamOption {
+	return func(o *streamOptions) {
+		o.checkpointEvery = int64(every)
+		o.checkpoint = fn
+	}
+}
+
+// Stream runs a query and calls fn for each row as it is read, instead of
+// loading the whole result set into memory. fn scans the current row with
+// scan, which takes the same destinations as sql.Rows.Scan. Returning
+// ErrStopStream from fn ends the stream early, after that row; any other
+// error aborts it.
+func (c *Connection) Stream(ctx context.Context, query string, args []interface{}, fn func(scan func(dest ...any) error) error, opts ...StreamOption) error {
+	var options streamOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.checkpoint != nil && options.checkpointEvery <= 0 {
+		return fmt.Errorf("checkpoint interval must be positive, got %d", options.checkpointEvery)
+	}
+
+	rows, err := c.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to run stream query: %w", err)
+	}
+	defer rows.Close()
+
+	var count, checkpointed int64
+	for rows.Next() {
+		count++
+		if err := fn(rows.Scan); err != nil {
+			if errors.Is(err, ErrStopStream) {
+				break
+			}
+			return fmt.Errorf("stream stopped at row %d: %w", count, err)
+		}
+
+		if options.checkpoint != nil && count%options.checkpointEvery == 0 {
+			if err := options.checkpoint(ctx, count); err != nil {
+				return fmt.Errorf("checkpoint failed after %d rows: %w", count, err)
+			}
+			checkpointed = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read stream rows: %w", err)
+	}
+
+	// Final checkpoint for the last partial batch
+	if options.checkpoint != nil && count > checkpointed {
+		if err := options.checkpoint(ctx, count); err != nil {
+			return fmt.Errorf("checkpoint failed after %d rows: %w", count, err)
+		}
+	}
+	return nil
+}
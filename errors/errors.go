@@ -0,0 +1,302 @@
+// Package errors provides structured application errors: a code that maps to
+// an HTTP status, a safe message, context fields, an optional cause, and the
+// stack where the error was created. Errors from this package work with the
+// standard library's errors.Is, errors.As and errors.Unwrap, which are also
+// re-exported so this package can replace the standard one in imports.
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// **************************************************
+// Errors
+// An Error carries a Code describing what went wrong, a message
+// that is safe to show to clients, and fields and a cause that are
+// only meant for logs.
+// **************************************************
+
+// Code classifies an error
+type Code string
+
+// Error codes
+const (
+	CodeInvalid         Code = "invalid"
+	CodeUnauthenticated Code = "unauthenticated"
+	CodeForbidden       Code = "forbidden"
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeRateLimited     Code = "rate_limited"
+	CodeUnavailable     Code = "unavailable"
+	CodeTimeout         Code = "timeout"
+	CodeInternal        Code = "internal"
+)
+
+// httpStatus maps codes to HTTP status codes
+var httpStatus = map[Code]int{
+	CodeInvalid:         http.StatusBadRequest,
+	CodeUnauthenticated: http.StatusUnauthorized,
+	CodeForbidden:       http.StatusForbidden,
+	CodeNotFound:        http.StatusNotFound,
+	CodeConflict:        http.StatusConflict,
+	CodeRateLimited:     http.StatusTooManyRequests,
+	CodeUnavailable:     http.StatusServiceUnavailable,
+	CodeTimeout:         http.StatusGatewayTimeout,
+	CodeInternal:        http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP status code of the code
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatus[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// maxStackDepth is the number of frames recorded for an error
+const maxStackDepth = 32
+
+// Error is a structured application error
+type Error struct {
+	Code    Code
+	Message string         // safe to return to clients
+	Fields  map[string]any // context for logs
+	Err     error          // underlying cause, if any
+
+	stack []uintptr
+}
+
+// New creates an error with a code and message, recording the caller's stack
+func New(code Code, message string) *Error {
+	return newError(code, message, nil)
+}
+
+// Errorf creates an error with a code and formatted message. The message
+// is returned to clients, so use Wrap to attach a cause instead of %w.
+func Errorf(code Code, format string, args ...any) *Error {
+	return newError(code, fmt.Sprintf(format, args...), nil)
+}
+
+// Wrap wraps err with a code and message. It returns nil if err is nil,
+// as an untyped error so that a nil result compares equal to nil.
+func Wrap(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	return newError(code, message, err)
+}
+
+// NotFound creates a CodeNotFound error
+func NotFound(message string) *Error {
+	return newError(CodeNotFound, message, nil)
+}
+
+// Conflict creates a CodeConflict error
+func Conflict(message string) *Error {
+	return newError(CodeConflict, message, nil)
+}
+
+// Invalid creates a CodeInvalid error
+func Invalid(message string) *Error {
+	return newError(CodeInvalid, message, nil)
+}
+
+// Internal wraps err as a CodeInternal error. The cause is kept for logs;
+// clients only see the generic message. It returns nil if err is nil.
+func Internal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return newError(CodeInternal, "internal server error", err)
+}
+
+// newError creates an error, recording the stack above its exported caller
+func newError(code Code, message string, cause error) *Error {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	return &Error{
+		Code:    code,
+		Message: message,
+		Err:     cause,
+		stack:   pcs[:n],
+	}
+}
+
+// Error returns the message, followed by the cause if there is one
+func (e *Error) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// With returns a copy of the error with a context field added
+func (e *Error) With(key string, value any) *Error {
+	clone := *e
+	clone.Fields = make(map[string]any, len(e.Fields)+1)
+	maps.Copy(clone.Fields, e.Fields)
+	clone.Fields[key] = value
+	return &clone
+}
+
+// WithFields returns a copy of the error with context fields added
+func (e *Error) WithFields(fields map[string]any) *Error {
+	clone := *e
+	clone.Fields = make(map[string]any, len(e.Fields)+len(fields))
+	maps.Copy(clone.Fields, e.Fields)
+	maps.Copy(clone.Fields, fields)
+	return &clone
+}
+
+// ErrorCode returns the code as a string, for loggers that do not import
+// this package
+func (e *Error) ErrorCode() string {
+	return string(e.Code)
+}
+
+// ErrorFields returns the context fields
+func (e *Error) ErrorFields() map[string]any {
+	return e.Fields
+}
+
+// HTTPStatus returns the HTTP status code of the error's code
+func (e *Error) HTTPStatus() int {
+	return e.Code.HTTPStatus()
+}
+
+// StackTrace returns the stack where the error was created, one
+// "function\n\tfile:line" entry per frame as in runtime/debug.Stack
+func (e *Error) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// LogValue logs the error as a group of its code, message, fields and cause
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", string(e.Code)),
+		slog.String("message", e.Message),
+	}
+	for key, value := range e.Fields {
+		attrs = append(attrs, slog.Any(key, value))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("cause", e.Err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// **************************************************
+// Inspection
+// **************************************************
+
+// Is reports whether any error in err's chain matches target, as errors.Is
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, as errors.As
+func As(err error, target any) bool {
+	return stderrors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, as errors.Unwrap
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
+
+// Join returns an error that wraps the given errors, as errors.Join
+func Join(errs ...error) error {
+	return stderrors.Join(errs...)
+}
+
+// From finds the first *Error in err's chain
+func From(err error) (*Error, bool) {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// CodeOf returns the code of the first *Error in err's chain. It returns
+// CodeInternal for other errors and an empty code for nil.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	if e, ok := From(err); ok {
+		return e.Code
+	}
+	return CodeInternal
+}
+
+// HTTPStatus returns the HTTP status code for err, or 200 for nil
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return CodeOf(err).HTTPStatus()
+}
+
+// PublicMessage returns the message of err that is safe to return to
+// clients: the message of the first *Error for codes other than
+// CodeInternal, and the status text otherwise
+func PublicMessage(err error) string {
+	if e, ok := From(err); ok && e.Code != CodeInternal && e.Message != "" {
+		return e.Message
+	}
+	return strings.ToLower(http.StatusText(HTTPStatus(err)))
+}
+
+// HasCode checks if err has the given code
+func HasCode(err error, code Code) bool {
+	return err != nil && CodeOf(err) == code
+}
+
+// IsNotFound checks if err has CodeNotFound
+func IsNotFound(err error) bool {
+	return HasCode(err, CodeNotFound)
+}
+
+// IsConflict checks if err has CodeConflict
+func IsConflict(err error) bool {
+	return HasCode(err, CodeConflict)
+}
+
+// IsInvalid checks if err has CodeInvalid
+func IsInvalid(err error) bool {
+	return HasCode(err, CodeInvalid)
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// GORM
+// FromGorm turns GORM errors into coded errors, so handlers can
+// return repository errors directly. Duplicate key and foreign key
+// errors are only recognized when the connection is opened with
+// gorm.Config{TranslateError: true}.
+// **************************************************
+
+// FromGorm translates a GORM error into an *Error. It returns nil for nil
+// and leaves errors that already carry a code unchanged.
+func FromGorm(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := From(err); ok {
+		return err
+	}
+
+	switch {
+	case stderrors.Is(err, gorm.ErrRecordNotFound):
+		return newError(CodeNotFound, "record not found", err)
+	case stderrors.Is(err, gorm.ErrDuplicatedKey):
+		return newError(CodeConflict, "record already exists", err)
+	case stderrors.Is(err, gorm.ErrForeignKeyViolated):
+		return newError(CodeInvalid, "referenced record does not exist", err)
+	case stderrors.Is(err, gorm.ErrCheckConstraintViolated):
+		return newError(CodeInvalid, "record violates a constraint", err)
+	case stderrors.Is(err, gorm.ErrInvalidData), stderrors.Is(err, gorm.ErrInvalidValue),
+		stderrors.Is(err, gorm.ErrInvalidField), stderrors.Is(err, gorm.ErrPrimaryKeyRequired):
+		return newError(CodeInvalid, "invalid data", err)
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return newError(CodeTimeout, "database operation timed out", err)
+	default:
+		return newError(CodeInternal, "internal server error", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package geo provides WGS 84 coordinate validation, great-circle
+// distances, bounding boxes and geohashes for location-based filtering.
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/arbenlabs/stoner/assert"
+)
+
+// **************************************************
+// Points and Distances
+// Distances use the haversine formula on a spherical Earth, which
+// is accurate to about 0.5%, and are returned in meters.
+// **************************************************
+
+// EarthRadius is the mean radius of the Earth in meters
+const EarthRadius = 6371008.8
+
+// Distance units in meters
+const (
+	Meter     = 1.0
+	Kilometer = 1000 * Meter
+	Mile      = 1609.344 * Meter
+)
+
+// Point is a location in decimal degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// NewPoint creates a point after validating its coordinates
+func NewPoint(lat, lng float64) (Point, error) {
+	p := Point{Lat: lat, Lng: lng}
+	if err := p.Validate(); err != nil {
+		return Point{}, err
+	}
+	return p, nil
+}
+
+// Validate checks that the latitude and longitude are in range
+func (p Point) Validate() error {
+	return assert.AssertValidCoordinates(p.Lat, p.Lng)
+}
+
+// String returns the point as "lat,lng"
+func (p Point) String() string {
+	return fmt.Sprintf("%g,%g", p.Lat, p.Lng)
+}
+
+// Distance returns the great-circle distance between two points in meters
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// DistanceTo returns the great-circle distance to another point in meters
+func (p Point) DistanceTo(other Point) float64 {
+	return Distance(p, other)
+}
+
+// radians converts degrees to radians
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// degrees converts radians to degrees
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// **************************************************
+// Bounding Boxes
+// A box whose MinLng is greater than its MaxLng crosses the
+// antimeridian (180th meridian).
+// **************************************************
+
+// BoundingBox is a latitude/longitude rectangle
+type BoundingBox struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// BoundingBoxAround returns the smallest box containing every point within
+// radius meters of center. Near the poles the box spans all longitudes.
+func BoundingBoxAround(center Point, radius float64) BoundingBox {
+	angular := radius / EarthRadius
+	lat := radians(center.Lat)
+	lng := radians(center.Lng)
+
+	minLat, maxLat := lat-angular, lat+angular
+	if minLat <= -math.Pi/2 || maxLat >= math.Pi/2 {
+		// The circle contains a pole
+		return BoundingBox{
+			MinLat: degrees(math.Max(minLat, -math.Pi/2)),
+			MinLng: -180,
+			MaxLat: degrees(math.Min(maxLat, math.Pi/2)),
+			MaxLng: 180,
+		}
+	}
+
+	dLng := math.Asin(math.Sin(angular) / math.Cos(lat))
+	return BoundingBox{
+		MinLat: degrees(minLat),
+		MinLng: normalizeLongitude(degrees(lng - dLng)),
+		MaxLat: degrees(maxLat),
+		MaxLng: normalizeLongitude(degrees(lng + dLng)),
+	}
+}
+
+// CrossesAntimeridian checks if the box wraps around the 180th meridian
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// Contains checks if a point lies inside the box
+func (b BoundingBox) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return p.Lng >= b.MinLng || p.Lng <= b.MaxLng
+	}
+	return p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+}
+
+// Center returns the middle of the box
+func (b BoundingBox) Center() Point {
+	lng := (b.MinLng + b.MaxLng) / 2
+	if b.CrossesAntimeridian() {
+		lng = normalizeLongitude((b.MinLng + b.MaxLng + 360) / 2)
+	}
+	return Point{Lat: (b.MinLat + b.MaxLat) / 2, Lng: lng}
+}
+
+// normalizeLongitude wraps a longitude into [-180, 180]
+func normalizeLongitude(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// **************************************************
+// Geohash
+// A geohash encodes a point as a base-32 string; each character
+// narrows the cell, and points sharing a prefix are close. 6
+// characters give cells of about 1.2km x 0.6km, 9 about 5m.
+// **************************************************
+
+// MaxGeohashPrecision is the longest geohash produced or accepted
+const MaxGeohashPrecision = 12
+
+// ErrInvalidGeohash is returned for geohashes with invalid characters or length
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+// geohashAlphabet is the geohash base-32 alphabet
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash returns the geohash of p with precision characters, from 1
+// to MaxGeohashPrecision
+func EncodeGeohash(p Point, precision int) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	if precision < 1 || precision > MaxGeohashPrecision {
+		return "", fmt.Errorf("%w: precision must be between 1 and %d", ErrInvalidGeohash, MaxGeohashPrecision)
+	}
+
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	hash := make([]byte, 0, precision)
+	even := true
+	bit, ch := 0, 0
+
+	for len(hash) < precision {
+		if even {
+			ch = ch<<1 | bisect(&lngRange, p.Lng)
+		} else {
+			ch = ch<<1 | bisect(&latRange, p.Lat)
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return string(hash), nil
+}
+
+// DecodeGeohash returns the cell of a geohash and its center
+func DecodeGeohash(hash string) (Point, BoundingBox, error) {
+	if hash == "" || len(hash) > MaxGeohashPrecision {
+		return Point{}, BoundingBox{}, fmt.Errorf("%w: %q", ErrInvalidGeohash, hash)
+	}
+
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	even := true
+
+	for _, c := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index < 0 {
+			return Point{}, BoundingBox{}, fmt.Errorf("%w: %q", ErrInvalidGeohash, hash)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			r := &latRange
+			if even {
+				r = &lngRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if index&mask != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+
+	box := BoundingBox{MinLat: latRange[0], MinLng: lngRange[0], MaxLat: latRange[1], MaxLng: lngRange[1]}
+	return box.Center(), box, nil
+}
+
+// bisect halves r around value and returns 1 if value is in the upper half
+func bisect(r *[2]float64, value float64) int {
+	mid := (r[0] + r[1]) / 2
+	if value >= mid {
+		r[0] = mid
+		return 1
+	}
+	r[1] = mid
+	return 0
+}
@@ -0,0 +1,185 @@
+package gq
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Aggregation
+// Sum, average, minimum and maximum of a numeric column over
+// the records matching a condition map, and the same grouped by
+// another column, for dashboard queries without raw SQL. Read
+// options such as soft delete and default scopes apply.
+// --------------------------------------------------
+// **************************************************
+
+// Aggregate is an aggregate function
+type Aggregate string
+
+// Aggregate functions
+const (
+	AggCount Aggregate = "COUNT"
+	AggSum   Aggregate = "SUM"
+	AggAvg   Aggregate = "AVG"
+	AggMin   Aggregate = "MIN"
+	AggMax   Aggregate = "MAX"
+)
+
+// aggregates lists the valid aggregate functions
+var aggregates = map[Aggregate]bool{
+	AggCount: true, AggSum: true, AggAvg: true, AggMin: true, AggMax: true,
+}
+
+// GroupResult is the aggregate of one group
+type GroupResult struct {
+	Key   interface{} // value of the group column
+	Count int64       // records in the group
+	Value float64     // the aggregate; 0 when every value is NULL
+}
+
+// aggregateQuery validates the columns and conditions of an aggregation of
+// model T and returns the query and the aggregate expression
+func aggregateQuery[T any](db *gorm.DB, aggregate Aggregate, field string, conditions map[string]interface{}, opts []QueryOption) (*gorm.DB, clause.Expr, error) {
+	if !aggregates[aggregate] {
+		return nil, clause.Expr{}, fmt.Errorf("unknown aggregate %q", aggregate)
+	}
+
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, clause.Expr{}, err
+	}
+
+	if aggregate == AggCount && field == "" {
+		expr := clause.Expr{SQL: "COUNT(*)"}
+		query, err := applyConditions[T](db.Model(new(T)), conditions)
+		return query, expr, err
+	}
+
+	if err := validateFieldName(field); err != nil {
+		return nil, clause.Expr{}, err
+	}
+	if !isFieldInModel[T](field) {
+		return nil, clause.Expr{}, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
+	}
+
+	expr := clause.Expr{SQL: string(aggregate) + "(?)", Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: field}}}
+	query, err := applyConditions[T](db.Model(new(T)), conditions)
+	return query, expr, err
+}
+
+// aggregateField computes aggregate over field for the records matching
+// conditions
+func aggregateField[T any](db *gorm.DB, aggregate Aggregate, field string, conditions map[string]interface{}, opts []QueryOption) (float64, error) {
+	query, expr, err := aggregateQuery[T](db, aggregate, field, conditions, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var value sql.NullFloat64
+	if err := query.Select("?", expr).Scan(&value).Error; err != nil {
+		return 0, err
+	}
+	return value.Float64, nil
+}
+
+// SumField sums field over the records matching conditions, or every
+// record when conditions is empty. It returns 0 when no record matches.
+func SumField[T any](db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return aggregateField[T](db, AggSum, field, conditions, opts)
+}
+
+// AvgField averages field over the records matching conditions. It
+// returns 0 when no record matches.
+func AvgField[T any](db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return aggregateField[T](db, AggAvg, field, conditions, opts)
+}
+
+// MinField returns the smallest value of a numeric field over the records
+// matching conditions, or 0 when no record matches.
+func MinField[T any](db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return aggregateField[T](db, AggMin, field, conditions, opts)
+}
+
+// MaxField returns the largest value of a numeric field over the records
+// matching conditions, or 0 when no record matches.
+func MaxField[T any](db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return aggregateField[T](db, AggMax, field, conditions, opts)
+}
+
+// GroupBy computes aggregate over field for each value of groupField among
+// the records matching conditions, ordered by the group value, e.g.
+// revenue per country:
+//
+//	gq.GroupBy[Order](db, "country", gq.AggSum, "amount", map[string]interface{}{"status": "paid"})
+//
+// With AggCount field may be empty to count records.
+func GroupBy[T any](db *gorm.DB, groupField string, aggregate Aggregate, field string, conditions map[string]interface{}, opts ...QueryOption) ([]GroupResult, error) {
+	if err := validateFieldName(groupField); err != nil {
+		return nil, err
+	}
+	if !isFieldInModel[T](groupField) {
+		return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, groupField)
+	}
+
+	query, expr, err := aggregateQuery[T](db, aggregate, field, conditions, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	group := clause.Column{Table: clause.CurrentTable, Name: groupField}
+	rows, err := query.
+		Select("? AS group_key, COUNT(*) AS group_count, ? AS group_value", group, expr).
+		Group(groupField).
+		Order(clause.OrderByColumn{Column: group}).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []GroupResult
+	for rows.Next() {
+		var result GroupResult
+		var value sql.NullFloat64
+		if err := rows.Scan(&result.Key, &result.Count, &value); err != nil {
+			return nil, err
+		}
+		if key, ok := result.Key.([]byte); ok {
+			result.Key = string(key) // Drivers return text as bytes
+		}
+		result.Value = value.Float64
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// SumFieldCtx sums field over the records matching conditions using ctx.
+func SumFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return SumField[T](db.WithContext(ctx), field, conditions, opts...)
+}
+
+// AvgFieldCtx averages field over the records matching conditions using ctx.
+func AvgFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return AvgField[T](db.WithContext(ctx), field, conditions, opts...)
+}
+
+// MinFieldCtx returns the smallest value of field over the records matching conditions using ctx.
+func MinFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return MinField[T](db.WithContext(ctx), field, conditions, opts...)
+}
+
+// MaxFieldCtx returns the largest value of field over the records matching conditions using ctx.
+func MaxFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, conditions map[string]interface{}, opts ...QueryOption) (float64, error) {
+	return MaxField[T](db.WithContext(ctx), field, conditions, opts...)
+}
+
+// GroupByCtx computes aggregate over field for each value of groupField using ctx.
+func GroupByCtx[T any](ctx context.Context, db *gorm.DB, groupField string, aggregate Aggregate, field string, conditions map[string]interface{}, opts ...QueryOption) ([]GroupResult, error) {
+	return GroupBy[T](db.WithContext(ctx), groupField, aggregate, field, conditions, opts...)
+}
@@ -0,0 +1,130 @@
+package gq
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Large Result Sets
+// Exports and background jobs read millions of rows without
+// holding them all in memory: FindInBatchesGeneric pushes
+// batches to a callback, paging by primary key so each batch
+// is an indexed query, and RecordIterator pulls one record at a
+// time from a single streaming query.
+// --------------------------------------------------
+// **************************************************
+
+// FindInBatchesGeneric calls fn with the records in the database matching
+// conditions, batchSize at a time in primary key order, or every record
+// when conditions is empty. An error from fn stops the iteration and is
+// returned. The batch slice is reused, so fn must copy records it keeps.
+func FindInBatchesGeneric[T any](db *gorm.DB, conditions map[string]interface{}, batchSize int, fn func(batch []T) error, opts ...QueryOption) error {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := validateBatchSize(batchSize); err != nil {
+		return err
+	}
+
+	query, err := applyConditions[T](db, conditions)
+	if err != nil {
+		return err
+	}
+
+	batch := make([]T, 0, batchSize)
+	result := query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	})
+	return result.Error
+}
+
+// RecordIterator reads the records of a query one at a time, like
+// sql.Rows. It holds a database connection until it is closed.
+type RecordIterator[T any] struct {
+	db     *gorm.DB
+	rows   *sql.Rows
+	record T
+	err    error
+}
+
+// IterateRecords starts reading the records in the database matching
+// conditions, or every record when conditions is empty. The iterator must
+// be closed:
+//
+//	it, err := gq.IterateRecords[Event](db, conditions)
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		event := it.Record()
+//		...
+//	}
+//	return it.Err()
+func IterateRecords[T any](db *gorm.DB, conditions map[string]interface{}, opts ...QueryOption) (*RecordIterator[T], error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := applyConditions[T](db.Model(new(T)), conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	return &RecordIterator[T]{db: query, rows: rows}, nil
+}
+
+// Next reads the next record, returning false at the end of the records or
+// on an error
+func (it *RecordIterator[T]) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+
+	var record T
+	if err := it.db.ScanRows(it.rows, &record); err != nil {
+		it.err = err
+		return false
+	}
+	it.record = record
+	return true
+}
+
+// Record returns the record read by the last call to Next
+func (it *RecordIterator[T]) Record() T {
+	return it.record
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *RecordIterator[T]) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+// Close releases the connection of the iterator
+func (it *RecordIterator[T]) Close() error {
+	return it.rows.Close()
+}
+
+// FindInBatchesGenericCtx calls fn with the records in the database matching conditions in batches using ctx.
+func FindInBatchesGenericCtx[T any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}, batchSize int, fn func(batch []T) error, opts ...QueryOption) error {
+	return FindInBatchesGeneric[T](db.WithContext(ctx), conditions, batchSize, fn, opts...)
+}
+
+// IterateRecordsCtx starts reading the records in the database matching conditions using ctx.
+func IterateRecordsCtx[T any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}, opts ...QueryOption) (*RecordIterator[T], error) {
+	return IterateRecords[T](db.WithContext(ctx), conditions, opts...)
+}
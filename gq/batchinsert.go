@@ -0,0 +1,141 @@
+package gq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Partial Batch Inserts
+// BatchInsertSkipErrors inserts what it can: a failing batch is
+// split in halves and retried until the failing rows are found,
+// so one bad row costs a few extra statements instead of the
+// whole import. Each attempt runs in its own transaction (a
+// savepoint inside an outer one) so a failed statement does not
+// abort the rest.
+// --------------------------------------------------
+// **************************************************
+
+// RowError is the error of one record of a batch
+type RowError struct {
+	Index int   // position of the record in the input
+	Err   error // why it could not be inserted
+}
+
+// Error returns the error message
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// BatchInsertReport describes the outcome of BatchInsertSkipErrors
+type BatchInsertReport struct {
+	InsertedIDs []interface{} // primary keys of the inserted records, in input order; empty for models without one
+	Errors      []RowError    // records that failed, in input order
+
+	inserted int
+}
+
+// Inserted returns the number of inserted records
+func (r BatchInsertReport) Inserted() int {
+	return r.inserted
+}
+
+// Failed returns the number of records that failed
+func (r BatchInsertReport) Failed() int {
+	return len(r.Errors)
+}
+
+// Err joins the row errors, or returns nil if every record was inserted
+func (r BatchInsertReport) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	errs := make([]error, len(r.Errors))
+	for i, rowErr := range r.Errors {
+		errs[i] = rowErr
+	}
+	return errors.Join(errs...)
+}
+
+// BatchInsertSkipErrors inserts a batch of records into the database,
+// skipping the records that fail. Inserted records get their primary
+// keys set as with BatchInsert. The error is only for invalid arguments
+// or a cancelled context; row failures are in the report.
+func BatchInsertSkipErrors[T any](db *gorm.DB, records []T, batchSize int) (BatchInsertReport, error) {
+	var report BatchInsertReport
+	if err := validateBatchSize(batchSize); err != nil {
+		return report, err
+	}
+
+	if len(records) == 0 {
+		return report, nil // Nothing to insert
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return report, err
+	}
+	idField := stmt.Schema.PrioritizedPrimaryField
+
+	for start := 0; start < len(records); start += batchSize {
+		end := min(start+batchSize, len(records))
+		if err := insertSplitting(db, records[start:end], start, &report); err != nil {
+			return report, err
+		}
+	}
+
+	if idField != nil {
+		failed := make(map[int]bool, len(report.Errors))
+		for _, rowErr := range report.Errors {
+			failed[rowErr.Index] = true
+		}
+		for i := range records {
+			if !failed[i] {
+				id, _ := idField.ValueOf(db.Statement.Context, reflect.ValueOf(&records[i]).Elem())
+				report.InsertedIDs = append(report.InsertedIDs, id)
+			}
+		}
+	}
+	return report, nil
+}
+
+// BatchInsertSkipErrorsCtx inserts a batch of records into the database, skipping failing records, using ctx.
+func BatchInsertSkipErrorsCtx[T any](ctx context.Context, db *gorm.DB, records []T, batchSize int) (BatchInsertReport, error) {
+	return BatchInsertSkipErrors(db.WithContext(ctx), records, batchSize)
+}
+
+// insertSplitting inserts records in one statement, or splits them in
+// halves when that fails. offset is the position of records in the input.
+func insertSplitting[T any](db *gorm.DB, records []T, offset int, report *BatchInsertReport) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&records).Error
+	})
+	if err == nil {
+		report.inserted += len(records)
+		return nil
+	}
+	if ctxErr := db.Statement.Context.Err(); ctxErr != nil {
+		return ctxErr // Stop instead of retrying every row
+	}
+
+	if len(records) == 1 {
+		report.Errors = append(report.Errors, RowError{Index: offset, Err: err})
+		return nil
+	}
+
+	half := len(records) / 2
+	if err := insertSplitting(db, records[:half], offset, report); err != nil {
+		return err
+	}
+	return insertSplitting(db, records[half:], offset+half, report)
+}
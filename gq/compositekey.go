@@ -0,0 +1,131 @@
+package gq
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Composite Keys
+// The ByID helpers assume a single "id" column. The ByKey
+// helpers take every part of the model's primary key instead,
+// e.g. {"order_id": 7, "line_no": 2}, for legacy tables with
+// composite keys. Parts are matched against the model's primary
+// fields by column or field name; every part is required.
+// --------------------------------------------------
+// **************************************************
+
+// Key is a primary key, by part
+type Key map[string]interface{}
+
+// keyCondition validates key against the primary key of model T and
+// builds its WHERE clause
+func keyCondition[T any](db *gorm.DB, key Key) (clause.Expression, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	primaryFields := stmt.Schema.PrimaryFields
+	if len(primaryFields) == 0 {
+		return nil, fmt.Errorf("%w: model %s has no primary key", ErrInvalidKey, stmt.Schema.Name)
+	}
+
+	// Resolve each part to its primary field
+	values := make(map[string]interface{}, len(key))
+	for part, value := range key {
+		if err := validateFieldName(part); err != nil {
+			return nil, fmt.Errorf("invalid key part '%s': %w", part, err)
+		}
+		field := stmt.Schema.LookUpField(part)
+		if field == nil {
+			return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, part)
+		}
+		if !field.PrimaryKey {
+			return nil, fmt.Errorf("%w: '%s' is not part of the primary key of %s", ErrInvalidKey, part, stmt.Schema.Name)
+		}
+		if _, ok := values[field.DBName]; ok {
+			return nil, fmt.Errorf("%w: '%s' is given twice", ErrInvalidKey, field.DBName)
+		}
+		values[field.DBName] = value
+	}
+
+	// Every part is required, so a key never matches more than one record
+	exprs := make([]clause.Expression, len(primaryFields))
+	for i, field := range primaryFields {
+		value, ok := values[field.DBName]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing part '%s' of the primary key of %s", ErrInvalidKey, field.DBName, stmt.Schema.Name)
+		}
+		exprs[i] = clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value}
+	}
+	return clause.And(exprs...), nil
+}
+
+// GetRecordByKey gets a record from the database by its composite key.
+func GetRecordByKey[T any](db *gorm.DB, key Key, opts ...QueryOption) (*T, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	where, err := keyCondition[T](db, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var record T
+	result := db.Where(where).First(&record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// UpdateRecordByKey updates a record in the database by its composite key.
+func UpdateRecordByKey[T any, U any](db *gorm.DB, key Key, updates U) error {
+	where, err := keyCondition[T](db, key)
+	if err != nil {
+		return err
+	}
+
+	var record T
+	result := db.Model(&record).Where(where).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// DeleteRecordByKey deletes a record from the database by its composite key.
+func DeleteRecordByKey[T any](db *gorm.DB, key Key) error {
+	where, err := keyCondition[T](db, key)
+	if err != nil {
+		return err
+	}
+
+	var record T
+	result := db.Where(where).Delete(&record)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// GetRecordByKeyCtx gets a record from the database by its composite key using ctx.
+func GetRecordByKeyCtx[T any](ctx context.Context, db *gorm.DB, key Key, opts ...QueryOption) (*T, error) {
+	return GetRecordByKey[T](db.WithContext(ctx), key, opts...)
+}
+
+// UpdateRecordByKeyCtx updates a record in the database by its composite key using ctx.
+func UpdateRecordByKeyCtx[T any, U any](ctx context.Context, db *gorm.DB, key Key, updates U) error {
+	return UpdateRecordByKey[T](db.WithContext(ctx), key, updates)
+}
+
+// DeleteRecordByKeyCtx deletes a record from the database by its composite key using ctx.
+func DeleteRecordByKeyCtx[T any](ctx context.Context, db *gorm.DB, key Key) error {
+	return DeleteRecordByKey[T](db.WithContext(ctx), key)
+}
@@ -0,0 +1,208 @@
+package gq
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Condition Groups
+// Conditions compose comparisons with And and Or, e.g.
+// And(Or(Eq("a", 1), Eq("b", 2)), Eq("c", 3)) for
+// (a = 1 OR b = 2) AND c = 3. Where adds a condition to any read
+// helper; field names are validated against the model and values
+// are always bound as parameters.
+// --------------------------------------------------
+// **************************************************
+
+// Condition is a filter built with Eq, Neq, Gt, Gte, Lt, Lte, Fields, And
+// and Or, or a Filter
+type Condition interface {
+	expression(validate func(field string) error) (clause.Expression, error)
+}
+
+// comparison compares a field with a value
+type comparison struct {
+	field string
+	op    string
+	value interface{}
+}
+
+// group combines conditions with AND or OR
+type group struct {
+	or         bool
+	conditions []Condition
+}
+
+// Eq matches records whose field equals value. As in condition maps, a
+// slice value matches any of its elements, NotIn excludes its elements,
+// and nil matches NULL.
+func Eq(field string, value interface{}) Condition {
+	return comparison{field: field, op: "=", value: value}
+}
+
+// Neq matches records whose field differs from value
+func Neq(field string, value interface{}) Condition {
+	return comparison{field: field, op: "<>", value: value}
+}
+
+// Gt matches records whose field is greater than value
+func Gt(field string, value interface{}) Condition {
+	return comparison{field: field, op: ">", value: value}
+}
+
+// Gte matches records whose field is greater than or equal to value
+func Gte(field string, value interface{}) Condition {
+	return comparison{field: field, op: ">=", value: value}
+}
+
+// Lt matches records whose field is less than value
+func Lt(field string, value interface{}) Condition {
+	return comparison{field: field, op: "<", value: value}
+}
+
+// Lte matches records whose field is less than or equal to value
+func Lte(field string, value interface{}) Condition {
+	return comparison{field: field, op: "<=", value: value}
+}
+
+// Fields matches records equal to every entry of a condition map, like
+// the maps of GetRecordsByFields, so maps can be combined with Or
+func Fields(conditions map[string]interface{}) Condition {
+	fields := make([]string, 0, len(conditions))
+	for field := range conditions {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields) // Deterministic SQL for query plan caches
+
+	g := group{conditions: make([]Condition, len(fields))}
+	for i, field := range fields {
+		g.conditions[i] = Eq(field, conditions[field])
+	}
+	return g
+}
+
+// And matches records that match every condition
+func And(conditions ...Condition) Condition {
+	return group{conditions: conditions}
+}
+
+// Or matches records that match any condition
+func Or(conditions ...Condition) Condition {
+	return group{or: true, conditions: conditions}
+}
+
+// Where filters the results of a read helper by a condition, ANDed with
+// its other filters
+func Where(condition Condition) QueryOption {
+	return func(o *queryOptions) {
+		o.conditions = append(o.conditions, condition)
+	}
+}
+
+// hasConditions checks if opts include a Where condition
+func hasConditions(opts []QueryOption) bool {
+	var options queryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return len(options.conditions) > 0
+}
+
+// expression builds the clause of a comparison
+func (c comparison) expression(validate func(field string) error) (clause.Expression, error) {
+	if err := validate(c.field); err != nil {
+		return nil, err
+	}
+	if err := validateConditionValue(c.field, c.value); err != nil {
+		return nil, err
+	}
+
+	column := clause.Column{Table: clause.CurrentTable, Name: c.field}
+	if c.op == "=" {
+		switch {
+		case isNotIn(c.value):
+			values := listValues(c.value.(NotIn).Values)
+			if len(values) == 0 {
+				return clause.Expr{SQL: "1 = 1"}, nil // Nothing to exclude
+			}
+			return clause.Not(clause.IN{Column: column, Values: values}), nil
+		case isListValue(c.value):
+			// An empty list renders as IN (NULL), which matches nothing
+			return clause.IN{Column: column, Values: listValues(c.value)}, nil
+		default:
+			return clause.Eq{Column: column, Value: c.value}, nil
+		}
+	}
+
+	if c.value == nil || isNotIn(c.value) || isListValue(c.value) {
+		return nil, fmt.Errorf("%w: %s %s needs a single value, got %T", ErrInvalidFilter, c.field, c.op, c.value)
+	}
+	switch c.op {
+	case "<>":
+		return clause.Neq{Column: column, Value: c.value}, nil
+	case ">":
+		return clause.Gt{Column: column, Value: c.value}, nil
+	case ">=":
+		return clause.Gte{Column: column, Value: c.value}, nil
+	case "<":
+		return clause.Lt{Column: column, Value: c.value}, nil
+	default:
+		return clause.Lte{Column: column, Value: c.value}, nil
+	}
+}
+
+// expression builds the clause of a group, in parentheses when nested
+func (g group) expression(validate func(field string) error) (clause.Expression, error) {
+	if len(g.conditions) == 0 {
+		return nil, fmt.Errorf("condition group cannot be empty")
+	}
+
+	exprs := make([]clause.Expression, len(g.conditions))
+	for i, condition := range g.conditions {
+		if condition == nil {
+			return nil, fmt.Errorf("condition group contains a nil condition")
+		}
+		expr, err := condition.expression(validate)
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = expr
+	}
+
+	if g.or {
+		return clause.Or(exprs...), nil
+	}
+	return clause.And(exprs...), nil
+}
+
+// conditionExpression builds a condition, validating its fields against
+// model T
+func conditionExpression[T any](condition Condition) (clause.Expression, error) {
+	if condition == nil {
+		return nil, fmt.Errorf("condition cannot be nil")
+	}
+	return condition.expression(func(field string) error {
+		if err := validateFieldName(field); err != nil {
+			return fmt.Errorf("invalid field '%s': %w", field, err)
+		}
+		if !isFieldInModel[T](field) {
+			return fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
+		}
+		return nil
+	})
+}
+
+// listValues converts a slice or array to the values of an IN clause
+func listValues(list interface{}) []interface{} {
+	v := reflect.ValueOf(list)
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
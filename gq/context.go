@@ -0,0 +1,117 @@
+package gq
+
+import (
+	"context"
+
+	"github.com/arbenlabs/stoner/pagination"
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Context-Aware Helpers
+// Each helper has a Ctx variant that runs its queries with
+// db.WithContext(ctx), so cancellation, deadlines and trace IDs
+// reach the database driver.
+// --------------------------------------------------
+// **************************************************
+
+// InsertRecordCtx inserts a record into the database using ctx.
+func InsertRecordCtx[T any](ctx context.Context, db *gorm.DB, record T) (*T, error) {
+	return InsertRecord(db.WithContext(ctx), record)
+}
+
+// BatchInsertCtx inserts a batch of records into the database using ctx.
+func BatchInsertCtx[T any](ctx context.Context, db *gorm.DB, records []T, batchSize int) error {
+	return BatchInsert(db.WithContext(ctx), records, batchSize)
+}
+
+// GetAllRecordsCtx gets all records from the database using ctx.
+func GetAllRecordsCtx[T any](ctx context.Context, db *gorm.DB, page, pageSize int, opts ...QueryOption) ([]T, int, error) {
+	return GetAllRecords[T](db.WithContext(ctx), page, pageSize, opts...)
+}
+
+// GetRecordByIDCtx gets a record from the database by ID using ctx.
+func GetRecordByIDCtx[T any](ctx context.Context, db *gorm.DB, id string, opts ...QueryOption) (*T, error) {
+	return GetRecordByID[T](db.WithContext(ctx), id, opts...)
+}
+
+// GetRecordByFieldCtx gets a record from the database by field using ctx.
+func GetRecordByFieldCtx[T any](ctx context.Context, db *gorm.DB, fieldName string, fieldValue interface{}, opts ...QueryOption) (*T, error) {
+	return GetRecordByField[T](db.WithContext(ctx), fieldName, fieldValue, opts...)
+}
+
+// LockAndGetRecordByFieldCtx gets a record from the database by field and locks the record using ctx.
+func LockAndGetRecordByFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, value interface{}, opts ...QueryOption) (*T, error) {
+	return LockAndGetRecordByField[T](db.WithContext(ctx), field, value, opts...)
+}
+
+// GetRecordsByFieldCtx gets records from the database by field using ctx.
+func GetRecordsByFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, value interface{}, page, pageSize int, orderBy string, opts ...QueryOption) ([]T, int64, error) {
+	return GetRecordsByField[T](db.WithContext(ctx), field, value, page, pageSize, orderBy, opts...)
+}
+
+// GetRecordsByFieldsCtx gets records from the database by fields using ctx.
+func GetRecordsByFieldsCtx[T any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}, opts ...QueryOption) ([]T, error) {
+	return GetRecordsByFields[T](db.WithContext(ctx), conditions, opts...)
+}
+
+// GetFilteredPaginatedRecordsCtx gets filtered paginated records from the database using ctx.
+func GetFilteredPaginatedRecordsCtx[T any](ctx context.Context, db *gorm.DB, page, pageSize int, conditions map[string]interface{}, opts ...QueryOption) ([]T, int, error) {
+	return GetFilteredPaginatedRecords[T](db.WithContext(ctx), page, pageSize, conditions, opts...)
+}
+
+// CountRecordsCtx counts the records in the database matching conditions using ctx.
+func CountRecordsCtx[T any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}, opts ...QueryOption) (int64, error) {
+	return CountRecords[T](db.WithContext(ctx), conditions, opts...)
+}
+
+// RecordExistsCtx checks if a record exists in the database by field using ctx.
+func RecordExistsCtx[T any](ctx context.Context, db *gorm.DB, field string, value interface{}, opts ...QueryOption) (bool, error) {
+	return RecordExists[T](db.WithContext(ctx), field, value, opts...)
+}
+
+// UpdateRecordByIDCtx updates a record in the database by ID using ctx.
+func UpdateRecordByIDCtx[T any, U any](ctx context.Context, db *gorm.DB, id string, updates U) error {
+	return UpdateRecordByID[T](db.WithContext(ctx), id, updates)
+}
+
+// LockAndUpdateRecordByIDCtx updates a record in the database by ID and locks the record using ctx.
+func LockAndUpdateRecordByIDCtx[T any, U any](ctx context.Context, db *gorm.DB, id string, updates U) error {
+	return LockAndUpdateRecordByID[T](db.WithContext(ctx), id, updates)
+}
+
+// UpdateRecordByFieldCtx updates a record in the database by field using ctx.
+func UpdateRecordByFieldCtx[T any, U any](ctx context.Context, db *gorm.DB, field string, value interface{}, updates U) error {
+	return UpdateRecordByField[T](db.WithContext(ctx), field, value, updates)
+}
+
+// BulkUpdateByFieldsCtx updates the records in the database matching conditions using ctx.
+func BulkUpdateByFieldsCtx[T any, U any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}, updates U) (int64, error) {
+	return BulkUpdateByFields[T](db.WithContext(ctx), conditions, updates)
+}
+
+// DeleteRecordByIDCtx deletes a record in the database by ID using ctx.
+func DeleteRecordByIDCtx[T any](ctx context.Context, db *gorm.DB, id string) error {
+	return DeleteRecordByID[T](db.WithContext(ctx), id)
+}
+
+// BulkDeleteByFieldsCtx deletes the records in the database matching conditions using ctx.
+func BulkDeleteByFieldsCtx[T any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}) (int64, error) {
+	return BulkDeleteByFields[T](db.WithContext(ctx), conditions)
+}
+
+// DeleteInBatchesCtx deletes the records in the database matching conditions in batches using ctx.
+func DeleteInBatchesCtx[T any](ctx context.Context, db *gorm.DB, conditions map[string]interface{}, batchSize int) (int64, error) {
+	return DeleteInBatches[T](db.WithContext(ctx), conditions, batchSize)
+}
+
+// PaginateCtx returns an offset page of the records matched by db using ctx.
+func PaginateCtx[T any](ctx context.Context, db *gorm.DB, req pagination.PageRequest, opts ...QueryOption) (pagination.PageResponse[T], error) {
+	return Paginate[T](db.WithContext(ctx), req, opts...)
+}
+
+// PaginateByCursorCtx returns the page of records after req.Cursor using ctx.
+func PaginateByCursorCtx[T any, K any](ctx context.Context, db *gorm.DB, codec *pagination.CursorCodec, req pagination.PageRequest, column string, key func(T) K, opts ...QueryOption) (pagination.PageResponse[T], error) {
+	return PaginateByCursor(db.WithContext(ctx), codec, req, column, key, opts...)
+}
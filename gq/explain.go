@@ -0,0 +1,318 @@
+package gq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Query Plans
+// ExplainQuery runs EXPLAIN on the query GetRecordsByFields would
+// generate and summarizes the plan, to diagnose slow gq queries
+// in-app. Postgres and MySQL plans are read as JSON and SQLite
+// plans from EXPLAIN QUERY PLAN; ANALYZE is Postgres only.
+// --------------------------------------------------
+// **************************************************
+
+// ErrExplainUnsupported is returned when the database cannot explain a query
+var ErrExplainUnsupported = errors.New("explain not supported")
+
+// PlanNode is one step of a query plan
+type PlanNode struct {
+	Operation     string  // e.g. "Seq Scan", "Index Scan", "SCAN"
+	Table         string  // table read by the step, if any
+	Index         string  // index used by the step, if any
+	Depth         int     // nesting level in the plan tree
+	EstimatedRows float64 // rows the planner expects, when reported
+	ActualRows    float64 // rows produced, with ANALYZE
+	FullScan      bool    // reads the whole table without an index
+	Detail        string  // the database's description, when reported
+}
+
+// QueryPlan summarizes the plan of a query
+type QueryPlan struct {
+	SQL           string        // the explained query, with placeholders
+	Nodes         []PlanNode    // plan steps, in tree order
+	TotalCost     float64       // planner's estimated cost, when reported
+	EstimatedRows float64       // rows the planner expects, when reported
+	PlanningTime  time.Duration // with ANALYZE
+	ExecutionTime time.Duration // with ANALYZE
+	Raw           string        // the plan as returned by the database
+}
+
+// FullScans returns the tables read without an index
+func (p *QueryPlan) FullScans() []string {
+	var tables []string
+	for _, node := range p.Nodes {
+		if node.FullScan && node.Table != "" {
+			tables = append(tables, node.Table)
+		}
+	}
+	return tables
+}
+
+// String returns the plan as an indented tree
+func (p *QueryPlan) String() string {
+	var b strings.Builder
+	for _, node := range p.Nodes {
+		b.WriteString(strings.Repeat("  ", node.Depth))
+		if node.Detail != "" {
+			b.WriteString(node.Detail)
+		} else {
+			b.WriteString(node.Operation)
+			if node.Table != "" {
+				fmt.Fprintf(&b, " on %s", node.Table)
+			}
+			if node.Index != "" {
+				fmt.Fprintf(&b, " using %s", node.Index)
+			}
+		}
+		if node.EstimatedRows > 0 {
+			fmt.Fprintf(&b, " (rows=%g)", node.EstimatedRows)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ExplainQuery explains the query GetRecordsByFields would run for
+// conditions, ordered by orderBy if it is not empty. With analyze the query
+// is executed to measure it, so only analyze reads on production data.
+func ExplainQuery[T any](db *gorm.DB, conditions map[string]interface{}, orderBy string, analyze bool, opts ...QueryOption) (*QueryPlan, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := applyConditions[T](db, conditions)
+	if err != nil {
+		return nil, err
+	}
+	if orderBy != "" {
+		if err := validateOrderBy(orderBy); err != nil {
+			return nil, err
+		}
+		query = query.Order(orderBy)
+	}
+
+	// Build the statement without running it
+	var records []T
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&records).Statement
+	if stmt.Error != nil {
+		return nil, stmt.Error
+	}
+	sql := stmt.SQL.String()
+
+	var prefix string
+	switch db.Dialector.Name() {
+	case "postgres":
+		prefix = "EXPLAIN (FORMAT JSON) "
+		if analyze {
+			prefix = "EXPLAIN (ANALYZE, FORMAT JSON) "
+		}
+	case "mysql":
+		prefix = "EXPLAIN FORMAT=JSON "
+	case "sqlite":
+		prefix = "EXPLAIN QUERY PLAN "
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrExplainUnsupported, db.Dialector.Name())
+	}
+	if analyze && db.Dialector.Name() != "postgres" {
+		return nil, fmt.Errorf("%w: analyze on %s", ErrExplainUnsupported, db.Dialector.Name())
+	}
+
+	// Run on the connection directly so the dialect's placeholders are kept
+	rows, err := stmt.ConnPool.QueryContext(stmt.Context, prefix+sql, stmt.Vars...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan := &QueryPlan{SQL: sql}
+	if db.Dialector.Name() == "sqlite" {
+		err = parseSQLitePlan(plan, rows)
+	} else {
+		var raw string
+		if rows.Next() {
+			if err := rows.Scan(&raw); err != nil {
+				return nil, err
+			}
+		}
+		plan.Raw = raw
+		if db.Dialector.Name() == "postgres" {
+			err = parsePostgresPlan(plan, raw)
+		} else {
+			err = parseMySQLPlan(plan, raw)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, rows.Err()
+}
+
+// postgresNode is a node of a Postgres JSON plan
+type postgresNode struct {
+	NodeType     string         `json:"Node Type"`
+	RelationName string         `json:"Relation Name"`
+	IndexName    string         `json:"Index Name"`
+	TotalCost    float64        `json:"Total Cost"`
+	PlanRows     float64        `json:"Plan Rows"`
+	ActualRows   float64        `json:"Actual Rows"`
+	ActualLoops  float64        `json:"Actual Loops"`
+	Plans        []postgresNode `json:"Plans"`
+}
+
+// parsePostgresPlan reads the output of EXPLAIN (FORMAT JSON)
+func parsePostgresPlan(plan *QueryPlan, raw string) error {
+	var explained []struct {
+		Plan          postgresNode `json:"Plan"`
+		PlanningTime  float64      `json:"Planning Time"`
+		ExecutionTime float64      `json:"Execution Time"`
+	}
+	if err := json.Unmarshal([]byte(raw), &explained); err != nil || len(explained) == 0 {
+		return fmt.Errorf("cannot parse postgres plan: %v", err)
+	}
+
+	root := explained[0]
+	plan.TotalCost = root.Plan.TotalCost
+	plan.EstimatedRows = root.Plan.PlanRows
+	plan.PlanningTime = milliseconds(root.PlanningTime)
+	plan.ExecutionTime = milliseconds(root.ExecutionTime)
+
+	var walk func(node postgresNode, depth int)
+	walk = func(node postgresNode, depth int) {
+		plan.Nodes = append(plan.Nodes, PlanNode{
+			Operation:     node.NodeType,
+			Table:         node.RelationName,
+			Index:         node.IndexName,
+			Depth:         depth,
+			EstimatedRows: node.PlanRows,
+			ActualRows:    node.ActualRows * max(node.ActualLoops, 1),
+			FullScan:      node.NodeType == "Seq Scan",
+		})
+		for _, child := range node.Plans {
+			walk(child, depth+1)
+		}
+	}
+	walk(root.Plan, 0)
+	return nil
+}
+
+// parseMySQLPlan reads the output of EXPLAIN FORMAT=JSON, collecting the
+// "table" entries wherever they are nested
+func parseMySQLPlan(plan *QueryPlan, raw string) error {
+	var explained map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &explained); err != nil {
+		return fmt.Errorf("cannot parse mysql plan: %v", err)
+	}
+	if block, ok := explained["query_block"].(map[string]interface{}); ok {
+		if info, ok := block["cost_info"].(map[string]interface{}); ok {
+			plan.TotalCost = jsonNumber(info["query_cost"])
+		}
+	}
+
+	var walk func(value interface{}, depth int)
+	walk = func(value interface{}, depth int) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if table, ok := v["table"].(map[string]interface{}); ok {
+				accessType, _ := table["access_type"].(string)
+				name, _ := table["table_name"].(string)
+				key, _ := table["key"].(string)
+				plan.Nodes = append(plan.Nodes, PlanNode{
+					Operation:     accessType,
+					Table:         name,
+					Index:         key,
+					Depth:         depth,
+					EstimatedRows: jsonNumber(table["rows_examined_per_scan"]),
+					FullScan:      accessType == "ALL",
+				})
+			}
+			for key, child := range v {
+				if key != "table" {
+					walk(child, depth+1)
+				}
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child, depth)
+			}
+		}
+	}
+	walk(explained, 0)
+	return nil
+}
+
+// sqlitePlanRows is the subset of *sql.Rows used to read SQLite plans
+type sqlitePlanRows interface {
+	Next() bool
+	Scan(dest ...any) error
+}
+
+// parseSQLitePlan reads the rows of EXPLAIN QUERY PLAN: id, parent,
+// notused and a detail such as "SCAN users" or
+// "SEARCH users USING INDEX idx_users_email (email=?)"
+func parseSQLitePlan(plan *QueryPlan, rows sqlitePlanRows) error {
+	depths := map[int]int{0: -1}
+	var lines []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return err
+		}
+		lines = append(lines, detail)
+		depth := depths[parent] + 1
+		depths[id] = depth
+
+		node := PlanNode{Depth: depth, Detail: detail}
+		fields := strings.Fields(detail)
+		if len(fields) > 0 {
+			node.Operation = fields[0]
+		}
+		if (node.Operation == "SCAN" || node.Operation == "SEARCH") && len(fields) > 1 {
+			node.Table = fields[1]
+			if fields[1] == "TABLE" && len(fields) > 2 { // Older SQLite: "SCAN TABLE users"
+				node.Table = fields[2]
+			}
+		}
+		if i := strings.Index(detail, "USING "); i >= 0 {
+			usingFields := strings.Fields(detail[i:])
+			for j, field := range usingFields {
+				if field == "INDEX" && j+1 < len(usingFields) {
+					node.Index = usingFields[j+1]
+					break
+				}
+			}
+		}
+		node.FullScan = node.Operation == "SCAN" && node.Index == "" && node.Table != ""
+		plan.Nodes = append(plan.Nodes, node)
+	}
+	plan.Raw = strings.Join(lines, "\n")
+	return nil
+}
+
+// milliseconds converts a duration in milliseconds
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// jsonNumber reads a JSON number that may be encoded as a string
+func jsonNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		var f float64
+		fmt.Sscan(v, &f)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,229 @@
+package gq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Filter Conditions
+// Condition values in GetRecordsByFields and
+// GetFilteredPaginatedRecords are matched by equality, or by
+// membership when the value is a slice: []string{"open",
+// "pending"} becomes status IN (?). Wrap a slice in NotIn to
+// exclude values instead, or use a Filter for any other
+// operator, such as a range on a numeric or date column.
+// --------------------------------------------------
+// **************************************************
+
+// NotIn excludes the values of a slice, e.g.
+// map[string]interface{}{"status": gq.NotIn{Values: []string{"closed"}}}.
+// An empty slice excludes nothing.
+type NotIn struct {
+	Values interface{}
+}
+
+// isListValue checks if a condition value is a list of values. Byte
+// slices are single values, as they are for GORM.
+func isListValue(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if _, ok := value.([]byte); ok {
+		return false
+	}
+	kind := reflect.TypeOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// validateConditionValue checks that NotIn wraps a list
+func validateConditionValue(field string, value interface{}) error {
+	if notIn, ok := value.(NotIn); ok && !isListValue(notIn.Values) {
+		return fmt.Errorf("NotIn value for %s must be a slice, got %T", field, notIn.Values)
+	}
+	return nil
+}
+
+// whereCondition adds the condition of a validated field to query
+func whereCondition(query *gorm.DB, field string, value interface{}) *gorm.DB {
+	switch {
+	case isNotIn(value):
+		values := value.(NotIn).Values
+		if reflect.ValueOf(values).Len() == 0 {
+			return query // Nothing to exclude
+		}
+		return query.Where(field+" NOT IN (?)", values)
+	case isListValue(value):
+		// GORM renders an empty list as IN (NULL), which matches nothing
+		return query.Where(field+" IN (?)", value)
+	default:
+		return query.Where(field+" = ?", value)
+	}
+}
+
+// isNotIn checks if a condition value is a NotIn exclusion
+func isNotIn(value interface{}) bool {
+	_, ok := value.(NotIn)
+	return ok
+}
+
+// Op is a filter operator
+type Op string
+
+// Filter operators
+const (
+	OpEq      Op = "eq"      // field = value; a slice value matches any element
+	OpNeq     Op = "neq"     // field <> value
+	OpGt      Op = "gt"      // field > value
+	OpGte     Op = "gte"     // field >= value
+	OpLt      Op = "lt"      // field < value
+	OpLte     Op = "lte"     // field <= value
+	OpLike    Op = "like"    // field LIKE value, e.g. "%smith%"
+	OpILike   Op = "ilike"   // case-insensitive LIKE
+	OpBetween Op = "between" // field BETWEEN value[0] AND value[1]
+	OpIn      Op = "in"      // field IN value, a slice
+)
+
+// ops lists the valid operators
+var ops = map[Op]bool{
+	OpEq: true, OpNeq: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpLike: true, OpILike: true, OpBetween: true, OpIn: true,
+}
+
+// ParseOp parses an operator name, e.g. from a query string
+func ParseOp(s string) (Op, error) {
+	op := Op(strings.ToLower(strings.TrimSpace(s)))
+	if !ops[op] {
+		return "", fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, s)
+	}
+	return op, nil
+}
+
+// Filter compares a field with a value, e.g.
+// Filter{Field: "created_at", Op: OpGte, Value: since}. Filters are
+// Conditions, so they can be passed to Where and combined with And and
+// Or. As a condition map value the field defaults to the map key:
+// map[string]interface{}{"price": Filter{Op: OpLte, Value: 100}}.
+type Filter struct {
+	Field string
+	Op    Op
+	Value interface{}
+}
+
+// ParseRangeFilter parses the "number followed by + or -" syntax once
+// hardcoded for price and pct_remaining, for any column: "100+" is
+// field >= 100 and "100-" is field <= 100. Numbers are parsed as float64;
+// other values, such as dates, are compared as strings.
+func ParseRangeFilter(field, value string) (Filter, error) {
+	if len(value) < 2 {
+		return Filter{}, fmt.Errorf("%w: %s value %q must be followed by + or -", ErrInvalidFilter, field, value)
+	}
+
+	var op Op
+	switch value[len(value)-1] {
+	case '+':
+		op = OpGte
+	case '-':
+		op = OpLte
+	default:
+		return Filter{}, fmt.Errorf("%w: %s value %q must be followed by + or -", ErrInvalidFilter, field, value)
+	}
+
+	var bound interface{} = value[:len(value)-1]
+	if number, err := strconv.ParseFloat(value[:len(value)-1], 64); err == nil {
+		bound = number
+	}
+	return Filter{Field: field, Op: op, Value: bound}, nil
+}
+
+// expression builds the clause of a filter
+func (f Filter) expression(validate func(field string) error) (clause.Expression, error) {
+	if !ops[f.Op] {
+		return nil, fmt.Errorf("%w: unknown operator %q on %s", ErrInvalidFilter, f.Op, f.Field)
+	}
+
+	switch f.Op {
+	case OpEq:
+		return Eq(f.Field, f.Value).expression(validate)
+	case OpNeq:
+		return Neq(f.Field, f.Value).expression(validate)
+	case OpGt:
+		return Gt(f.Field, f.Value).expression(validate)
+	case OpGte:
+		return Gte(f.Field, f.Value).expression(validate)
+	case OpLt:
+		return Lt(f.Field, f.Value).expression(validate)
+	case OpLte:
+		return Lte(f.Field, f.Value).expression(validate)
+	}
+
+	if err := validate(f.Field); err != nil {
+		return nil, err
+	}
+	column := clause.Column{Table: clause.CurrentTable, Name: f.Field}
+
+	switch f.Op {
+	case OpLike, OpILike:
+		pattern, ok := f.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s %s needs a string pattern, got %T", ErrInvalidFilter, f.Field, f.Op, f.Value)
+		}
+		if f.Op == OpLike {
+			return clause.Like{Column: column, Value: pattern}, nil
+		}
+		// LOWER on both sides works on every database, unlike ILIKE
+		return clause.Expr{SQL: "LOWER(?) LIKE LOWER(?)", Vars: []interface{}{column, pattern}}, nil
+	case OpBetween:
+		if !isListValue(f.Value) || reflect.ValueOf(f.Value).Len() != 2 {
+			return nil, fmt.Errorf("%w: %s between needs two values, got %v", ErrInvalidFilter, f.Field, f.Value)
+		}
+		bounds := listValues(f.Value)
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{column, bounds[0], bounds[1]}}, nil
+	default: // OpIn
+		if !isListValue(f.Value) {
+			return nil, fmt.Errorf("%w: %s in needs a slice, got %T", ErrInvalidFilter, f.Field, f.Value)
+		}
+		return Eq(f.Field, f.Value).expression(validate)
+	}
+}
+
+// applyConditions validates a condition map against model T and adds it
+// to query. Values are matched as described by whereCondition, or by
+// their operator for Filters.
+func applyConditions[T any](query *gorm.DB, conditions map[string]interface{}) (*gorm.DB, error) {
+	for field, value := range conditions {
+		if err := validateFieldName(field); err != nil {
+			return nil, fmt.Errorf("invalid field '%s': %w", field, err)
+		}
+
+		if !isFieldInModel[T](field) {
+			return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
+		}
+
+		if filter, ok := value.(Filter); ok {
+			if filter.Field == "" {
+				filter.Field = field
+			} else if filter.Field != field {
+				return nil, fmt.Errorf("%w: filter on %s is keyed by %s", ErrInvalidFilter, filter.Field, field)
+			}
+			expr, err := conditionExpression[T](filter)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(expr)
+			continue
+		}
+
+		if err := validateConditionValue(field, value); err != nil {
+			return nil, err
+		}
+		query = whereCondition(query, field, value)
+	}
+	return query, nil
+}
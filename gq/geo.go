@@ -0,0 +1,112 @@
+package gq
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/arbenlabs/stoner/geo"
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Geo Queries
+// Location filters on latitude and longitude columns. A bounding
+// box narrows the rows in SQL, where an index on the columns can
+// be used, and exact distances are checked in Go.
+// --------------------------------------------------
+// **************************************************
+
+// WithinBoundingBox returns db filtered to rows whose latColumn and
+// lngColumn lie inside box, including boxes crossing the antimeridian
+func WithinBoundingBox(db *gorm.DB, latColumn, lngColumn string, box geo.BoundingBox) (*gorm.DB, error) {
+	if err := validateFieldName(latColumn); err != nil {
+		return nil, err
+	}
+	if err := validateFieldName(lngColumn); err != nil {
+		return nil, err
+	}
+
+	query := db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", latColumn), box.MinLat, box.MaxLat)
+	if box.CrossesAntimeridian() {
+		return query.Where(fmt.Sprintf("(%s >= ? OR %s <= ?)", lngColumn, lngColumn), box.MinLng, box.MaxLng), nil
+	}
+	return query.Where(fmt.Sprintf("%s BETWEEN ? AND ?", lngColumn), box.MinLng, box.MaxLng), nil
+}
+
+// GetRecordsInBoundingBox gets records from the database whose coordinates lie inside box.
+func GetRecordsInBoundingBox[T any](db *gorm.DB, latColumn, lngColumn string, box geo.BoundingBox, opts ...QueryOption) ([]T, error) {
+	for _, column := range []string{latColumn, lngColumn} {
+		if err := validateFieldName(column); err != nil {
+			return nil, err
+		}
+		if !isFieldInModel[T](column) {
+			return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, column)
+		}
+	}
+
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := WithinBoundingBox(db, latColumn, lngColumn, box)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []T
+	result := query.Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return records, nil
+}
+
+// GetRecordsWithinRadius gets records from the database within radius
+// meters of center, nearest first. location returns a record's coordinates.
+func GetRecordsWithinRadius[T any](db *gorm.DB, latColumn, lngColumn string, center geo.Point, radius float64, location func(T) geo.Point, opts ...QueryOption) ([]T, error) {
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+	if radius <= 0 {
+		return nil, fmt.Errorf("radius must be positive, got %v", radius)
+	}
+
+	candidates, err := GetRecordsInBoundingBox[T](db, latColumn, lngColumn, geo.BoundingBoxAround(center, radius), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The box's corners lie outside the circle, so check exact distances
+	type match struct {
+		record   T
+		distance float64
+	}
+	matches := make([]match, 0, len(candidates))
+	for _, record := range candidates {
+		if distance := geo.Distance(center, location(record)); distance <= radius {
+			matches = append(matches, match{record: record, distance: distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	records := make([]T, len(matches))
+	for i, m := range matches {
+		records[i] = m.record
+	}
+	return records, nil
+}
+
+// GetRecordsInBoundingBoxCtx gets records whose coordinates lie inside box using ctx.
+func GetRecordsInBoundingBoxCtx[T any](ctx context.Context, db *gorm.DB, latColumn, lngColumn string, box geo.BoundingBox, opts ...QueryOption) ([]T, error) {
+	return GetRecordsInBoundingBox[T](db.WithContext(ctx), latColumn, lngColumn, box, opts...)
+}
+
+// GetRecordsWithinRadiusCtx gets records within radius meters of center using ctx.
+func GetRecordsWithinRadiusCtx[T any](ctx context.Context, db *gorm.DB, latColumn, lngColumn string, center geo.Point, radius float64, location func(T) geo.Point, opts ...QueryOption) ([]T, error) {
+	return GetRecordsWithinRadius(db.WithContext(ctx), latColumn, lngColumn, center, radius, location, opts...)
+}
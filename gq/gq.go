@@ -8,15 +8,17 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/arbenlabs/stoner/pagination"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 )
 
 // **************************************************
@@ -26,7 +28,7 @@ import (
 
 // Constants for validation
 const (
-	MaxPageSize     = 1000
+	MaxPageSize     = pagination.MaxPageSize
 	MaxBatchSize    = 1000
 	MaxFieldLength  = 100
 	MaxOrderByItems = 5
@@ -36,15 +38,21 @@ const (
 var (
 	ErrInvalidFieldName  = errors.New("invalid field name")
 	ErrInvalidOrderBy    = errors.New("invalid order by clause")
-	ErrInvalidPagination = errors.New("invalid pagination")
+	ErrInvalidPagination = pagination.ErrInvalidPagination
+	ErrInvalidCursor     = pagination.ErrInvalidCursor
 	ErrInvalidBatchSize  = errors.New("invalid batch size")
 	ErrEmptyFilterValue  = errors.New("empty filter value")
+	ErrInvalidFilter     = errors.New("invalid filter")
+	ErrInvalidKey        = errors.New("invalid primary key")
 	ErrFieldNotFound     = errors.New("field not found")
 )
 
 // fieldNameRegex validates field names to prevent SQL injection
 var fieldNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
 
+// defaultNamingStrategy is GORM's default mapping of fields to columns
+var defaultNamingStrategy = schema.NamingStrategy{}
+
 // orderByRegex validates ORDER BY clauses
 var orderByRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*(\s+(ASC|DESC|asc|desc))?(\s*,\s*[a-zA-Z][a-zA-Z0-9_]*(\s+(ASC|DESC|asc|desc))?)*$`)
 
@@ -84,15 +92,7 @@ func validateOrderBy(orderBy string) error {
 
 // validatePagination checks pagination parameters
 func validatePagination(page, pageSize int) error {
-	if page < 1 {
-		return fmt.Errorf("%w: page must be >= 1", ErrInvalidPagination)
-	}
-
-	if pageSize < 1 || pageSize > MaxPageSize {
-		return fmt.Errorf("%w: page size must be between 1 and %d", ErrInvalidPagination, MaxPageSize)
-	}
-
-	return nil
+	return pagination.NewPageRequest(page, pageSize).Validate()
 }
 
 // validateBatchSize checks batch operation size
@@ -156,6 +156,12 @@ func isFieldInModel[T any](fieldName string) bool {
 		if pascalToSnakeCase(field.Name) == fieldName {
 			return true
 		}
+
+		// Check GORM's default column name, which keeps initialisms
+		// together: CustomerID is customer_id
+		if defaultNamingStrategy.ColumnName("", field.Name) == fieldName {
+			return true
+		}
 	}
 
 	return false
@@ -184,32 +190,6 @@ func pascalToSnakeCase(s string) string {
 	return strings.ToLower(string(result))
 }
 
-// validateFilterValue checks filter values for special cases
-func validateFilterValue(field string, value interface{}) error {
-	if value == nil {
-		return nil
-	}
-
-	// Special validation for price and pct_remaining fields
-	if field == "price" || field == "pct_remaining" {
-		str, ok := value.(string)
-		if !ok {
-			return fmt.Errorf("value for %s must be a string", field)
-		}
-
-		if len(str) == 0 {
-			return fmt.Errorf("%w: %s value cannot be empty", ErrEmptyFilterValue, field)
-		}
-
-		// Check if it has the expected format (number followed by + or -)
-		if len(str) < 2 {
-			return fmt.Errorf("invalid %s format: must be number followed by + or -", field)
-		}
-	}
-
-	return nil
-}
-
 // InsertRecord inserts a record into the database.
 func InsertRecord[T any](db *gorm.DB, record T) (*T, error) {
 	result := db.Create(&record)
@@ -236,7 +216,12 @@ func BatchInsert[T any](db *gorm.DB, records []T, batchSize int) error {
 }
 
 // GetAllRecords gets all records from the database.
-func GetAllRecords[T any](db *gorm.DB, page, pageSize int) ([]T, int, error) {
+func GetAllRecords[T any](db *gorm.DB, page, pageSize int, opts ...QueryOption) ([]T, int, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if err := validatePagination(page, pageSize); err != nil {
 		return nil, 0, err
 	}
@@ -257,13 +242,18 @@ func GetAllRecords[T any](db *gorm.DB, page, pageSize int) ([]T, int, error) {
 	}
 
 	// Calculate the total number of pages
-	totalPages := int((totalRecords + int64(pageSize) - 1) / int64(pageSize))
+	totalPages := pagination.TotalPages(totalRecords, pageSize)
 
 	return records, totalPages, nil
 }
 
 // GetRecordByID gets a record from the database by ID.
-func GetRecordByID[T any](db *gorm.DB, id string) (*T, error) {
+func GetRecordByID[T any](db *gorm.DB, id string, opts ...QueryOption) (*T, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	var record T
 	result := db.Where("id = ?", id).First(&record)
 	if result.Error != nil {
@@ -273,7 +263,12 @@ func GetRecordByID[T any](db *gorm.DB, id string) (*T, error) {
 }
 
 // GetRecordByField gets a record from the database by field.
-func GetRecordByField[T any](db *gorm.DB, fieldName string, fieldValue interface{}) (*T, error) {
+func GetRecordByField[T any](db *gorm.DB, fieldName string, fieldValue interface{}, opts ...QueryOption) (*T, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := validateFieldName(fieldName); err != nil {
 		return nil, err
 	}
@@ -297,7 +292,12 @@ func GetRecordByField[T any](db *gorm.DB, fieldName string, fieldValue interface
 }
 
 // LockAndGetRecordByField gets a record from the database by field and locks the record.
-func LockAndGetRecordByField[T any](db *gorm.DB, field string, value interface{}) (*T, error) {
+func LockAndGetRecordByField[T any](db *gorm.DB, field string, value interface{}, opts ...QueryOption) (*T, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	var record T
 	result := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where(fmt.Sprintf("%s = ?", field), value).First(&record)
 	if result.Error != nil {
@@ -307,7 +307,12 @@ func LockAndGetRecordByField[T any](db *gorm.DB, field string, value interface{}
 }
 
 // GetRecordsByField gets records from the database by field.
-func GetRecordsByField[T any](db *gorm.DB, field string, value interface{}, page, pageSize int, orderBy string) ([]T, int64, error) {
+func GetRecordsByField[T any](db *gorm.DB, field string, value interface{}, page, pageSize int, orderBy string, opts ...QueryOption) ([]T, int64, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if err := validateFieldName(field); err != nil {
 		return nil, 0, err
 	}
@@ -352,30 +357,25 @@ func GetRecordsByField[T any](db *gorm.DB, field string, value interface{}, page
 	return records, totalCount, nil
 }
 
-// GetRecordsByFields gets records from the database by fields.
-func GetRecordsByFields[T any](db *gorm.DB, conditions map[string]interface{}) ([]T, error) {
-	if len(conditions) == 0 {
-		return nil, fmt.Errorf("conditions cannot be empty")
+// GetRecordsByFields gets records from the database by fields. Slice
+// values match any of their elements and NotIn values exclude theirs;
+// use the Where option for OR groups.
+func GetRecordsByFields[T any](db *gorm.DB, conditions map[string]interface{}, opts ...QueryOption) ([]T, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate all field names first
-	for field := range conditions {
-		if err := validateFieldName(field); err != nil {
-			return nil, fmt.Errorf("invalid field '%s': %w", field, err)
-		}
-
-		if !isFieldInModel[T](field) {
-			return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
-		}
+	if len(conditions) == 0 && !hasConditions(opts) {
+		return nil, fmt.Errorf("conditions cannot be empty")
 	}
 
-	var records []T
-
-	query := db
-	for field, value := range conditions {
-		query = query.Where(field+" = ?", value)
+	query, err := applyConditions[T](db, conditions)
+	if err != nil {
+		return nil, err
 	}
 
+	var records []T
 	result := query.Find(&records)
 
 	if result.Error != nil {
@@ -385,95 +385,85 @@ func GetRecordsByFields[T any](db *gorm.DB, conditions map[string]interface{}) (
 	return records, nil
 }
 
-// GetFilteredPaginatedRecords gets filtered paginated records from the database.
-func GetFilteredPaginatedRecords[T any](db *gorm.DB, page, pageSize int, conditions map[string]interface{}) ([]T, int, error) {
-	if err := validatePagination(page, pageSize); err != nil {
-		return nil, 0, err
+// CountRecords counts the records in the database matching conditions,
+// or every record when conditions is empty.
+func CountRecords[T any](db *gorm.DB, conditions map[string]interface{}, opts ...QueryOption) (int64, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return 0, err
 	}
 
-	if len(conditions) == 0 {
-		return nil, 0, fmt.Errorf("conditions cannot be empty")
+	query, err := applyConditions[T](db.Model(new(T)), conditions)
+	if err != nil {
+		return 0, err
 	}
 
-	// Validate all field names and values first
-	for field, value := range conditions {
-		if err := validateFieldName(field); err != nil {
-			return nil, 0, fmt.Errorf("invalid field '%s': %w", field, err)
-		}
-
-		if !isFieldInModel[T](field) {
-			return nil, 0, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
-		}
-
-		if err := validateFilterValue(field, value); err != nil {
-			return nil, 0, fmt.Errorf("invalid value for field '%s': %w", field, err)
-		}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
 	}
+	return count, nil
+}
 
-	var records []T
-	var totalRecords int64
-
-	query := db.Model(new(T)) // Apply model to the query for proper counting
-
-	// Apply conditions dynamically
-	for field, value := range conditions {
-		if field == "price" {
-			str := value.(string) // Already validated above
-
-			// Safe to access since we validated length above
-			lastChar := str[len(str)-1]
-			priceStr := str[:len(str)-1] // Remove last character for conversion
-
-			price, err := strconv.Atoi(priceStr)
-			if err != nil {
-				return nil, 0, fmt.Errorf("invalid price value: %v", err)
-			}
-
-			if string(lastChar) == "-" {
-				query = query.Where(field+" <= ?", price)
-				continue
-			}
+// RecordExists checks if a record exists in the database by field, with
+// SELECT 1 ... LIMIT 1 instead of loading the record.
+func RecordExists[T any](db *gorm.DB, field string, value interface{}, opts ...QueryOption) (bool, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return false, err
+	}
 
-			if string(lastChar) == "+" {
-				query = query.Where(field+" >= ?", price)
-				continue
-			}
-		}
+	if err := validateFieldName(field); err != nil {
+		return false, err
+	}
 
-		if field == "pct_remaining" {
-			str := value.(string) // Already validated above
+	if !isFieldInModel[T](field) {
+		return false, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
+	}
 
-			// Safe to access since we validated length above
-			lastChar := str[len(str)-1]
-			pctStr := str[:len(str)-1] // Remove last character for conversion
+	var found int
+	result := db.Model(new(T)).Select("1").Where(field+" = ?", value).Limit(1).Scan(&found)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
 
-			pctRemaining, err := strconv.ParseFloat(pctStr, 64)
-			if err != nil {
-				return nil, 0, fmt.Errorf("invalid pct_remaining value: %v", err)
-			}
+// GetFilteredPaginatedRecords gets filtered paginated records from the database.
+// The conditions are ANDed: values are matched by equality, slices with IN,
+// and Filter values by their operator, e.g. {"price": Filter{Op: OpLte,
+// Value: 100}}. Use the Where option for OR groups, in which case
+// conditions may be empty.
+func GetFilteredPaginatedRecords[T any](db *gorm.DB, page, pageSize int, conditions map[string]interface{}, opts ...QueryOption) ([]T, int, error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return nil, 0, err
+	}
 
-			if string(lastChar) == "-" {
-				query = query.Where(field+" <= ?", pctRemaining)
-				continue
-			}
+	if err := validatePagination(page, pageSize); err != nil {
+		return nil, 0, err
+	}
 
-			if string(lastChar) == "+" {
-				query = query.Where(field+" >= ?", pctRemaining)
-				continue
-			}
-		}
+	if len(conditions) == 0 && !hasConditions(opts) {
+		return nil, 0, fmt.Errorf("conditions cannot be empty")
+	}
 
-		// Default condition for equality
-		query = query.Where(field+" = ?", value)
+	// Apply model to the query for proper counting
+	query, err := applyConditions[T](db.Model(new(T)), conditions)
+	if err != nil {
+		return nil, 0, err
 	}
 
+	var records []T
+	var totalRecords int64
+
 	// Count total number of records after applying conditions
 	if err := query.Count(&totalRecords).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Calculate total pages
-	totalPages := int((totalRecords + int64(pageSize) - 1) / int64(pageSize))
+	totalPages := pagination.TotalPages(totalRecords, pageSize)
 
 	// Apply pagination
 	offset := (page - 1) * pageSize
@@ -523,6 +513,26 @@ func UpdateRecordByField[T any, U any](db *gorm.DB, field string, value interfac
 	return nil
 }
 
+// BulkUpdateByFields updates the records in the database matching
+// conditions and returns how many were updated. Conditions are validated
+// and matched as in GetRecordsByFields, and cannot be empty.
+func BulkUpdateByFields[T any, U any](db *gorm.DB, conditions map[string]interface{}, updates U) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, fmt.Errorf("conditions cannot be empty")
+	}
+
+	query, err := applyConditions[T](db.Model(new(T)), conditions)
+	if err != nil {
+		return 0, err
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 // DeleteRecordByID deletes a record in the database by ID.
 func DeleteRecordByID[T any](db *gorm.DB, id string) error {
 	var record T
@@ -533,6 +543,78 @@ func DeleteRecordByID[T any](db *gorm.DB, id string) error {
 	return nil
 }
 
+// BulkDeleteByFields deletes the records in the database matching
+// conditions in one statement and returns how many were deleted.
+// Conditions are validated and matched as in GetRecordsByFields, and
+// cannot be empty.
+func BulkDeleteByFields[T any](db *gorm.DB, conditions map[string]interface{}) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, fmt.Errorf("conditions cannot be empty")
+	}
+
+	query, err := applyConditions[T](db, conditions)
+	if err != nil {
+		return 0, err
+	}
+
+	result := query.Delete(new(T))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteInBatches deletes the records in the database matching conditions
+// batchSize at a time, each batch in its own statement, so deleting from a
+// large table does not hold locks for long or build a huge transaction.
+// It returns how many records were deleted, including by the batches that
+// completed before an error.
+func DeleteInBatches[T any](db *gorm.DB, conditions map[string]interface{}, batchSize int) (int64, error) {
+	if err := validateBatchSize(batchSize); err != nil {
+		return 0, err
+	}
+	if len(conditions) == 0 {
+		return 0, fmt.Errorf("conditions cannot be empty")
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return 0, err
+	}
+	if len(stmt.Schema.PrimaryFieldDBNames) == 0 {
+		return 0, fmt.Errorf("%w: model %s has no primary key", ErrInvalidKey, stmt.Schema.Name)
+	}
+
+	query, err := applyConditions[T](db.Model(new(T)), conditions)
+	if err != nil {
+		return 0, err
+	}
+	query = query.Session(&gorm.Session{})
+
+	var deleted int64
+	for {
+		// Select only the keys of the next batch, then delete by key
+		var batch []T
+		if err := query.Select(stmt.Schema.PrimaryFieldDBNames).Limit(batchSize).Find(&batch).Error; err != nil {
+			return deleted, err
+		}
+		if len(batch) == 0 {
+			return deleted, nil
+		}
+
+		result := db.Delete(&batch)
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+
+		// A short batch is the last; a batch that deleted nothing would repeat
+		if len(batch) < batchSize || result.RowsAffected == 0 {
+			return deleted, nil
+		}
+	}
+}
+
 // StringMap is a custom type for handling map[string]string in GORM
 type StringMap map[string]string
 
@@ -600,13 +682,17 @@ type GormConfig struct {
 	ConnMaxLifetime int // in minutes
 	ConnMaxIdleTime int // in minutes
 	LogLevel        string
-	SlowThreshold   int // in milliseconds
+	SlowThreshold   int      // in milliseconds
+	ReplicaDSNs     []string // read replicas of DSN, with the same driver and pool settings
+	ReplicaPolicy   string   // "random" (default) or "round_robin"
 }
 
 // GormConnection represents a GORM connection wrapper
 type GormConnection struct {
 	DB     *gorm.DB
 	Config *GormConfig
+
+	resolver *dbresolver.DBResolver // routes reads to ReplicaDSNs
 }
 
 // NewGormConnection creates a new GORM connection
@@ -642,10 +728,26 @@ func NewGormConnection(config *GormConfig) (*GormConnection, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &GormConnection{
+	gc := &GormConnection{
 		DB:     db,
 		Config: config,
-	}, nil
+	}
+
+	// Route reads to replicas
+	if len(config.ReplicaDSNs) > 0 {
+		if gc.resolver, err = registerReplicas(db, config); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		for i, replica := range gc.replicaPools() {
+			if err := replica.Ping(); err != nil {
+				gc.Close()
+				return nil, fmt.Errorf("failed to ping replica %d: %w", i, err)
+			}
+		}
+	}
+
+	return gc, nil
 }
 
 // AutoMigrate performs auto-migration for the given models
@@ -727,22 +829,33 @@ func (gc *GormConnection) HasIndex(model interface{}, name string) bool {
 	return gc.DB.Migrator().HasIndex(model, name)
 }
 
-// Close closes the database connection
+// Close closes the database connection and those of the replicas
 func (gc *GormConnection) Close() error {
 	sqlDB, err := gc.DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
+	for _, replica := range gc.replicaPools() {
+		replica.Close()
+	}
 	return sqlDB.Close()
 }
 
-// Ping tests the database connection
+// Ping tests the database connection and those of the replicas
 func (gc *GormConnection) Ping() error {
 	sqlDB, err := gc.DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
-	return sqlDB.Ping()
+	if err := sqlDB.Ping(); err != nil {
+		return err
+	}
+	for i, replica := range gc.replicaPools() {
+		if err := replica.Ping(); err != nil {
+			return fmt.Errorf("replica %d: %w", i, err)
+		}
+	}
+	return nil
 }
 
 // Stats returns database connection statistics
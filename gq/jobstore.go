@@ -0,0 +1,169 @@
+package gq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	stonertime "github.com/arbenlabs/stoner/time"
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Cron Job Store
+// CronJobStore persists cron jobs for the time package scheduler so that
+// multiple replicas can share a schedule. Runs are claimed with a
+// conditional UPDATE, so exactly one replica executes each run.
+// --------------------------------------------------
+// **************************************************
+
+// CronJobRecord is the GORM model backing CronJobStore
+type CronJobRecord struct {
+	ID          string     `gorm:"column:id;primaryKey;size:255" json:"id"`
+	Schedule    string     `gorm:"column:schedule;size:255;not null" json:"schedule"`
+	LastRun     *time.Time `gorm:"column:last_run" json:"last_run,omitempty"`
+	NextRun     time.Time  `gorm:"column:next_run;not null;index" json:"next_run"`
+	LastError   string     `gorm:"column:last_error;type:text" json:"last_error,omitempty"`
+	LockedBy    string     `gorm:"column:locked_by;size:255" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `gorm:"column:locked_until" json:"locked_until,omitempty"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for cron job records
+func (CronJobRecord) TableName() string {
+	return "cron_jobs"
+}
+
+// toJobRecord converts the model to the time package representation
+func (r CronJobRecord) toJobRecord() stonertime.JobRecord {
+	record := stonertime.JobRecord{
+		ID:        r.ID,
+		Schedule:  r.Schedule,
+		NextRun:   r.NextRun,
+		LastError: r.LastError,
+		LockedBy:  r.LockedBy,
+	}
+	if r.LastRun != nil {
+		record.LastRun = *r.LastRun
+	}
+	if r.LockedUntil != nil {
+		record.LockedUntil = *r.LockedUntil
+	}
+	return record
+}
+
+// CronJobStore is a GORM-backed implementation of time.JobStore
+type CronJobStore struct {
+	db *gorm.DB
+}
+
+// NewCronJobStore creates a new cron job store
+func NewCronJobStore(db *gorm.DB) *CronJobStore {
+	return &CronJobStore{db: db}
+}
+
+// AutoMigrate creates or updates the cron_jobs table
+func (s *CronJobStore) AutoMigrate() error {
+	if err := s.db.AutoMigrate(&CronJobRecord{}); err != nil {
+		return fmt.Errorf("auto-migration failed: %w", err)
+	}
+	return nil
+}
+
+// SaveJob creates a job record or updates its schedule, preserving run state
+func (s *CronJobStore) SaveJob(ctx context.Context, record stonertime.JobRecord) (stonertime.JobRecord, error) {
+	var saved CronJobRecord
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", record.ID).Limit(1).Find(&saved)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			saved = CronJobRecord{
+				ID:       record.ID,
+				Schedule: record.Schedule,
+				NextRun:  record.NextRun,
+			}
+			return tx.Create(&saved).Error
+		}
+
+		if saved.Schedule == record.Schedule {
+			return nil
+		}
+
+		saved.Schedule = record.Schedule
+		saved.NextRun = record.NextRun
+		return tx.Model(&CronJobRecord{}).Where("id = ?", record.ID).Updates(map[string]interface{}{
+			"schedule": saved.Schedule,
+			"next_run": saved.NextRun,
+		}).Error
+	})
+	if err != nil {
+		return stonertime.JobRecord{}, fmt.Errorf("failed to save cron job %s: %w", record.ID, err)
+	}
+
+	return saved.toJobRecord(), nil
+}
+
+// ClaimRun claims the run of jobID scheduled at scheduledAt for owner
+func (s *CronJobStore) ClaimRun(ctx context.Context, jobID, owner string, scheduledAt, nextRun time.Time, lease time.Duration) (bool, error) {
+	now := time.Now()
+
+	updates := map[string]interface{}{
+		"last_run": scheduledAt,
+		"next_run": nextRun,
+	}
+
+	query := s.db.WithContext(ctx).Model(&CronJobRecord{}).
+		Where("id = ? AND next_run <= ?", jobID, scheduledAt)
+
+	if lease > 0 {
+		query = query.Where("locked_until IS NULL OR locked_until <= ?", now)
+		updates["locked_by"] = owner
+		updates["locked_until"] = now.Add(lease)
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim cron job %s: %w", jobID, result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// CompleteRun records a run's outcome and releases owner's lock
+func (s *CronJobStore) CompleteRun(ctx context.Context, jobID, owner string, finishedAt time.Time, runErr error) error {
+	lastError := ""
+	if runErr != nil {
+		lastError = runErr.Error()
+	}
+
+	db := s.db.WithContext(ctx)
+
+	if err := db.Model(&CronJobRecord{}).Where("id = ?", jobID).
+		Update("last_error", lastError).Error; err != nil {
+		return fmt.Errorf("failed to record cron job %s result: %w", jobID, err)
+	}
+
+	if err := db.Model(&CronJobRecord{}).Where("id = ? AND locked_by = ?", jobID, owner).
+		Updates(map[string]interface{}{
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to release cron job %s: %w", jobID, err)
+	}
+
+	return nil
+}
+
+// GetCronJob returns the stored record for a job
+func (s *CronJobStore) GetCronJob(ctx context.Context, jobID string) (*CronJobRecord, error) {
+	var record CronJobRecord
+	if err := s.db.WithContext(ctx).Where("id = ?", jobID).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,81 @@
+package gq
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Joins
+// WithJoin filters a read helper's model by a related model, e.g.
+// orders whose customer is in a given country. The join is a
+// semi-join, orders.customer_id IN (SELECT customers.id FROM
+// customers WHERE ...), so a to-many relation never duplicates
+// records and the existing helpers' unqualified columns stay
+// unambiguous. Columns of both models are validated.
+// --------------------------------------------------
+// **************************************************
+
+// join is a WithJoin filter
+type join struct {
+	localColumn string
+	subquery    func(db *gorm.DB) (*gorm.DB, error)
+}
+
+// WithJoin keeps the records whose localColumn matches foreignColumn of a
+// record of model R that matches conditions, e.g.
+//
+//	gq.GetAllRecords[Order](db, 1, 20, gq.WithJoin[Customer]("customer_id", "id",
+//		gq.Eq("country", "NL"), gq.Eq("active", true)))
+//
+// R's default scopes and soft delete apply to the related records.
+func WithJoin[R any](localColumn, foreignColumn string, conditions ...Condition) QueryOption {
+	j := join{
+		localColumn: localColumn,
+		subquery: func(db *gorm.DB) (*gorm.DB, error) {
+			if err := validateFieldName(foreignColumn); err != nil {
+				return nil, fmt.Errorf("invalid join field '%s': %w", foreignColumn, err)
+			}
+			if !isFieldInModel[R](foreignColumn) {
+				return nil, fmt.Errorf("%w: join field '%s' not found in related model", ErrFieldNotFound, foreignColumn)
+			}
+
+			sub := db.Session(&gorm.Session{NewDB: true}).Model(new(R)).
+				Select("?", clause.Column{Table: clause.CurrentTable, Name: foreignColumn})
+			sub = applyDefaultScopes[R](sub, queryOptions{})
+			for _, condition := range conditions {
+				expr, err := conditionExpression[R](condition)
+				if err != nil {
+					return nil, err
+				}
+				sub = sub.Where(expr)
+			}
+			return sub, nil
+		},
+	}
+	return func(o *queryOptions) {
+		o.joins = append(o.joins, j)
+	}
+}
+
+// applyJoins adds the WithJoin filters to a query for model T
+func applyJoins[T any](db *gorm.DB, joins []join) (*gorm.DB, error) {
+	for _, j := range joins {
+		if err := validateFieldName(j.localColumn); err != nil {
+			return nil, fmt.Errorf("invalid join field '%s': %w", j.localColumn, err)
+		}
+		if !isFieldInModel[T](j.localColumn) {
+			return nil, fmt.Errorf("%w: join field '%s' not found in model", ErrFieldNotFound, j.localColumn)
+		}
+
+		sub, err := j.subquery(db)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("? IN (?)", clause.Column{Table: clause.CurrentTable, Name: j.localColumn}, sub)
+	}
+	return db, nil
+}
@@ -0,0 +1,172 @@
+package gq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/arbenlabs/stoner/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Keyset Pagination
+// GetRecordsAfterCursor pages by the last seen sort key instead of
+// OFFSET, so each page costs the same however deep it is. Unlike
+// PaginateByCursor the sort column need not be unique, as the
+// primary key breaks ties, and it may be descending. Cursors are
+// signed with a pagination.CursorCodec.
+// --------------------------------------------------
+// **************************************************
+
+// keysetCursor is the position after the last record of a page
+type keysetCursor struct {
+	Value json.RawMessage `json:"v"`            // sort column value
+	ID    json.RawMessage `json:"id,omitempty"` // primary key, when sorting by another column
+}
+
+// GetRecordsAfterCursor returns the page of records after req.Cursor,
+// ordered by orderBy ("created_at" or "created_at DESC"). The position of
+// the last record is signed into the next cursor with codec; it is empty
+// on the last page. Leave req.Cursor empty for the first page.
+func GetRecordsAfterCursor[T any](db *gorm.DB, codec *pagination.CursorCodec, req pagination.PageRequest, orderBy string, opts ...QueryOption) (pagination.PageResponse[T], error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if err := req.Validate(); err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	column, desc, err := parseKeysetOrder(orderBy)
+	if err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+	sortField := stmt.Schema.LookUpField(column)
+	if sortField == nil || sortField.DBName == "" {
+		return pagination.PageResponse[T]{}, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, column)
+	}
+	idField := stmt.Schema.PrioritizedPrimaryField
+	if idField == nil {
+		return pagination.PageResponse[T]{}, fmt.Errorf("%w: model %s has no primary key", ErrFieldNotFound, stmt.Schema.Name)
+	}
+	tieBreak := idField.DBName != sortField.DBName
+
+	query := db.Order(clause.OrderByColumn{Column: clause.Column{Name: sortField.DBName}, Desc: desc})
+	if tieBreak {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: idField.DBName}, Desc: desc})
+	}
+
+	if req.Cursor != "" {
+		var position keysetCursor
+		if err := codec.Decode(req.Cursor, &position); err != nil {
+			return pagination.PageResponse[T]{}, err
+		}
+		value, err := decodeCursorValue(position.Value, sortField)
+		if err != nil {
+			return pagination.PageResponse[T]{}, err
+		}
+
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		sortColumn := stmt.Quote(clause.Column{Table: clause.CurrentTable, Name: sortField.DBName})
+		if tieBreak {
+			id, err := decodeCursorValue(position.ID, idField)
+			if err != nil {
+				return pagination.PageResponse[T]{}, err
+			}
+			idColumn := stmt.Quote(clause.Column{Table: clause.CurrentTable, Name: idField.DBName})
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", sortColumn, op, sortColumn, idColumn, op), value, value, id)
+		} else {
+			query = query.Where(fmt.Sprintf("%s %s ?", sortColumn, op), value)
+		}
+	}
+
+	// Fetch one extra record to learn whether there is a next page
+	var records []T
+	if err := query.Limit(req.PageSize + 1).Find(&records).Error; err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+	if len(records) <= req.PageSize {
+		return pagination.NewCursorResponse(records, req, ""), nil
+	}
+	records = records[:req.PageSize]
+
+	last := reflect.ValueOf(&records[len(records)-1]).Elem()
+	var next keysetCursor
+	if next.Value, err = encodeCursorValue(db, sortField, last); err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+	if tieBreak {
+		if next.ID, err = encodeCursorValue(db, idField, last); err != nil {
+			return pagination.PageResponse[T]{}, err
+		}
+	}
+	nextCursor, err := codec.Encode(next)
+	if err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+	return pagination.NewCursorResponse(records, req, nextCursor), nil
+}
+
+// GetRecordsAfterCursorCtx returns the page of records after req.Cursor using ctx.
+func GetRecordsAfterCursorCtx[T any](ctx context.Context, db *gorm.DB, codec *pagination.CursorCodec, req pagination.PageRequest, orderBy string, opts ...QueryOption) (pagination.PageResponse[T], error) {
+	return GetRecordsAfterCursor[T](db.WithContext(ctx), codec, req, orderBy, opts...)
+}
+
+// parseKeysetOrder parses "column" or "column ASC|DESC"
+func parseKeysetOrder(orderBy string) (string, bool, error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) == 0 || len(parts) > 2 {
+		return "", false, fmt.Errorf("%w: keyset pagination needs one sort column", ErrInvalidOrderBy)
+	}
+	if err := validateFieldName(parts[0]); err != nil {
+		return "", false, err
+	}
+	if len(parts) == 1 {
+		return parts[0], false, nil
+	}
+	switch strings.ToUpper(parts[1]) {
+	case "ASC":
+		return parts[0], false, nil
+	case "DESC":
+		return parts[0], true, nil
+	default:
+		return "", false, fmt.Errorf("%w: invalid direction %q", ErrInvalidOrderBy, parts[1])
+	}
+}
+
+// encodeCursorValue reads a field of a record as JSON
+func encodeCursorValue(db *gorm.DB, field *schema.Field, record reflect.Value) (json.RawMessage, error) {
+	value, _ := field.ValueOf(db.Statement.Context, record)
+	return json.Marshal(value)
+}
+
+// decodeCursorValue decodes a cursor value into the field's Go type, so
+// times and numbers are compared as their column type
+func decodeCursorValue(raw json.RawMessage, field *schema.Field) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidCursor, field.DBName)
+	}
+	value := reflect.New(field.FieldType)
+	if err := json.Unmarshal(raw, value.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return value.Elem().Interface(), nil
+}
@@ -0,0 +1,90 @@
+package gq
+
+import (
+	"fmt"
+
+	"github.com/arbenlabs/stoner/pagination"
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Pagination
+// Paginate serves offset pages of any query; PaginateByCursor
+// serves keyset pages, which stay fast and stable on large or
+// frequently changing tables.
+// --------------------------------------------------
+// **************************************************
+
+// Paginate returns an offset page of the records matched by db, which may
+// already carry conditions and ordering
+func Paginate[T any](db *gorm.DB, req pagination.PageRequest, opts ...QueryOption) (pagination.PageResponse[T], error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	var totalRecords int64
+	if err := db.Model(new(T)).Count(&totalRecords).Error; err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	var records []T
+	if err := db.Offset(req.Offset()).Limit(req.Limit()).Find(&records).Error; err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	return pagination.NewPageResponse(records, req, totalRecords), nil
+}
+
+// PaginateByCursor returns the page of records after req.Cursor, ordered by
+// column ascending. column must be unique and key must return its value for
+// a record; the last record's key is signed into the next cursor with codec.
+// GetRecordsAfterCursor pages by columns that are not unique or descending.
+func PaginateByCursor[T any, K any](db *gorm.DB, codec *pagination.CursorCodec, req pagination.PageRequest, column string, key func(T) K, opts ...QueryOption) (pagination.PageResponse[T], error) {
+	db, err := applyQueryOptions[T](db, opts)
+	if err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if err := req.Validate(); err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+	if err := validateFieldName(column); err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	query := db.Order(column + " ASC")
+	if req.Cursor != "" {
+		var after K
+		if err := codec.Decode(req.Cursor, &after); err != nil {
+			return pagination.PageResponse[T]{}, err
+		}
+		query = query.Where(fmt.Sprintf("%s > ?", column), after)
+	}
+
+	// Fetch one extra record to learn whether there is a next page
+	var records []T
+	if err := query.Limit(req.PageSize + 1).Find(&records).Error; err != nil {
+		return pagination.PageResponse[T]{}, err
+	}
+
+	nextCursor := ""
+	if len(records) > req.PageSize {
+		records = records[:req.PageSize]
+		cursor, err := codec.Encode(key(records[len(records)-1]))
+		if err != nil {
+			return pagination.PageResponse[T]{}, err
+		}
+		nextCursor = cursor
+	}
+
+	return pagination.NewCursorResponse(records, req, nextCursor), nil
+}
@@ -0,0 +1,323 @@
+package gq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Time Partitioning
+// Partitioner splits a high-volume time-series model, such as
+// events, into daily or monthly tables on Postgres. With native
+// partitioning the model's table is a partitioned parent and
+// inserts are routed by Postgres; with child tables each period
+// is a copy of the parent (LIKE ... INCLUDING ALL) with a CHECK
+// constraint, and the Partitioner routes inserts and queries.
+// Partitions are created on demand by Insert, or ahead of time
+// with EnsurePartitions from a scheduled job.
+// --------------------------------------------------
+// **************************************************
+
+// ErrPartitioningUnsupported is returned for databases other than Postgres
+var ErrPartitioningUnsupported = errors.New("table partitioning requires postgres")
+
+// PartitionInterval is the period covered by one partition
+type PartitionInterval int
+
+const (
+	// PartitionMonthly creates one partition per calendar month, e.g. events_2026_10
+	PartitionMonthly PartitionInterval = iota
+	// PartitionDaily creates one partition per day, e.g. events_2026_10_18
+	PartitionDaily
+)
+
+// PartitionMode selects how partitions are implemented
+type PartitionMode int
+
+const (
+	// NativePartitions uses declarative partitioning: PARTITION OF the parent
+	NativePartitions PartitionMode = iota
+	// ChildTables uses plain tables cloned from the parent
+	ChildTables
+)
+
+// Partitioner manages the time partitions of model T. Partition bounds are
+// computed in UTC.
+type Partitioner[T any] struct {
+	db       *gorm.DB
+	table    string
+	field    *schema.Field
+	interval PartitionInterval
+	mode     PartitionMode
+}
+
+// NewPartitioner creates a partitioner for model T, partitioned by the
+// time column column
+func NewPartitioner[T any](db *gorm.DB, column string, interval PartitionInterval, mode PartitionMode) (*Partitioner[T], error) {
+	if db.Dialector.Name() != "postgres" {
+		return nil, ErrPartitioningUnsupported
+	}
+	if interval != PartitionMonthly && interval != PartitionDaily {
+		return nil, fmt.Errorf("unknown partition interval %d", interval)
+	}
+	if mode != NativePartitions && mode != ChildTables {
+		return nil, fmt.Errorf("unknown partition mode %d", mode)
+	}
+	if err := validateFieldName(column); err != nil {
+		return nil, err
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	field := stmt.Schema.LookUpField(column)
+	if field == nil || field.DBName == "" {
+		return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, column)
+	}
+	if field.FieldType != reflect.TypeOf(time.Time{}) && field.FieldType != reflect.TypeOf(&time.Time{}) {
+		return nil, fmt.Errorf("partition column '%s' must be a time.Time, got %s", column, field.FieldType)
+	}
+
+	return &Partitioner[T]{
+		db:       db,
+		table:    stmt.Schema.Table,
+		field:    field,
+		interval: interval,
+		mode:     mode,
+	}, nil
+}
+
+// Bounds returns the half-open range [from, to) of the partition holding t
+func (p *Partitioner[T]) Bounds(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	if p.interval == PartitionDaily {
+		from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 0, 1)
+	}
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, 0)
+}
+
+// PartitionName returns the table name of the partition holding t
+func (p *Partitioner[T]) PartitionName(t time.Time) string {
+	t = t.UTC()
+	if p.interval == PartitionDaily {
+		return fmt.Sprintf("%s_%s", p.table, t.Format("2006_01_02"))
+	}
+	return fmt.Sprintf("%s_%s", p.table, t.Format("2006_01"))
+}
+
+// CreateParent creates the partitioned parent table of a model for native
+// partitions. Postgres requires the partition column to be part of the
+// primary key and of every unique index.
+func (p *Partitioner[T]) CreateParent(ctx context.Context) error {
+	if p.mode != NativePartitions {
+		return p.db.WithContext(ctx).Migrator().AutoMigrate(new(T))
+	}
+	if p.db.WithContext(ctx).Migrator().HasTable(p.table) {
+		return nil
+	}
+	options := fmt.Sprintf("PARTITION BY RANGE (%s)", p.db.Statement.Quote(p.field.DBName))
+	return p.db.WithContext(ctx).Set("gorm:table_options", options).Migrator().CreateTable(new(T))
+}
+
+// EnsurePartition creates the partition holding t if it does not exist
+func (p *Partitioner[T]) EnsurePartition(ctx context.Context, t time.Time) error {
+	from, to := p.Bounds(t)
+	name := p.PartitionName(t)
+	quote := p.db.Statement.Quote
+
+	// DDL cannot take bind parameters; the bounds are formatted here
+	var sql string
+	if p.mode == NativePartitions {
+		sql = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%s) TO (%s)",
+			quote(name), quote(p.table), timestampLiteral(from), timestampLiteral(to))
+	} else {
+		sql = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING ALL, CHECK (%s >= %s AND %s < %s))",
+			quote(name), quote(p.table),
+			quote(p.field.DBName), timestampLiteral(from), quote(p.field.DBName), timestampLiteral(to))
+	}
+	return p.db.WithContext(ctx).Exec(sql).Error
+}
+
+// EnsurePartitions creates the partitions covering [from, to), e.g. the
+// next three months from a scheduled job
+func (p *Partitioner[T]) EnsurePartitions(ctx context.Context, from, to time.Time) error {
+	for start, _ := p.Bounds(from); start.Before(to); _, start = p.Bounds(start) {
+		if err := p.EnsurePartition(ctx, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Insert inserts records into their partitions, creating missing
+// partitions first
+func (p *Partitioner[T]) Insert(ctx context.Context, records []T, batchSize int) error {
+	if err := validateBatchSize(batchSize); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil // Nothing to insert
+	}
+
+	// Group the records by partition, keeping their order
+	var names []string
+	groups := make(map[string][]int)
+	for i, record := range records {
+		t, err := p.partitionTime(ctx, record)
+		if err != nil {
+			return err
+		}
+		name := p.PartitionName(t)
+		if _, ok := groups[name]; !ok {
+			if err := p.EnsurePartition(ctx, t); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], i)
+	}
+
+	db := p.db.WithContext(ctx)
+	if p.mode == NativePartitions {
+		// Postgres routes rows of the parent to their partitions
+		return db.CreateInBatches(records, batchSize).Error
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, name := range names {
+			indexes := groups[name]
+			group := make([]T, len(indexes))
+			for i, index := range indexes {
+				group[i] = records[index]
+			}
+			if err := tx.Table(name).CreateInBatches(&group, batchSize).Error; err != nil {
+				return err
+			}
+			// Copy generated keys back, as BatchInsert does
+			for i, index := range indexes {
+				records[index] = group[i]
+			}
+		}
+		return nil
+	})
+}
+
+// FindRange gets the records whose partition column is in [from, to),
+// ordered by it. Native partitions are pruned by Postgres; child tables
+// outside the range are not read.
+func (p *Partitioner[T]) FindRange(ctx context.Context, from, to time.Time, opts ...QueryOption) ([]T, error) {
+	db, err := applyQueryOptions[T](p.db.WithContext(ctx), opts)
+	if err != nil {
+		return nil, err
+	}
+	column := p.db.Statement.Quote(p.field.DBName)
+	where := fmt.Sprintf("%s >= ? AND %s < ?", column, column)
+
+	var records []T
+	if p.mode == NativePartitions {
+		err := db.Where(where, from, to).Order(p.field.DBName).Find(&records).Error
+		return records, err
+	}
+
+	for start, _ := p.Bounds(from); start.Before(to); _, start = p.Bounds(start) {
+		name := p.PartitionName(start)
+		if !p.db.WithContext(ctx).Migrator().HasTable(name) {
+			continue
+		}
+		var page []T
+		if err := db.Table(name).Where(where, from, to).Order(p.field.DBName).Find(&page).Error; err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+	}
+	return records, nil
+}
+
+// DropPartitionsBefore drops the partitions that end at or before t, for
+// retention. It returns the names of the dropped tables.
+func (p *Partitioner[T]) DropPartitionsBefore(ctx context.Context, t time.Time) ([]string, error) {
+	partitions, err := p.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, partition := range partitions {
+		if partition.To.After(t) {
+			continue
+		}
+		sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", p.db.Statement.Quote(partition.Name))
+		if err := p.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, partition.Name)
+	}
+	return dropped, nil
+}
+
+// Partition describes an existing partition
+type Partition struct {
+	Name string
+	From time.Time // inclusive
+	To   time.Time // exclusive
+}
+
+// Partitions lists the existing partitions of the model, oldest first.
+// Tables are recognized by their name, so other tables named like
+// partitions must not exist.
+func (p *Partitioner[T]) Partitions(ctx context.Context) ([]Partition, error) {
+	var names []string
+	err := p.db.WithContext(ctx).Raw(
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name LIKE ? ORDER BY table_name",
+		strings.ReplaceAll(p.table, "_", "\\_")+"\\_%",
+	).Scan(&names).Error
+	if err != nil {
+		return nil, err
+	}
+
+	layout := "2006_01"
+	if p.interval == PartitionDaily {
+		layout = "2006_01_02"
+	}
+	var partitions []Partition
+	for _, name := range names {
+		t, err := time.Parse(layout, name[len(p.table)+1:])
+		if err != nil {
+			continue // Not a partition of this interval
+		}
+		from, to := p.Bounds(t)
+		partitions = append(partitions, Partition{Name: name, From: from, To: to})
+	}
+	return partitions, nil
+}
+
+// partitionTime reads the partition column of a record
+func (p *Partitioner[T]) partitionTime(ctx context.Context, record T) (time.Time, error) {
+	value, zero := p.field.ValueOf(ctx, reflect.ValueOf(&record).Elem())
+	switch t := value.(type) {
+	case time.Time:
+		if !zero {
+			return t, nil
+		}
+	case *time.Time:
+		if t != nil {
+			return *t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("record has no %s to partition by", p.field.DBName)
+}
+
+// timestampLiteral formats t as a Postgres timestamp literal
+func timestampLiteral(t time.Time) string {
+	return "'" + t.UTC().Format("2006-01-02 15:04:05") + "+00'"
+}
@@ -0,0 +1,42 @@
+package gq
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Column Projection
+// WithSelect makes a read helper fetch only the given columns,
+// to cut the payload of wide tables. Other fields of the
+// returned records keep their zero values. Columns are validated
+// against the model; keyset and batched reads page by the
+// primary key, so it must be among them there.
+// --------------------------------------------------
+// **************************************************
+
+// WithSelect fetches only columns, e.g. gq.WithSelect("id", "name")
+func WithSelect(columns ...string) QueryOption {
+	return func(o *queryOptions) {
+		o.columns = append(o.columns, columns...)
+	}
+}
+
+// applySelect validates the WithSelect columns against model T and
+// selects them
+func applySelect[T any](db *gorm.DB, columns []string) (*gorm.DB, error) {
+	if len(columns) == 0 {
+		return db, nil
+	}
+	for _, column := range columns {
+		if err := validateFieldName(column); err != nil {
+			return nil, fmt.Errorf("invalid select field '%s': %w", column, err)
+		}
+		if !isFieldInModel[T](column) {
+			return nil, fmt.Errorf("%w: select field '%s' not found in model", ErrFieldNotFound, column)
+		}
+	}
+	return db.Select(columns), nil
+}
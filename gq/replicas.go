@@ -0,0 +1,120 @@
+package gq
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Read Replicas
+// With GormConfig.ReplicaDSNs set, reads from the generic
+// helpers (Find, First, Count, Rows and raw SELECTs) go to a
+// replica, while writes, locking reads and transactions go to
+// the primary. WithPrimary sends a read to the primary, e.g.
+// to read back a record just written despite replica lag.
+// --------------------------------------------------
+// **************************************************
+
+// Replica selection policies for GormConfig.ReplicaPolicy
+const (
+	ReplicaPolicyRandom     = "random"
+	ReplicaPolicyRoundRobin = "round_robin"
+)
+
+// WithPrimary reads from the primary instead of a replica. It has no
+// effect without replicas.
+func WithPrimary() QueryOption {
+	return func(o *queryOptions) {
+		o.primary = true
+	}
+}
+
+// Primary returns a DB instance whose reads also go to the primary
+func (gc *GormConnection) Primary() *gorm.DB {
+	return gc.DB.Clauses(dbresolver.Write)
+}
+
+// HasReplicas checks if reads are routed to replicas
+func (gc *GormConnection) HasReplicas() bool {
+	return gc.resolver != nil
+}
+
+// applyPrimary routes the reads of db to the primary
+func applyPrimary(db *gorm.DB, primary bool) *gorm.DB {
+	if !primary {
+		return db
+	}
+	return db.Clauses(dbresolver.Write)
+}
+
+// registerReplicas registers the replicas of config with db, with the same
+// driver and pool settings as the primary
+func registerReplicas(db *gorm.DB, config *GormConfig) (*dbresolver.DBResolver, error) {
+	policy, err := replicaPolicy(config.ReplicaPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(config.ReplicaDSNs))
+	for _, dsn := range config.ReplicaDSNs {
+		replicas = append(replicas, getDialector(config.Driver, dsn))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   policy,
+	})
+	if err := db.Use(resolver); err != nil {
+		return nil, fmt.Errorf("failed to connect to replicas: %w", err)
+	}
+
+	if config.MaxOpenConns > 0 {
+		resolver.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		resolver.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		resolver.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime) * time.Minute)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		resolver.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTime) * time.Minute)
+	}
+
+	return resolver, nil
+}
+
+// replicaPolicy returns the dbresolver policy named name
+func replicaPolicy(name string) (dbresolver.Policy, error) {
+	switch strings.ToLower(name) {
+	case "", ReplicaPolicyRandom:
+		return dbresolver.RandomPolicy{}, nil
+	case ReplicaPolicyRoundRobin:
+		return dbresolver.RoundRobinPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unknown replica policy %q", name)
+	}
+}
+
+// replicaPools returns the connection pools of the replicas
+func (gc *GormConnection) replicaPools() []*sql.DB {
+	if gc.resolver == nil {
+		return nil
+	}
+	primary, _ := gc.DB.DB()
+
+	var pools []*sql.DB
+	gc.resolver.Call(func(connPool gorm.ConnPool) error {
+		if pool, ok := connPool.(*sql.DB); ok && pool != primary {
+			pools = append(pools, pool)
+		}
+		return nil
+	})
+	return pools
+}
@@ -0,0 +1,191 @@
+package gq
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Repository
+// Repository[T] binds a database and model T to the generic
+// helpers, so call sites no longer pass both each time. Default
+// QueryOptions given to the repository, such as a tenant Where
+// condition or WithDeleted, apply to every read; their conditions,
+// joins and scopes also guard Update and Delete. Options passed to
+// a call are added to them.
+// --------------------------------------------------
+// **************************************************
+
+// Repository exposes the gq helpers for model T as methods
+type Repository[T any] struct {
+	db   *gorm.DB
+	opts []QueryOption
+}
+
+// NewRepository creates a repository for model T with default options
+func NewRepository[T any](db *gorm.DB, opts ...QueryOption) *Repository[T] {
+	return &Repository[T]{db: db, opts: opts}
+}
+
+// DB returns the database of the repository
+func (r *Repository[T]) DB() *gorm.DB {
+	return r.db
+}
+
+// WithContext returns a copy of the repository that runs queries using ctx
+func (r *Repository[T]) WithContext(ctx context.Context) *Repository[T] {
+	return &Repository[T]{db: r.db.WithContext(ctx), opts: r.opts}
+}
+
+// WithTx returns a copy of the repository that runs queries in tx, e.g.
+// inside WithTx
+func (r *Repository[T]) WithTx(tx *gorm.DB) *Repository[T] {
+	return &Repository[T]{db: tx, opts: r.opts}
+}
+
+// Transaction runs fn with a copy of the repository bound to a new
+// transaction, committed when fn returns nil and rolled back otherwise
+func (r *Repository[T]) Transaction(fn func(repo *Repository[T]) error) error {
+	return WithTx(r.db, func(tx *gorm.DB) error {
+		return fn(r.WithTx(tx))
+	})
+}
+
+// With returns a copy of the repository with opts added to its defaults,
+// e.g. repo.With(gq.Where(gq.Eq("tenant_id", tenantID)))
+func (r *Repository[T]) With(opts ...QueryOption) *Repository[T] {
+	return &Repository[T]{db: r.db, opts: r.options(opts)}
+}
+
+// options returns the default options followed by opts
+func (r *Repository[T]) options(opts []QueryOption) []QueryOption {
+	if len(opts) == 0 {
+		return r.opts
+	}
+	all := make([]QueryOption, 0, len(r.opts)+len(opts))
+	all = append(all, r.opts...)
+	return append(all, opts...)
+}
+
+// scoped returns the database restricted to the records the default
+// conditions, joins and scopes match, for writes
+func (r *Repository[T]) scoped() (*gorm.DB, error) {
+	var options queryOptions
+	for _, opt := range r.opts {
+		opt(&options)
+	}
+	// WithDeleted and OnlyDeleted widen reads only: unscoped, Delete would
+	// remove rows for good. Restore reaches soft-deleted rows itself.
+	options.withDeleted = false
+	options.onlyDeleted = false
+	// WithSelect narrows what reads fetch, not the columns writes change
+	options.columns = nil
+	return applyOptions[T](r.db, options)
+}
+
+// Create inserts a record into the database.
+func (r *Repository[T]) Create(record T) (*T, error) {
+	return InsertRecord(r.db, record)
+}
+
+// CreateBatch inserts a batch of records into the database.
+func (r *Repository[T]) CreateBatch(records []T, batchSize int) error {
+	return BatchInsert(r.db, records, batchSize)
+}
+
+// GetByID gets a record from the database by ID.
+func (r *Repository[T]) GetByID(id string, opts ...QueryOption) (*T, error) {
+	return GetRecordByID[T](r.db, id, r.options(opts)...)
+}
+
+// GetByField gets a record from the database by field. It returns nil,
+// nil when no record matches, as GetRecordByField does.
+func (r *Repository[T]) GetByField(field string, value interface{}, opts ...QueryOption) (*T, error) {
+	return GetRecordByField[T](r.db, field, value, r.options(opts)...)
+}
+
+// GetByKey gets a record from the database by its composite key.
+func (r *Repository[T]) GetByKey(key Key, opts ...QueryOption) (*T, error) {
+	return GetRecordByKey[T](r.db, key, r.options(opts)...)
+}
+
+// LockByField gets a record from the database by field and locks it until
+// the end of the transaction.
+func (r *Repository[T]) LockByField(field string, value interface{}, opts ...QueryOption) (*T, error) {
+	return LockAndGetRecordByField[T](r.db, field, value, r.options(opts)...)
+}
+
+// Find gets all records from the database matching conditions.
+func (r *Repository[T]) Find(conditions map[string]interface{}, opts ...QueryOption) ([]T, error) {
+	return GetRecordsByFields[T](r.db, conditions, r.options(opts)...)
+}
+
+// Count counts the records in the database matching conditions.
+func (r *Repository[T]) Count(conditions map[string]interface{}, opts ...QueryOption) (int64, error) {
+	return CountRecords[T](r.db, conditions, r.options(opts)...)
+}
+
+// Exists checks if a record exists in the database by field.
+func (r *Repository[T]) Exists(field string, value interface{}, opts ...QueryOption) (bool, error) {
+	return RecordExists[T](r.db, field, value, r.options(opts)...)
+}
+
+// List gets a page of records from the database matching conditions, and
+// the total number of pages. With no conditions every record is listed.
+func (r *Repository[T]) List(page, pageSize int, conditions map[string]interface{}, opts ...QueryOption) ([]T, int, error) {
+	opts = r.options(opts)
+	if len(conditions) == 0 && !hasConditions(opts) {
+		return GetAllRecords[T](r.db, page, pageSize, opts...)
+	}
+	return GetFilteredPaginatedRecords[T](r.db, page, pageSize, conditions, opts...)
+}
+
+// Update updates a record in the database by ID. updates is a struct or
+// a map, as for UpdateRecordByID.
+func (r *Repository[T]) Update(id string, updates interface{}) error {
+	db, err := r.scoped()
+	if err != nil {
+		return err
+	}
+	return UpdateRecordByID[T](db, id, updates)
+}
+
+// UpdateByField updates the records in the database matching field.
+func (r *Repository[T]) UpdateByField(field string, value interface{}, updates interface{}) error {
+	db, err := r.scoped()
+	if err != nil {
+		return err
+	}
+	return UpdateRecordByField[T](db, field, value, updates)
+}
+
+// BulkUpdate updates the records in the database matching conditions and
+// returns how many were updated.
+func (r *Repository[T]) BulkUpdate(conditions map[string]interface{}, updates interface{}) (int64, error) {
+	db, err := r.scoped()
+	if err != nil {
+		return 0, err
+	}
+	return BulkUpdateByFields[T](db, conditions, updates)
+}
+
+// Delete deletes a record in the database by ID. Models with a
+// gorm.DeletedAt field are soft deleted.
+func (r *Repository[T]) Delete(id string) error {
+	db, err := r.scoped()
+	if err != nil {
+		return err
+	}
+	return DeleteRecordByID[T](db, id)
+}
+
+// Restore restores a soft-deleted record in the database by ID.
+func (r *Repository[T]) Restore(id string) error {
+	db, err := r.scoped()
+	if err != nil {
+		return err
+	}
+	return RestoreRecordByID[T](db, id)
+}
@@ -0,0 +1,83 @@
+package gq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+
+	"github.com/arbenlabs/stoner/retry"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Transaction Retry
+// Deadlocks and serialization failures abort a transaction
+// without it being at fault, so the whole transaction is
+// retried with backoff.
+// --------------------------------------------------
+// **************************************************
+
+// Database error codes that mean a transaction can be retried
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	mysqlLockWaitTimeout   = 1205
+	mysqlDeadlock          = 1213
+)
+
+// DefaultTxRetryPolicy is the policy used by TransactionWithRetry when none is
+// given: 5 attempts starting at 50ms with jitter so the competing
+// transactions do not collide again
+func DefaultTxRetryPolicy() retry.Policy {
+	policy := retry.DefaultPolicy()
+	policy.InitialInterval = 50 * time.Millisecond
+	policy.MaxInterval = 2 * time.Second
+	policy.Jitter = 0.5
+	return policy
+}
+
+// IsRetryableTxError checks if err is a deadlock, serialization failure or
+// lock timeout reported by PostgreSQL, MySQL or SQLite
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+
+	var mysqlErr *gomysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+
+	return false
+}
+
+// TransactionWithRetry executes fn within a transaction, retrying the whole
+// transaction when it fails with a deadlock or serialization failure. fn may
+// run several times, so it must not have side effects outside the database.
+// A zero policy uses DefaultTxRetryPolicy; the policy's Retryable function,
+// when set, replaces IsRetryableTxError.
+func (gc *GormConnection) TransactionWithRetry(ctx context.Context, policy retry.Policy, fn func(*gorm.DB) error) error {
+	if policy.MaxAttempts == 0 && policy.InitialInterval == 0 && policy.MaxElapsedTime == 0 {
+		onRetry := policy.OnRetry
+		policy = DefaultTxRetryPolicy()
+		policy.OnRetry = onRetry
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = IsRetryableTxError
+	}
+
+	return retry.Do(ctx, policy, func(ctx context.Context) error {
+		return gc.DB.WithContext(ctx).Transaction(fn)
+	})
+}
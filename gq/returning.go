@@ -0,0 +1,97 @@
+package gq
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Delete and Return
+// Deletes that also return what was removed, for audit trails.
+// Postgres deletes and reads the rows in one statement with
+// RETURNING; other databases lock and read the rows, then delete
+// them, in a transaction.
+// --------------------------------------------------
+// **************************************************
+
+// supportsReturning checks if the database can return deleted rows
+func supportsReturning(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
+
+// DeleteAndReturnByID deletes a record from the database by ID and returns
+// it. It returns gorm.ErrRecordNotFound when there is no such record.
+func DeleteAndReturnByID[T any](db *gorm.DB, id string) (*T, error) {
+	var record T
+	if supportsReturning(db) {
+		result := db.Clauses(clause.Returning{}).Where("id = ?", id).Delete(&record)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &record, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&record).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// DeleteAndReturnByField deletes the records matching a field from the
+// database and returns them.
+func DeleteAndReturnByField[T any](db *gorm.DB, field string, value interface{}) ([]T, error) {
+	if err := validateFieldName(field); err != nil {
+		return nil, err
+	}
+
+	if !isFieldInModel[T](field) {
+		return nil, fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, field)
+	}
+
+	var records []T
+	if supportsReturning(db) {
+		result := db.Clauses(clause.Returning{}).Where(field+" = ?", value).Delete(&records)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		return records, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(field+" = ?", value).Find(&records).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil // Nothing to delete
+		}
+		// Delete the rows that were read, by primary key
+		return tx.Delete(&records).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// DeleteAndReturnByIDCtx deletes a record from the database by ID and returns it using ctx.
+func DeleteAndReturnByIDCtx[T any](ctx context.Context, db *gorm.DB, id string) (*T, error) {
+	return DeleteAndReturnByID[T](db.WithContext(ctx), id)
+}
+
+// DeleteAndReturnByFieldCtx deletes the records matching a field from the database and returns them using ctx.
+func DeleteAndReturnByFieldCtx[T any](ctx context.Context, db *gorm.DB, field string, value interface{}) ([]T, error) {
+	return DeleteAndReturnByField[T](db.WithContext(ctx), field, value)
+}
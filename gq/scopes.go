@@ -0,0 +1,130 @@
+package gq
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Default Scopes
+// Scopes registered for a model, such as "not archived" or
+// "published only", are applied by every read helper for that
+// model. Unscoped bypasses them; soft-deleted records stay hidden
+// unless WithDeleted or OnlyDeleted is also given.
+// --------------------------------------------------
+// **************************************************
+
+// Scope narrows a query, as for gorm.DB.Scopes
+type Scope func(*gorm.DB) *gorm.DB
+
+// namedScope is a registered default scope
+type namedScope struct {
+	name  string
+	scope Scope
+}
+
+// defaultScopes holds the default scopes of each model, in registration order
+var (
+	defaultScopesMu sync.RWMutex
+	defaultScopes   = map[reflect.Type][]namedScope{}
+)
+
+// RegisterScope registers a default scope for model T. Registering a name
+// again replaces the scope and keeps its position.
+func RegisterScope[T any](name string, scope Scope) error {
+	if name == "" {
+		return fmt.Errorf("scope name cannot be empty")
+	}
+	if scope == nil {
+		return fmt.Errorf("scope %q has no function", name)
+	}
+
+	model := modelType[T]()
+	defaultScopesMu.Lock()
+	defer defaultScopesMu.Unlock()
+	// Copy on write, as queries read the slice without the lock
+	scopes := append([]namedScope(nil), defaultScopes[model]...)
+	for i := range scopes {
+		if scopes[i].name == name {
+			scopes[i].scope = scope
+			defaultScopes[model] = scopes
+			return nil
+		}
+	}
+	defaultScopes[model] = append(scopes, namedScope{name: name, scope: scope})
+	return nil
+}
+
+// UnregisterScope removes a default scope of model T
+func UnregisterScope[T any](name string) {
+	model := modelType[T]()
+	defaultScopesMu.Lock()
+	defer defaultScopesMu.Unlock()
+	scopes := defaultScopes[model]
+	for i := range scopes {
+		if scopes[i].name == name {
+			defaultScopes[model] = append(scopes[:i:i], scopes[i+1:]...)
+			return
+		}
+	}
+}
+
+// RegisteredScopes returns the names of the default scopes of model T
+func RegisteredScopes[T any]() []string {
+	defaultScopesMu.RLock()
+	defer defaultScopesMu.RUnlock()
+	scopes := defaultScopes[modelType[T]()]
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = scope.name
+	}
+	return names
+}
+
+// Unscoped bypasses the named default scopes of the model, or all of them
+// when no names are given
+func Unscoped(names ...string) QueryOption {
+	return func(o *queryOptions) {
+		if len(names) == 0 {
+			o.unscoped = true
+			return
+		}
+		if o.skipScopes == nil {
+			o.skipScopes = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			o.skipScopes[name] = true
+		}
+	}
+}
+
+// applyDefaultScopes applies the default scopes of model T to db
+func applyDefaultScopes[T any](db *gorm.DB, options queryOptions) *gorm.DB {
+	if options.unscoped {
+		return db
+	}
+
+	defaultScopesMu.RLock()
+	scopes := defaultScopes[modelType[T]()]
+	defaultScopesMu.RUnlock()
+
+	for _, scope := range scopes {
+		if !options.skipScopes[scope.name] {
+			db = db.Scopes(scope.scope)
+		}
+	}
+	return db
+}
+
+// modelType returns the struct type of model T
+func modelType[T any]() reflect.Type {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,154 @@
+package gq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Soft Delete
+// Models with a gorm.DeletedAt field are soft deleted: GORM sets
+// the column instead of removing the row and hides those rows
+// from queries. WithDeleted and OnlyDeleted widen the read
+// helpers to include them.
+// --------------------------------------------------
+// **************************************************
+
+// ErrNotSoftDeletable is returned when a soft delete helper is used with a
+// model that has no gorm.DeletedAt field
+var ErrNotSoftDeletable = errors.New("model does not support soft delete")
+
+// deletedAtType is the field type GORM uses for soft deletes
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// QueryOption changes which records a read helper returns
+type QueryOption func(*queryOptions)
+
+// queryOptions holds the options of a read helper
+type queryOptions struct {
+	withDeleted bool
+	onlyDeleted bool
+	unscoped    bool            // skip all default scopes
+	skipScopes  map[string]bool // default scopes to skip
+	conditions  []Condition     // Where conditions
+	joins       []join          // WithJoin filters
+	columns     []string        // WithSelect columns
+	primary     bool            // WithPrimary
+}
+
+// WithDeleted includes soft-deleted records
+func WithDeleted() QueryOption {
+	return func(o *queryOptions) {
+		o.withDeleted = true
+	}
+}
+
+// OnlyDeleted returns only soft-deleted records
+func OnlyDeleted() QueryOption {
+	return func(o *queryOptions) {
+		o.onlyDeleted = true
+	}
+}
+
+// applyQueryOptions scopes db according to opts for model T
+func applyQueryOptions[T any](db *gorm.DB, opts []QueryOption) (*gorm.DB, error) {
+	var options queryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return applyOptions[T](db, options)
+}
+
+// applyOptions scopes db according to parsed options for model T
+func applyOptions[T any](db *gorm.DB, options queryOptions) (*gorm.DB, error) {
+	switch {
+	case options.onlyDeleted:
+		field, err := deletedAtField[T](db)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Unscoped().Where(clause.Neq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+			Value:  nil,
+		})
+	case options.withDeleted:
+		db = db.Unscoped()
+	}
+	db = applyDefaultScopes[T](db, options)
+	db = applyPrimary(db, options.primary)
+
+	for _, condition := range options.conditions {
+		expr, err := conditionExpression[T](condition)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(expr)
+	}
+	db, err := applyJoins[T](db, options.joins)
+	if err != nil {
+		return nil, err
+	}
+	return applySelect[T](db, options.columns)
+}
+
+// deletedAtField returns the gorm.DeletedAt field of model T
+func deletedAtField[T any](db *gorm.DB) (*schema.Field, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.FieldType == deletedAtType && field.DBName != "" {
+			return field, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotSoftDeletable, stmt.Schema.Name)
+}
+
+// SoftDeleteRecordByID soft deletes a record in the database by ID. Unlike
+// DeleteRecordByID it fails instead of removing the row when the model has
+// no gorm.DeletedAt field.
+func SoftDeleteRecordByID[T any](db *gorm.DB, id string) error {
+	if _, err := deletedAtField[T](db); err != nil {
+		return err
+	}
+
+	var record T
+	result := db.Where("id = ?", id).Delete(&record)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// RestoreRecordByID restores a soft-deleted record in the database by ID.
+func RestoreRecordByID[T any](db *gorm.DB, id string) error {
+	field, err := deletedAtField[T](db)
+	if err != nil {
+		return err
+	}
+
+	var record T
+	result := db.Unscoped().Model(&record).Where("id = ?", id).Update(field.DBName, nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// SoftDeleteRecordByIDCtx soft deletes a record in the database by ID using ctx.
+func SoftDeleteRecordByIDCtx[T any](ctx context.Context, db *gorm.DB, id string) error {
+	return SoftDeleteRecordByID[T](db.WithContext(ctx), id)
+}
+
+// RestoreRecordByIDCtx restores a soft-deleted record in the database by ID using ctx.
+func RestoreRecordByIDCtx[T any](ctx context.Context, db *gorm.DB, id string) error {
+	return RestoreRecordByID[T](db.WithContext(ctx), id)
+}
@@ -0,0 +1,30 @@
+package gq
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Transactions
+// WithTx runs a sequence of helpers atomically: pass the tx it
+// gives to each helper, or bind repositories to it with
+// Repository.WithTx. Lock helpers such as LockAndGetRecordByField
+// only hold their locks inside a transaction.
+// --------------------------------------------------
+// **************************************************
+
+// WithTx runs fn in a transaction on db. The transaction is committed when
+// fn returns nil and rolled back when fn returns an error or panics. When
+// db is already a transaction, fn runs in a savepoint, so only its own
+// changes are rolled back.
+func WithTx(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return db.Transaction(fn)
+}
+
+// WithTxCtx runs fn in a transaction on db using ctx.
+func WithTxCtx(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return WithTx(db.WithContext(ctx), fn)
+}
@@ -0,0 +1,116 @@
+package gq
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Upserts
+// Insert-or-update helpers built on ON CONFLICT (ON DUPLICATE KEY
+// UPDATE on MySQL), for idempotent writes from ingestion jobs.
+// --------------------------------------------------
+// **************************************************
+
+// UpsertOptions configures the conflict handling of an upsert
+type UpsertOptions struct {
+	ConflictColumns []string // columns of the unique constraint; empty uses the primary key
+	UpdateColumns   []string // columns overwritten on conflict; empty overwrites every column
+	DoNothing       bool     // keep the existing row on conflict
+}
+
+// onConflict validates the options and builds the ON CONFLICT clause
+func (o UpsertOptions) onConflict() (clause.OnConflict, error) {
+	for _, columns := range [][]string{o.ConflictColumns, o.UpdateColumns} {
+		for _, column := range columns {
+			if err := validateFieldName(column); err != nil {
+				return clause.OnConflict{}, err
+			}
+		}
+	}
+	if o.DoNothing && len(o.UpdateColumns) > 0 {
+		return clause.OnConflict{}, fmt.Errorf("upsert cannot both do nothing and update columns")
+	}
+
+	onConflict := clause.OnConflict{DoNothing: o.DoNothing}
+	for _, column := range o.ConflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	switch {
+	case o.DoNothing:
+	case len(o.UpdateColumns) > 0:
+		onConflict.DoUpdates = clause.AssignmentColumns(o.UpdateColumns)
+	default:
+		onConflict.UpdateAll = true
+	}
+	return onConflict, nil
+}
+
+// validateUpsertColumns checks that every column exists in model T
+func validateUpsertColumns[T any](options UpsertOptions) error {
+	for _, columns := range [][]string{options.ConflictColumns, options.UpdateColumns} {
+		for _, column := range columns {
+			if !isFieldInModel[T](column) {
+				return fmt.Errorf("%w: field '%s' not found in model", ErrFieldNotFound, column)
+			}
+		}
+	}
+	return nil
+}
+
+// UpsertRecord inserts a record into the database, or updates the
+// conflicting row according to options.
+func UpsertRecord[T any](db *gorm.DB, record T, options UpsertOptions) (*T, error) {
+	onConflict, err := options.onConflict()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateUpsertColumns[T](options); err != nil {
+		return nil, err
+	}
+
+	result := db.Clauses(onConflict).Create(&record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// BatchUpsert inserts a batch of records into the database, updating
+// conflicting rows according to options.
+func BatchUpsert[T any](db *gorm.DB, records []T, batchSize int, options UpsertOptions) error {
+	if err := validateBatchSize(batchSize); err != nil {
+		return err
+	}
+
+	onConflict, err := options.onConflict()
+	if err != nil {
+		return err
+	}
+	if err := validateUpsertColumns[T](options); err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return nil // Nothing to upsert
+	}
+
+	if err := db.Clauses(onConflict).CreateInBatches(records, batchSize).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpsertRecordCtx inserts or updates a record in the database using ctx.
+func UpsertRecordCtx[T any](ctx context.Context, db *gorm.DB, record T, options UpsertOptions) (*T, error) {
+	return UpsertRecord(db.WithContext(ctx), record, options)
+}
+
+// BatchUpsertCtx inserts or updates a batch of records in the database using ctx.
+func BatchUpsertCtx[T any](ctx context.Context, db *gorm.DB, records []T, batchSize int, options UpsertOptions) error {
+	return BatchUpsert(db.WithContext(ctx), records, batchSize, options)
+}
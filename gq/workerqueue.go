@@ -0,0 +1,211 @@
+package gq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arbenlabs/stoner/worker"
+	"gorm.io/gorm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Worker Queue
+// WorkerQueue persists background jobs for the worker package so
+// that they survive restarts and are shared between replicas. Jobs
+// are claimed with a conditional UPDATE, so exactly one replica
+// runs each attempt.
+// --------------------------------------------------
+// **************************************************
+
+// Worker job statuses
+const (
+	WorkerJobPending = "pending"
+	WorkerJobDone    = "done"
+	WorkerJobDead    = "dead"
+)
+
+// workerQueueClaimAttempts bounds how often Claim retries when another
+// replica claims the selected job first
+const workerQueueClaimAttempts = 3
+
+// WorkerJobRecord is the GORM model backing WorkerQueue
+type WorkerJobRecord struct {
+	ID          string     `gorm:"column:id;primaryKey;size:64" json:"id"`
+	Name        string     `gorm:"column:name;size:255;not null" json:"name"`
+	Payload     []byte     `gorm:"column:payload" json:"payload"`
+	Priority    int        `gorm:"column:priority;not null;default:0" json:"priority"`
+	Status      string     `gorm:"column:status;size:16;not null;index:idx_worker_jobs_due,priority:1" json:"status"`
+	RunAt       time.Time  `gorm:"column:run_at;not null;index:idx_worker_jobs_due,priority:2" json:"run_at"`
+	Attempts    int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"column:max_attempts;not null" json:"max_attempts"`
+	LastError   string     `gorm:"column:last_error;type:text" json:"last_error,omitempty"`
+	LockedBy    string     `gorm:"column:locked_by;size:255" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `gorm:"column:locked_until" json:"locked_until,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for worker job records
+func (WorkerJobRecord) TableName() string {
+	return "worker_jobs"
+}
+
+// toQueueRecord converts the model to the worker package representation
+func (r WorkerJobRecord) toQueueRecord() worker.QueueRecord {
+	return worker.QueueRecord{
+		ID:          r.ID,
+		Name:        r.Name,
+		Payload:     r.Payload,
+		Priority:    worker.Priority(r.Priority),
+		Attempts:    r.Attempts,
+		MaxAttempts: r.MaxAttempts,
+		RunAt:       r.RunAt,
+		LastError:   r.LastError,
+	}
+}
+
+// WorkerQueue is a GORM-backed implementation of worker.Queue
+type WorkerQueue struct {
+	db *gorm.DB
+}
+
+// NewWorkerQueue creates a new worker queue
+func NewWorkerQueue(db *gorm.DB) *WorkerQueue {
+	return &WorkerQueue{db: db}
+}
+
+// AutoMigrate creates or updates the worker_jobs table
+func (q *WorkerQueue) AutoMigrate() error {
+	if err := q.db.AutoMigrate(&WorkerJobRecord{}); err != nil {
+		return fmt.Errorf("auto-migration failed: %w", err)
+	}
+	return nil
+}
+
+// Enqueue persists a new job
+func (q *WorkerQueue) Enqueue(ctx context.Context, record worker.QueueRecord) error {
+	model := WorkerJobRecord{
+		ID:          record.ID,
+		Name:        record.Name,
+		Payload:     record.Payload,
+		Priority:    int(record.Priority),
+		Status:      WorkerJobPending,
+		RunAt:       record.RunAt,
+		MaxAttempts: record.MaxAttempts,
+	}
+	if err := q.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to enqueue worker job %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// Claim locks the highest-priority due job for owner
+func (q *WorkerQueue) Claim(ctx context.Context, owner string, lease time.Duration) (*worker.QueueRecord, error) {
+	db := q.db.WithContext(ctx)
+
+	// Jobs whose lease expired on their last attempt crashed every run:
+	// mark them dead instead of reclaiming them
+	err := db.Model(&WorkerJobRecord{}).
+		Where("status = ? AND locked_until <= ? AND attempts >= max_attempts", WorkerJobPending, time.Now()).
+		Updates(map[string]interface{}{
+			"status":       WorkerJobDead,
+			"last_error":   "lease expired on the last attempt",
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire worker jobs: %w", err)
+	}
+
+	for attempt := 0; attempt < workerQueueClaimAttempts; attempt++ {
+		now := time.Now()
+
+		var candidate WorkerJobRecord
+		result := db.Where("status = ? AND run_at <= ?", WorkerJobPending, now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Order("priority DESC").Order("run_at ASC").
+			Limit(1).Find(&candidate)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to find worker job: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil, nil
+		}
+
+		lockedUntil := now.Add(lease)
+		result = db.Model(&WorkerJobRecord{}).
+			Where("id = ? AND status = ? AND attempts = ?", candidate.ID, WorkerJobPending, candidate.Attempts).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Updates(map[string]interface{}{
+				"locked_by":    owner,
+				"locked_until": lockedUntil,
+				"attempts":     gorm.Expr("attempts + 1"),
+			})
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim worker job %s: %w", candidate.ID, result.Error)
+		}
+		if result.RowsAffected == 1 {
+			candidate.Attempts++
+			candidate.LockedBy = owner
+			candidate.LockedUntil = &lockedUntil
+			record := candidate.toQueueRecord()
+			return &record, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Complete marks a job claimed by owner as done
+func (q *WorkerQueue) Complete(ctx context.Context, id, owner string) error {
+	err := q.db.WithContext(ctx).Model(&WorkerJobRecord{}).
+		Where("id = ? AND locked_by = ?", id, owner).
+		Updates(map[string]interface{}{
+			"status":       WorkerJobDone,
+			"last_error":   "",
+			"locked_by":    "",
+			"locked_until": nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete worker job %s: %w", id, err)
+	}
+	return nil
+}
+
+// Fail records a failed attempt of a job claimed by owner
+func (q *WorkerQueue) Fail(ctx context.Context, id, owner string, runErr error, retryAt time.Time) error {
+	updates := map[string]interface{}{
+		"locked_by":    "",
+		"locked_until": nil,
+	}
+	if runErr != nil {
+		updates["last_error"] = runErr.Error()
+	}
+	if retryAt.IsZero() {
+		updates["status"] = WorkerJobDead
+	} else {
+		updates["run_at"] = retryAt
+	}
+
+	err := q.db.WithContext(ctx).Model(&WorkerJobRecord{}).
+		Where("id = ? AND locked_by = ?", id, owner).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to record failure of worker job %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteFinished removes done jobs last updated before the given time and
+// returns how many were removed
+func (q *WorkerQueue) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	result := q.db.WithContext(ctx).
+		Where("status = ? AND updated_at < ?", WorkerJobDone, before).
+		Delete(&WorkerJobRecord{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete finished worker jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/arbenlabs/stoner/retry"
+)
+
+// DefaultMaxBufferedBody is the largest io.Reader body buffered so that it
+// can be sent again on retries and redirects
+const DefaultMaxBufferedBody = 1 << 20 // 1 MiB
+
+// ErrBodyNotReplayable is returned instead of retrying a request whose body
+// was a stream too large to buffer and had no GetBody
+var ErrBodyNotReplayable = errors.New("request body cannot be sent again")
+
+// SetMaxBufferedBody sets the largest io.Reader body buffered for retries
+func (c *Client) SetMaxBufferedBody(size int64) {
+	c.maxBufferedBody = size
+}
+
+// requestBody produces the body of each attempt of a request
+type requestBody struct {
+	open       func() (io.ReadCloser, error)
+	size       int64 // -1 when unknown
+	replayable bool  // open returns a fresh body on every call
+	json       bool  // the body was marshaled from a value
+}
+
+// requestBody prepares the body of req once for all its attempts: GetBody
+// is used as is, io.Reader bodies are buffered up to the client's limit,
+// and other values are marshaled to JSON
+func (c *Client) requestBody(req *Request) (*requestBody, error) {
+	if req.GetBody != nil {
+		return &requestBody{open: req.GetBody, size: -1, replayable: true}, nil
+	}
+
+	switch body := req.Body.(type) {
+	case nil:
+		return nil, nil
+	case io.Reader:
+		return c.readerBody(body)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		b := bytesBody(data)
+		b.json = true
+		return b, nil
+	}
+}
+
+// bytesBody replays data
+func bytesBody(data []byte) *requestBody {
+	return &requestBody{
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+		size:       int64(len(data)),
+		replayable: true,
+	}
+}
+
+// readerBody buffers r when it fits the limit. Larger bodies are streamed
+// and can be sent only once; a retry then fails with ErrBodyNotReplayable
+// instead of sending an empty body.
+func (c *Client) readerBody(r io.Reader) (*requestBody, error) {
+	limit := c.maxBufferedBody
+	if limit <= 0 {
+		limit = DefaultMaxBufferedBody
+	}
+
+	buffered, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	if int64(len(buffered)) <= limit {
+		if closer, ok := r.(io.Closer); ok {
+			closer.Close()
+		}
+		return bytesBody(buffered), nil
+	}
+
+	var once sync.Once
+	return &requestBody{
+		open: func() (io.ReadCloser, error) {
+			var body io.ReadCloser
+			once.Do(func() {
+				body = &streamBody{Reader: io.MultiReader(bytes.NewReader(buffered), r), source: r}
+			})
+			if body == nil {
+				return nil, retry.Permanent(ErrBodyNotReplayable)
+			}
+			return body, nil
+		},
+		size: -1,
+	}, nil
+}
+
+// streamBody closes the source of a streamed body
+type streamBody struct {
+	io.Reader
+	source io.Reader
+}
+
+func (b *streamBody) Close() error {
+	if closer, ok := b.source.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
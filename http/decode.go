@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// StatusError is returned by the JSON helpers for responses with a status
+// of 400 or above
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP error: %d", e.StatusCode)
+}
+
+// Envelope is a response that wraps its payload, as in
+// {"data": ..., "error": ..., "meta": ...}
+type Envelope[T any] struct {
+	Data  T                      `json:"data"`
+	Error *EnvelopeError         `json:"error,omitempty"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+}
+
+// EnvelopeError is the error of an envelope. APIs that send the error as a
+// plain string fill only Message.
+type EnvelopeError struct {
+	Code    string          `json:"code,omitempty"`
+	Message string          `json:"message"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+func (e *EnvelopeError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return e.Message
+}
+
+// UnmarshalJSON accepts an error object or a string
+func (e *EnvelopeError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = EnvelopeError{Message: message}
+		return nil
+	}
+	type envelopeError EnvelopeError // Without the UnmarshalJSON method
+	return json.Unmarshal(data, (*envelopeError)(e))
+}
+
+// DecodeEnvelope decodes an enveloped response body. When the envelope
+// holds an error, the envelope is returned with it as the error.
+func DecodeEnvelope[T any](body []byte) (*Envelope[T], error) {
+	var envelope Envelope[T]
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	if envelope.Error != nil {
+		return &envelope, envelope.Error
+	}
+	return &envelope, nil
+}
+
+// DoAs performs a request with the client's retries and decodes the JSON
+// response into a T
+func DoAs[T any](ctx context.Context, c *Client, req *Request) (T, error) {
+	var result T
+	resp, err := c.DoContext(ctx, req)
+	if err != nil {
+		return result, err
+	}
+	if resp.StatusCode >= 400 {
+		return result, &StatusError{StatusCode: resp.StatusCode, Body: resp.Body}
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// GetAs performs a GET request and decodes the JSON response into a T
+func GetAs[T any](ctx context.Context, c *Client, url string, headers map[string]string) (T, error) {
+	return DoAs[T](ctx, c, &Request{Method: "GET", URL: url, Headers: headers})
+}
+
+// PostAs performs a POST request and decodes the JSON response into a T
+func PostAs[T any](ctx context.Context, c *Client, url string, body interface{}, headers map[string]string) (T, error) {
+	return DoAs[T](ctx, c, &Request{Method: "POST", URL: url, Body: body, Headers: headers})
+}
+
+// DoEnvelope performs a request and decodes an enveloped JSON response.
+// The envelope error of a failed response is returned when the body has
+// one, wrapped with the status; otherwise a *StatusError is.
+func DoEnvelope[T any](ctx context.Context, c *Client, req *Request) (*Envelope[T], error) {
+	resp, err := c.DoContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := DecodeEnvelope[T](resp.Body)
+	if resp.StatusCode >= 400 {
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Body: resp.Body}
+		if envelope != nil && envelope.Error != nil {
+			return envelope, fmt.Errorf("%w: %w", statusErr, envelope.Error)
+		}
+		return envelope, statusErr
+	}
+	return envelope, err
+}
+
+// GetEnvelope performs a GET request and decodes an enveloped JSON response
+func GetEnvelope[T any](ctx context.Context, c *Client, url string, headers map[string]string) (*Envelope[T], error) {
+	return DoEnvelope[T](ctx, c, &Request{Method: "GET", URL: url, Headers: headers})
+}
+
+// PostEnvelope performs a POST request and decodes an enveloped JSON response
+func PostEnvelope[T any](ctx context.Context, c *Client, url string, body interface{}, headers map[string]string) (*Envelope[T], error) {
+	return DoEnvelope[T](ctx, c, &Request{Method: "POST", URL: url, Body: body, Headers: headers})
+}
@@ -1,22 +1,26 @@
 package http
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/arbenlabs/stoner/retry"
 )
 
 // Client represents an HTTP client with additional features
 type Client struct {
-	httpClient     *http.Client
-	baseURL        string
-	defaultHeaders map[string]string
-	retryConfig    *RetryConfig
-	circuitBreaker *CircuitBreaker
+	httpClient      *http.Client
+	baseURL         string
+	defaultHeaders  map[string]string
+	retryConfig     *RetryConfig
+	circuitBreaker  *CircuitBreaker
+	profiles        map[string]*Profile
+	maxBufferedBody int64
 }
 
 // RetryConfig represents retry configuration
@@ -24,6 +28,18 @@ type RetryConfig struct {
 	MaxRetries int
 	Delay      time.Duration
 	Backoff    float64
+	Retryable  func(err error) bool // optional; by default every transport error is retried
+}
+
+// Policy returns the retry policy of the configuration. Transport errors
+// are retried; responses of any status code are returned as is.
+func (rc *RetryConfig) Policy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:     rc.MaxRetries + 1,
+		InitialInterval: rc.Delay,
+		Multiplier:      rc.Backoff,
+		Retryable:       rc.Retryable,
+	}
 }
 
 // CircuitBreaker represents circuit breaker configuration
@@ -33,13 +49,18 @@ type CircuitBreaker struct {
 	ResetTimeout time.Duration
 }
 
-// Request represents an HTTP request
+// Request represents an HTTP request. Body is sent as is when it is an
+// io.Reader, buffered so retries and redirects can send it again, and
+// marshaled to JSON otherwise. GetBody, when set, replaces Body and is
+// called for every attempt, for large bodies such as files.
 type Request struct {
 	Method  string
 	URL     string
 	Headers map[string]string
 	Body    interface{}
+	GetBody func() (io.ReadCloser, error)
 	Query   map[string]string
+	Profile string // name of a profile set with SetProfile; empty uses the client's settings
 }
 
 // Response represents an HTTP response
@@ -55,8 +76,10 @@ func NewClient(baseURL string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		baseURL:        baseURL,
-		defaultHeaders: make(map[string]string),
+		baseURL:         baseURL,
+		defaultHeaders:  make(map[string]string),
+		profiles:        make(map[string]*Profile),
+		maxBufferedBody: DefaultMaxBufferedBody,
 		retryConfig: &RetryConfig{
 			MaxRetries: 3,
 			Delay:      1 * time.Second,
@@ -137,75 +160,115 @@ func (c *Client) Delete(url string, headers map[string]string) (*Response, error
 
 // Do performs an HTTP request with retry logic
 func (c *Client) Do(req *Request) (*Response, error) {
-	var lastErr error
+	return c.DoContext(context.Background(), req)
+}
 
-	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
-		response, err := c.doRequest(req)
-		if err == nil {
-			return response, nil
-		}
+// DoContext performs an HTTP request with retry logic using ctx
+func (c *Client) DoContext(ctx context.Context, req *Request) (*Response, error) {
+	profile, err := c.profile(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+	retryConfig := c.retryConfig
+	if profile != nil && profile.Retry != nil {
+		retryConfig = profile.Retry
+	}
 
-		lastErr = err
+	// The key and body are prepared once, so every attempt sends the same
+	idempotencyKey, err := profile.idempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.requestBody(req)
+	if err != nil {
+		return nil, err
+	}
 
-		// Don't retry on the last attempt
-		if attempt == c.retryConfig.MaxRetries {
-			break
+	var lastErr error
+	response, err := retry.DoValue(ctx, retryConfig.Policy(), func(ctx context.Context) (*Response, error) {
+		response, err := c.doRequest(ctx, req, profile, idempotencyKey, body)
+		if errors.Is(err, ErrBodyNotReplayable) && lastErr != nil {
+			// Report the failure that needed the retry, not only the body
+			return nil, retry.Permanent(fmt.Errorf("%w after attempt failed: %w", ErrBodyNotReplayable, lastErr))
 		}
+		lastErr = err
+		return response, err
+	})
 
-		// Calculate delay with exponential backoff
-		delay := time.Duration(float64(c.retryConfig.Delay) *
-			pow(c.retryConfig.Backoff, float64(attempt)))
-
-		time.Sleep(delay)
+	var exhausted *retry.ExhaustedError
+	if errors.As(err, &exhausted) {
+		return nil, fmt.Errorf("request failed after %d attempts: %w", exhausted.Attempts, exhausted.Err)
 	}
-
-	return nil, fmt.Errorf("request failed after %d attempts: %w",
-		c.retryConfig.MaxRetries+1, lastErr)
+	return response, err
 }
 
 // doRequest performs a single HTTP request
-func (c *Client) doRequest(req *Request) (*Response, error) {
+func (c *Client) doRequest(ctx context.Context, req *Request, profile *Profile, idempotencyKey string, body *requestBody) (*Response, error) {
 	// Build URL
 	url := c.baseURL + req.URL
 
-	// Prepare request body
-	var bodyReader io.Reader
-	if req.Body != nil {
-		bodyBytes, err := json.Marshal(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal body: %w", err)
+	// Open this attempt's body
+	var bodyReader io.ReadCloser
+	if body != nil {
+		var err error
+		if bodyReader, err = body.open(); err != nil {
+			return nil, err
 		}
-		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
 	// Create HTTP request
-	httpReq, err := http.NewRequest(req.Method, url, bodyReader)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, bodyReader)
 	if err != nil {
+		if bodyReader != nil {
+			bodyReader.Close()
+		}
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if body != nil {
+		if body.size >= 0 {
+			httpReq.ContentLength = body.size
+		}
+		if body.replayable {
+			httpReq.GetBody = body.open // lets redirects resend the body
+		}
+	}
 
 	// Set headers
 	for key, value := range c.defaultHeaders {
 		httpReq.Header.Set(key, value)
 	}
+	if profile != nil {
+		for key, value := range profile.Headers {
+			httpReq.Header.Set(key, value)
+		}
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	}
 	for key, value := range req.Headers {
 		httpReq.Header.Set(key, value)
 	}
 
-	// Set content type if body is present
-	if req.Body != nil {
+	// Set content type if body is JSON
+	if body != nil && body.json {
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
 
 	// Perform request
-	resp, err := c.httpClient.Do(httpReq)
+	httpClient := c.httpClient
+	if profile != nil && profile.Timeout > 0 {
+		profileClient := *c.httpClient
+		profileClient.Timeout = profile.Timeout
+		httpClient = &profileClient
+	}
+	resp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
@@ -213,7 +276,7 @@ func (c *Client) doRequest(req *Request) (*Response, error) {
 	return &Response{
 		StatusCode: resp.StatusCode,
 		Headers:    resp.Header,
-		Body:       body,
+		Body:       respBody,
 	}, nil
 }
 
@@ -225,7 +288,7 @@ func (c *Client) JSON(req *Request, result interface{}) error {
 	}
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("HTTP error: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode, Body: resp.Body}
 	}
 
 	return json.Unmarshal(resp.Body, result)
@@ -269,75 +332,21 @@ func (c *Client) DeleteJSON(url string, result interface{}, headers map[string]s
 	}, result)
 }
 
-// Utility functions
-
-// pow calculates x^y
-func pow(x, y float64) float64 {
-	result := 1.0
-	for i := 0; i < int(y); i++ {
-		result *= x
-	}
-	return result
-}
-
-// WithContext performs a request with context
+// WithContext performs a request with context, without retries
 func (c *Client) WithContext(ctx context.Context, req *Request) (*Response, error) {
-	// Create a copy of the client with context
-	clientCopy := *c
-	clientCopy.httpClient = &http.Client{
-		Timeout: c.httpClient.Timeout,
-	}
-
-	// Build URL
-	url := c.baseURL + req.URL
-
-	// Prepare request body
-	var bodyReader io.Reader
-	if req.Body != nil {
-		bodyBytes, err := json.Marshal(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal body: %w", err)
-		}
-		bodyReader = bytes.NewReader(bodyBytes)
-	}
-
-	// Create HTTP request with context
-	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, bodyReader)
+	profile, err := c.profile(req.Profile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	for key, value := range c.defaultHeaders {
-		httpReq.Header.Set(key, value)
+		return nil, err
 	}
-	for key, value := range req.Headers {
-		httpReq.Header.Set(key, value)
-	}
-
-	// Set content type if body is present
-	if req.Body != nil {
-		httpReq.Header.Set("Content-Type", "application/json")
-	}
-
-	// Perform request
-	resp, err := clientCopy.httpClient.Do(httpReq)
+	idempotencyKey, err := profile.idempotencyKey()
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.requestBody(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
-
-	return &Response{
-		StatusCode: resp.StatusCode,
-		Headers:    resp.Header,
-		Body:       body,
-	}, nil
+	return c.doRequest(ctx, req, profile, idempotencyKey, body)
 }
 
 // RateLimiter represents a rate limiter
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arbenlabs/stoner/uuid"
+)
+
+// IdempotencyKeyHeader is the header set for profiles with IdempotencyKey
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Profile holds the settings of requests to one upstream, so a single
+// client can serve upstreams with different SLAs, e.g. a "search" profile
+// with a 2s timeout and no retries next to a "payments" profile with
+// idempotency keys and 3 retries. Zero fields use the client's settings.
+type Profile struct {
+	Timeout        time.Duration     // timeout of each attempt
+	Retry          *RetryConfig      // replaces the client's retry config; MaxRetries 0 disables retries
+	Headers        map[string]string // added to the default headers; request headers still win
+	IdempotencyKey bool              // send a new Idempotency-Key per request, the same on every retry
+}
+
+// SetProfile sets a named profile, selected with Request.Profile
+func (c *Client) SetProfile(name string, profile Profile) {
+	c.profiles[name] = &profile
+}
+
+// profile returns the named profile, or nil for the empty name
+func (c *Client) profile(name string) (*Profile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := c.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown client profile %q", name)
+	}
+	return profile, nil
+}
+
+// idempotencyKey creates the idempotency key of a request, if the profile
+// sends one
+func (p *Profile) idempotencyKey() (string, error) {
+	if p == nil || !p.IdempotencyKey {
+		return "", nil
+	}
+	key, err := uuid.NewUUIDString()
+	if err != nil {
+		return "", fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+	return key, nil
+}
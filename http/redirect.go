@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/arbenlabs/stoner/retry"
+)
+
+// DefaultMaxRedirects is the number of redirects followed by default, as
+// for http.Client
+const DefaultMaxRedirects = 10
+
+// Redirect errors, returned wrapped in a *url.Error by the request. They
+// are not retried.
+var (
+	ErrTooManyRedirects  = errors.New("too many redirects")
+	ErrCrossHostRedirect = errors.New("redirect to another host not allowed")
+	ErrInsecureRedirect  = errors.New("redirect from https to http not allowed")
+)
+
+// RedirectPolicy controls how the client follows redirects
+type RedirectPolicy struct {
+	// MaxRedirects is the number of redirects followed; 0 uses
+	// DefaultMaxRedirects, and a negative value returns the redirect
+	// response itself instead of following it
+	MaxRedirects int
+
+	// DenyCrossHost refuses redirects to a host other than the one of the
+	// original request, except those listed in AllowedHosts
+	DenyCrossHost bool
+	AllowedHosts  []string
+
+	// AllowDowngrade allows redirects from https to http
+	AllowDowngrade bool
+
+	// KeepAuthorization forwards the Authorization header on redirects to
+	// another origin (scheme, host and port). By default it is removed.
+	KeepAuthorization bool
+
+	// OnRedirect is called before each hop, numbered from 1, with the
+	// request about to be sent; an error stops the redirect and is returned
+	OnRedirect func(hop int, req *http.Request, via []*http.Request) error
+}
+
+// SetRedirectPolicy sets how the client follows redirects
+func (c *Client) SetRedirectPolicy(policy RedirectPolicy) {
+	c.httpClient.CheckRedirect = policy.checkRedirect
+}
+
+// checkRedirect implements http.Client.CheckRedirect
+func (p RedirectPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	maxRedirects := p.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	if maxRedirects < 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > maxRedirects {
+		return retry.Permanent(fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, maxRedirects))
+	}
+
+	original, previous := via[0].URL, via[len(via)-1].URL
+	if p.DenyCrossHost && !strings.EqualFold(req.URL.Hostname(), original.Hostname()) && !p.allowedHost(req.URL.Hostname()) {
+		return retry.Permanent(fmt.Errorf("%w: %s to %s", ErrCrossHostRedirect, original.Host, req.URL.Host))
+	}
+	if !p.AllowDowngrade && previous.Scheme == "https" && req.URL.Scheme == "http" {
+		return retry.Permanent(fmt.Errorf("%w: %s", ErrInsecureRedirect, req.URL.Redacted()))
+	}
+
+	// net/http drops the header before calling us when the domain changes,
+	// but keeps it for a new scheme or port, which is another origin too
+	if p.KeepAuthorization {
+		if auth := via[0].Header.Get("Authorization"); auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+	} else if !sameOrigin(req.URL.Scheme, req.URL.Host, original.Scheme, original.Host) {
+		req.Header.Del("Authorization")
+	}
+
+	if p.OnRedirect != nil {
+		return p.OnRedirect(len(via), req, via)
+	}
+	return nil
+}
+
+// allowedHost checks if host is in AllowedHosts
+func (p RedirectPolicy) allowedHost(host string) bool {
+	for _, allowed := range p.AllowedHosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOrigin compares two origins, ignoring default ports
+func sameOrigin(scheme1, host1, scheme2, host2 string) bool {
+	return strings.EqualFold(scheme1, scheme2) &&
+		strings.EqualFold(stripDefaultPort(scheme1, host1), stripDefaultPort(scheme2, host2))
+}
+
+// stripDefaultPort removes the default port of scheme from host
+func stripDefaultPort(scheme, host string) string {
+	switch {
+	case scheme == "http" && strings.HasSuffix(host, ":80"):
+		return strings.TrimSuffix(host, ":80")
+	case scheme == "https" && strings.HasSuffix(host, ":443"):
+		return strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
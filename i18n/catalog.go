@@ -0,0 +1,174 @@
+// Package i18n is a minimal localization layer: message catalogs keyed by
+// code with named parameters, locale negotiation from Accept-Language, and
+// translation of validation errors and problem responses.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// **************************************************
+// Catalogs
+// A Catalog holds messages per locale, keyed by code. Messages use
+// {name} placeholders filled from Params. Lookups fall back from
+// "pt-BR" to "pt" and then to the default locale, and finally return
+// the code itself, so a missing translation never breaks a response.
+// **************************************************
+
+// ErrInvalidLocale is returned for locale tags that cannot be parsed
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// Params are the named values substituted into a message
+type Params map[string]any
+
+// Catalog stores translated messages. It is safe for concurrent use.
+type Catalog struct {
+	defaultLocale string
+	mu            sync.RWMutex
+	messages      map[string]map[string]string // locale -> code -> message
+}
+
+// NewCatalog creates an empty catalog whose fallback locale is
+// defaultLocale, e.g. "en"
+func NewCatalog(defaultLocale string) (*Catalog, error) {
+	locale, err := NormalizeLocale(defaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	return &Catalog{
+		defaultLocale: locale,
+		messages:      map[string]map[string]string{locale: {}},
+	}, nil
+}
+
+// DefaultLocale returns the fallback locale of the catalog
+func (c *Catalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Add adds or replaces messages for a locale
+func (c *Catalog) Add(locale string, messages map[string]string) error {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+	}
+	for code, message := range messages {
+		c.messages[locale][code] = message
+	}
+	return nil
+}
+
+// Locales returns the locales that have messages, sorted
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Lookup finds the message for code in locale or its fallbacks and fills
+// in params. It reports false when no locale has the code.
+func (c *Catalog) Lookup(locale, code string, params Params) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, candidate := range c.fallbacks(locale) {
+		if message, ok := c.messages[candidate][code]; ok {
+			return Format(message, params), true
+		}
+	}
+	return "", false
+}
+
+// Translate returns the message for code in locale, or code itself when
+// the catalog has no message for it
+func (c *Catalog) Translate(locale, code string, params Params) string {
+	if message, ok := c.Lookup(locale, code, params); ok {
+		return message
+	}
+	return code
+}
+
+// fallbacks lists the locales to search for locale, most specific first
+func (c *Catalog) fallbacks(locale string) []string {
+	var candidates []string
+	if normalized, err := NormalizeLocale(locale); err == nil {
+		parts := strings.Split(normalized, "-")
+		for i := len(parts); i > 0; i-- {
+			candidates = append(candidates, strings.Join(parts[:i], "-"))
+		}
+	}
+	return append(candidates, c.defaultLocale)
+}
+
+// Format fills the {name} placeholders of message from params. Unknown
+// placeholders are left as they are.
+func Format(message string, params Params) string {
+	if len(params) == 0 || !strings.Contains(message, "{") {
+		return message
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(message, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(message[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(message[:start])
+		if value, ok := params[message[start+1:end]]; ok {
+			fmt.Fprint(&b, value)
+		} else {
+			b.WriteString(message[start : end+1])
+		}
+		message = message[end+1:]
+	}
+	b.WriteString(message)
+	return b.String()
+}
+
+// NormalizeLocale canonicalizes a BCP 47 tag: "en_us" becomes "en-US" and
+// "zh-hant-tw" becomes "zh-Hant-TW"
+func NormalizeLocale(locale string) (string, error) {
+	parts := strings.FieldsFunc(strings.TrimSpace(locale), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(parts) == 0 {
+		return "", fmt.Errorf("%w: empty tag", ErrInvalidLocale)
+	}
+	for i, part := range parts {
+		if len(part) > 8 || strings.Trim(strings.ToLower(part), "abcdefghijklmnopqrstuvwxyz0123456789") != "" {
+			return "", fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+		}
+		switch {
+		case i == 0:
+			if len(part) < 2 || len(part) > 3 {
+				return "", fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+			}
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-"), nil
+}
@@ -0,0 +1,158 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// Locale Negotiation
+// The locale of a request is chosen from its Accept-Language header
+// among the catalog's locales. Middleware stores it in the request
+// context so handlers and error writers translate consistently.
+// **************************************************
+
+// contextKey is the type of context keys set by this package
+type contextKey string
+
+// LocaleKey is the context key of the request locale
+const LocaleKey contextKey = "locale"
+
+// WithLocale returns a copy of ctx carrying locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, LocaleKey, locale)
+}
+
+// LocaleFromContext returns the locale stored in ctx, or "" if none
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(LocaleKey).(string); ok {
+		return locale
+	}
+	return ""
+}
+
+// LanguageRange is one entry of an Accept-Language header
+type LanguageRange struct {
+	Tag     string  // normalized tag, or "*"
+	Quality float64 // 0 to 1
+}
+
+// ParseAcceptLanguage parses an Accept-Language header, e.g.
+// "fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5", into ranges sorted by descending
+// quality. Invalid entries and entries with q=0 are skipped.
+func ParseAcceptLanguage(header string) []LanguageRange {
+	var ranges []LanguageRange
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+			quality = q
+		}
+		if quality == 0 {
+			continue
+		}
+
+		if tag != "*" {
+			normalized, err := NormalizeLocale(tag)
+			if err != nil {
+				continue
+			}
+			tag = normalized
+		}
+		ranges = append(ranges, LanguageRange{Tag: tag, Quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+	return ranges
+}
+
+// Negotiate picks the best of the supported locales for an Accept-Language
+// header. A range matches a supported locale exactly, or by language when
+// only the language agrees ("en-GB" accepts "en" and "en-US"). It returns
+// "" when nothing matches, so the caller can choose a default.
+func Negotiate(header string, supported ...string) string {
+	normalized := make([]string, 0, len(supported))
+	for _, locale := range supported {
+		if locale, err := NormalizeLocale(locale); err == nil {
+			normalized = append(normalized, locale)
+		}
+	}
+
+	for _, r := range ParseAcceptLanguage(header) {
+		if r.Tag == "*" {
+			if len(normalized) > 0 {
+				return normalized[0]
+			}
+			continue
+		}
+		for _, locale := range normalized {
+			if locale == r.Tag {
+				return locale
+			}
+		}
+		language := baseLanguage(r.Tag)
+		for _, locale := range normalized {
+			if locale == language {
+				return locale
+			}
+		}
+		for _, locale := range normalized {
+			if baseLanguage(locale) == language {
+				return locale
+			}
+		}
+	}
+	return ""
+}
+
+// baseLanguage returns the language subtag of a normalized locale
+func baseLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// Negotiate picks the best catalog locale for an Accept-Language header,
+// falling back to the default locale
+func (c *Catalog) Negotiate(header string) string {
+	locales := c.Locales()
+	// Offer the default locale first so "*" selects it
+	supported := append([]string{c.defaultLocale}, locales...)
+	if locale := Negotiate(header, supported...); locale != "" {
+		return locale
+	}
+	return c.defaultLocale
+}
+
+// RequestLocale returns the locale stored in the request context by
+// Middleware, or negotiates one from the request's Accept-Language header
+func (c *Catalog) RequestLocale(r *http.Request) string {
+	if locale := LocaleFromContext(r.Context()); locale != "" {
+		return locale
+	}
+	return c.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// Middleware negotiates the locale of each request, stores it in the
+// request context and sets the Content-Language response header
+func (c *Catalog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := c.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", locale)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+	})
+}
@@ -0,0 +1,146 @@
+package i18n
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/arbenlabs/stoner/assert"
+	apperrors "github.com/arbenlabs/stoner/errors"
+)
+
+// **************************************************
+// Translating Errors
+// Validation errors are translated by rule code under "validation.",
+// with the {field} and {param} placeholders. Problem responses use
+// "status.<code>" for titles and "error.<code>" for generic details.
+// English messages for all built-in codes are in English().
+// **************************************************
+
+// Message code prefixes and codes used to translate errors
+const (
+	ValidationPrefix  = "validation."        // + assert rule code, e.g. "validation.required"
+	ErrorPrefix       = "error."             // + errors code, e.g. "error.not_found"
+	StatusPrefix      = "status."            // + HTTP status, e.g. "status.404"
+	ValidationSummary = "validation.summary" // detail of a validation problem, with {count}
+)
+
+// English returns the English messages for the built-in validation rules,
+// error codes and statuses, to add to a catalog or to translate from
+func English() map[string]string {
+	return map[string]string{
+		ValidationPrefix + assert.CodeRequired:   "{field} is required",
+		ValidationPrefix + assert.CodeEmail:      "{field} must be a valid email address",
+		ValidationPrefix + assert.CodeURL:        "{field} must be a valid URL",
+		ValidationPrefix + assert.CodeUUID:       "{field} must be a valid UUID",
+		ValidationPrefix + assert.CodeJSON:       "{field} must be valid JSON",
+		ValidationPrefix + assert.CodeMin:        "{field} must be at least {param}",
+		ValidationPrefix + assert.CodeMax:        "{field} must be at most {param}",
+		ValidationPrefix + assert.CodeOneOf:      "{field} must be one of: {param}",
+		ValidationPrefix + assert.CodeIP:         "{field} must be a valid IP address",
+		ValidationPrefix + assert.CodeIPv4:       "{field} must be a valid IPv4 address",
+		ValidationPrefix + assert.CodeIPv6:       "{field} must be a valid IPv6 address",
+		ValidationPrefix + assert.CodeCIDR:       "{field} must be a valid CIDR block",
+		ValidationPrefix + assert.CodePort:       "{field} must be a valid port",
+		ValidationPrefix + assert.CodeHostname:   "{field} must be a valid hostname",
+		ValidationPrefix + assert.CodeMAC:        "{field} must be a valid MAC address",
+		ValidationPrefix + assert.CodeSemver:     "{field} must be a semantic version",
+		ValidationPrefix + assert.CodeCreditCard: "{field} must be a valid card number",
+		ValidationPrefix + assert.CodeIBAN:       "{field} must be a valid IBAN",
+		ValidationPrefix + assert.CodeCurrency:   "{field} must be a valid currency code",
+		ValidationPrefix + assert.CodeCountry:    "{field} must be a valid country code",
+		ValidationPrefix + assert.CodeLatitude:   "{field} must be a valid latitude",
+		ValidationPrefix + assert.CodeLongitude:  "{field} must be a valid longitude",
+		ValidationPrefix + assert.CodeRequiredIf: "{field} is required",
+		ValidationPrefix + assert.CodeEqField:    "{field} must match {param}",
+		ValidationPrefix + assert.CodeNeField:    "{field} must differ from {param}",
+		ValidationPrefix + assert.CodeGtField:    "{field} must be greater than {param}",
+		ValidationPrefix + assert.CodeGteField:   "{field} must be at least {param}",
+		ValidationPrefix + assert.CodeLtField:    "{field} must be less than {param}",
+		ValidationPrefix + assert.CodeLteField:   "{field} must be at most {param}",
+		ValidationSummary:                        "{count} field(s) failed validation",
+
+		ErrorPrefix + string(apperrors.CodeInvalid):         "the request is invalid",
+		ErrorPrefix + string(apperrors.CodeUnauthenticated): "authentication is required",
+		ErrorPrefix + string(apperrors.CodeForbidden):       "you do not have access to this resource",
+		ErrorPrefix + string(apperrors.CodeNotFound):        "the resource was not found",
+		ErrorPrefix + string(apperrors.CodeConflict):        "the resource already exists or was changed",
+		ErrorPrefix + string(apperrors.CodeRateLimited):     "too many requests, try again later",
+		ErrorPrefix + string(apperrors.CodeUnavailable):     "the service is unavailable, try again later",
+		ErrorPrefix + string(apperrors.CodeTimeout):         "the request timed out",
+		ErrorPrefix + string(apperrors.CodeInternal):        "internal server error",
+
+		StatusPrefix + "400": "Bad Request",
+		StatusPrefix + "401": "Unauthorized",
+		StatusPrefix + "403": "Forbidden",
+		StatusPrefix + "404": "Not Found",
+		StatusPrefix + "409": "Conflict",
+		StatusPrefix + "422": "Validation failed",
+		StatusPrefix + "429": "Too Many Requests",
+		StatusPrefix + "500": "Internal Server Error",
+		StatusPrefix + "503": "Service Unavailable",
+		StatusPrefix + "504": "Gateway Timeout",
+	}
+}
+
+// TranslateFieldError translates the message of a field error by its rule
+// code. Errors from rules without a message keep their message.
+func (c *Catalog) TranslateFieldError(locale string, fieldErr assert.FieldError) assert.FieldError {
+	params := Params{"field": fieldErr.Field, "param": fieldErr.Param}
+	if message, ok := c.Lookup(locale, ValidationPrefix+fieldErr.Code, params); ok {
+		fieldErr.Message = message
+	}
+	return fieldErr
+}
+
+// TranslateValidationErrors returns a translated copy of errs
+func (c *Catalog) TranslateValidationErrors(locale string, errs assert.ValidationErrors) assert.ValidationErrors {
+	translated := make(assert.ValidationErrors, len(errs))
+	for i, fieldErr := range errs {
+		translated[i] = c.TranslateFieldError(locale, fieldErr)
+	}
+	return translated
+}
+
+// TranslateProblem translates a problem body written for err, as built by
+// middleware.WriteError. The title is translated by status; field errors
+// by rule code. The detail is translated when the catalog has the error's
+// public message as a code, so apps can key their own messages; otherwise
+// errors without a client message (internal and uncoded errors) get the
+// generic message of their code, and other messages are kept.
+func (c *Catalog) TranslateProblem(locale string, problem assert.ProblemDetails, err error) assert.ProblemDetails {
+	if title, ok := c.Lookup(locale, StatusPrefix+strconv.Itoa(problem.Status), nil); ok {
+		problem.Title = title
+	}
+
+	if len(problem.Errors) > 0 {
+		problem.Errors = c.TranslateValidationErrors(locale, problem.Errors)
+		if detail, ok := c.Lookup(locale, ValidationSummary, Params{"count": len(problem.Errors)}); ok {
+			problem.Detail = detail
+		}
+		return problem
+	}
+
+	if detail, ok := c.Lookup(locale, problem.Detail, nil); ok && problem.Detail != "" {
+		problem.Detail = detail
+		return problem
+	}
+	if e, ok := apperrors.From(err); !ok || e.Code == apperrors.CodeInternal || e.Message == "" {
+		code := apperrors.CodeOf(err)
+		if err == nil {
+			code = apperrors.CodeInternal
+		}
+		if detail, ok := c.Lookup(locale, ErrorPrefix+string(code), nil); ok {
+			problem.Detail = detail
+		}
+	}
+	return problem
+}
+
+// StatusText returns the translated text of an HTTP status, or the
+// standard text when the catalog has none
+func (c *Catalog) StatusText(locale string, status int) string {
+	if text, ok := c.Lookup(locale, StatusPrefix+strconv.Itoa(status), nil); ok {
+		return text
+	}
+	return http.StatusText(status)
+}
@@ -11,6 +11,8 @@ import (
 	"context"
 
 	"os"
+
+	"github.com/arbenlabs/stoner/sanitize"
 )
 
 // **************************************************
@@ -31,8 +33,13 @@ type LoggerConfig struct {
 	ServiceVersion     string
 	ServiceEnvironment string
 	Writer             io.Writer
+	AttrTransformer    AttrTransformer
 }
 
+// AttrTransformer rewrites attributes before they are written, after the
+// logger's own formatting of the time and source attributes.
+type AttrTransformer func(groups []string, a slog.Attr) slog.Attr
+
 var defaultLogger *Logger
 
 // NewLogger creates a new logger with a specific logger config.
@@ -59,6 +66,10 @@ func NewLogger(config *LoggerConfig) (*Logger, error) {
 					Value: slog.StringValue(a.Value.Time().Format(time.RFC3339)),
 				}
 			}
+
+			if config.AttrTransformer != nil {
+				return config.AttrTransformer(groups, a)
+			}
 			return a
 		},
 	}
@@ -93,6 +104,30 @@ func NewLoggerConfig(serviceName string, shouldAddSource bool, serviceVersion st
 	}
 }
 
+// WithSanitizedAttrs sets an attribute transformer that sanitizes string and
+// error values with sanitize.SanitizeForLogWithLimit, escaping line breaks
+// and stripping terminal escape sequences from untrusted input.
+func (c *LoggerConfig) WithSanitizedAttrs(maxLength int) *LoggerConfig {
+	c.AttrTransformer = SanitizeAttr(maxLength)
+	return c
+}
+
+// SanitizeAttr returns an attribute transformer that sanitizes string and
+// error values, truncating them to maxLength characters.
+func SanitizeAttr(maxLength int) AttrTransformer {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		switch a.Value.Kind() {
+		case slog.KindString:
+			a.Value = slog.StringValue(sanitize.SanitizeForLogWithLimit(a.Value.String(), maxLength))
+		case slog.KindAny:
+			if err, ok := a.Value.Any().(error); ok {
+				a.Value = slog.StringValue(sanitize.SanitizeForLogWithLimit(err.Error(), maxLength))
+			}
+		}
+		return a
+	}
+}
+
 type contextKey string
 
 const (
@@ -100,6 +135,7 @@ const (
 	RequestIDKey contextKey = "request_id"
 	UserIDKey    contextKey = "user_id"
 	SessionIDKey contextKey = "session_id"
+	RouteKey     contextKey = "route"
 )
 
 // withSlog wraps the slog.Logger with additional functionality.
@@ -171,6 +207,10 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 		logger = logger.With("session_id", sessionID)
 	}
 
+	if route, ok := ctx.Value(RouteKey).(string); ok && route != "" {
+		logger = logger.With("route", route)
+	}
+
 	return &Logger{
 		Logger: logger,
 		config: l.config,
@@ -198,15 +238,43 @@ func (l *Logger) ErrorIf(condition bool, msg string, fields ...interface{}) {
 	}
 }
 
-// ErrorWithStack logs an error message with a stack trace.
+// StackTracer is implemented by errors that record the stack where they
+// were created, such as those of the errors package.
+type StackTracer interface {
+	StackTrace() string
+}
+
+// CodedError is implemented by errors that carry a code and context fields,
+// such as those of the errors package.
+type CodedError interface {
+	ErrorCode() string
+	ErrorFields() map[string]any
+}
+
+// ErrorWithStack logs an error message with a stack trace. When err records
+// its own stack, that stack is logged instead of the caller's, and the code
+// and fields of coded errors are logged as error_code and error_details.
 func (l *Logger) ErrorWithStack(msg string, err error, fields ...interface{}) {
 	args := []interface{}{"error", err.Error()}
+
+	var coded CodedError
+	if errors.As(err, &coded) {
+		args = append(args, "error_code", coded.ErrorCode())
+		if details := coded.ErrorFields(); len(details) > 0 {
+			args = append(args, "error_details", details)
+		}
+	}
 	args = append(args, fields...)
 
 	if l.config.Level <= slog.LevelDebug {
-		stack := make([]byte, 4096)
-		length := runtime.Stack(stack, false)
-		args = append(args, "stack_trace", string(stack[:length]))
+		var tracer StackTracer
+		if errors.As(err, &tracer) {
+			args = append(args, "stack_trace", tracer.StackTrace())
+		} else {
+			stack := make([]byte, 4096)
+			length := runtime.Stack(stack, false)
+			args = append(args, "stack_trace", string(stack[:length]))
+		}
 	}
 
 	l.Error(msg, args...)
@@ -302,6 +370,11 @@ func ContextWithUserID(ctx context.Context, userID interface{}) context.Context
 	return context.WithValue(ctx, UserIDKey, userID)
 }
 
+// ContextWithRoute wraps the context.Context with a route, e.g. "POST:/orders".
+func ContextWithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, RouteKey, route)
+}
+
 // LogHTTPRequest logs an HTTP request.
 func (l *Logger) LogHTTPRequest(method, path, userAgent, clientIP string, contentType string) {
 	l.Info("HTTP request",
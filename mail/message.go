@@ -0,0 +1,340 @@
+// Package mail builds multipart emails and sends them over SMTP or through
+// any API-based provider implementing Sender.
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arbenlabs/stoner/sanitize"
+)
+
+// **************************************************
+// Message
+// A Message is rendered as multipart/alternative (plain text and
+// HTML) inside multipart/mixed when it has attachments. A
+// missing plain-text body is generated from the HTML.
+// **************************************************
+
+var (
+	// ErrInvalidMessage is returned when a message cannot be sent as built
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// Message is an email message
+type Message struct {
+	From        string            // sender address, e.g. "App <no-reply@example.com>"
+	To          []string          // recipient addresses
+	Cc          []string          // carbon copy addresses
+	Bcc         []string          // blind carbon copy addresses; never written to headers
+	ReplyTo     string            // optional reply address
+	Subject     string            // subject line; non-ASCII is encoded
+	Text        string            // plain-text body; generated from HTML when empty
+	HTML        string            // HTML body
+	Headers     map[string]string // extra headers, e.g. List-Unsubscribe
+	Attachments []Attachment      // files attached to the message
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Filename    string // file name shown to the recipient
+	ContentType string // MIME type; detected from Filename when empty
+	Data        []byte // file contents
+	Inline      bool   // display inline, referenced from HTML as cid:ContentID
+	ContentID   string // content ID for inline attachments; defaults to Filename
+}
+
+// Attach adds a file attachment
+func (m *Message) Attach(filename string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data})
+}
+
+// Embed adds an inline attachment that the HTML body references as
+// cid:contentID, e.g. <img src="cid:logo">
+func (m *Message) Embed(contentID, filename string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data, Inline: true, ContentID: contentID})
+}
+
+// Recipients returns the envelope recipients: To, Cc and Bcc addresses
+// without display names
+func (m *Message) Recipients() ([]string, error) {
+	var recipients []string
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, raw := range list {
+			address, err := netmail.ParseAddress(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: recipient %q: %v", ErrInvalidMessage, raw, err)
+			}
+			recipients = append(recipients, address.Address)
+		}
+	}
+	return recipients, nil
+}
+
+// Validate checks that the message has a sender, recipients, a body and
+// well-formed headers without line breaks
+func (m *Message) Validate() error {
+	if _, err := netmail.ParseAddress(m.From); err != nil {
+		return fmt.Errorf("%w: from %q: %v", ErrInvalidMessage, m.From, err)
+	}
+	if m.ReplyTo != "" {
+		if _, err := netmail.ParseAddress(m.ReplyTo); err != nil {
+			return fmt.Errorf("%w: reply-to %q: %v", ErrInvalidMessage, m.ReplyTo, err)
+		}
+	}
+
+	recipients, err := m.Recipients()
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("%w: no recipients", ErrInvalidMessage)
+	}
+	if m.Text == "" && m.HTML == "" {
+		return fmt.Errorf("%w: empty body", ErrInvalidMessage)
+	}
+
+	if hasLineBreak(m.Subject) {
+		return fmt.Errorf("%w: subject contains a line break", ErrInvalidMessage)
+	}
+	for key, value := range m.Headers {
+		if key == "" || hasLineBreak(key) || strings.ContainsAny(key, ": ") || hasLineBreak(value) {
+			return fmt.Errorf("%w: header %q", ErrInvalidMessage, key)
+		}
+	}
+	for _, attachment := range m.Attachments {
+		if attachment.Filename == "" || hasLineBreak(attachment.Filename) || hasLineBreak(attachment.ContentID) || hasLineBreak(attachment.ContentType) {
+			return fmt.Errorf("%w: attachment %q", ErrInvalidMessage, attachment.Filename)
+		}
+	}
+	return nil
+}
+
+// Bytes validates the message and renders it in RFC 5322 format
+func (m *Message) Bytes() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	from, _ := netmail.ParseAddress(m.From)
+	header := textproto.MIMEHeader{}
+	header.Set("From", from.String())
+	if to, err := formatAddressList(m.To); err != nil {
+		return nil, err
+	} else if to != "" {
+		header.Set("To", to)
+	}
+	if cc, err := formatAddressList(m.Cc); err != nil {
+		return nil, err
+	} else if cc != "" {
+		header.Set("Cc", cc)
+	}
+	if m.ReplyTo != "" {
+		replyTo, _ := netmail.ParseAddress(m.ReplyTo)
+		header.Set("Reply-To", replyTo.String())
+	}
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", messageID(from.Address))
+	header.Set("MIME-Version", "1.0")
+	for key, value := range m.Headers {
+		header.Set(key, mime.QEncoding.Encode("utf-8", value))
+	}
+
+	var buf bytes.Buffer
+	if len(m.Attachments) == 0 {
+		body, err := m.renderBody(header)
+		if err != nil {
+			return nil, err
+		}
+		writeHeader(&buf, header)
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&buf)
+	header.Set("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+	writeHeader(&buf, header)
+
+	bodyHeader := textproto.MIMEHeader{}
+	body, err := m.renderBody(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	part, err := mixed.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(body); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range m.Attachments {
+		if err := writeAttachment(mixed, attachment); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderBody renders the text and HTML bodies and sets their content type
+// on header
+func (m *Message) renderBody(header textproto.MIMEHeader) ([]byte, error) {
+	text := m.Text
+	if text == "" {
+		text = sanitize.HTMLToText(m.HTML)
+	}
+
+	var buf bytes.Buffer
+	if m.HTML == "" {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		if err := writeQuotedPrintable(&buf, text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	alternative := multipart.NewWriter(&buf)
+	header.Set("Content-Type", "multipart/alternative; boundary="+alternative.Boundary())
+
+	for _, body := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		part, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {body.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(part, body.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeAttachment writes one base64-encoded attachment part
+func writeAttachment(w *multipart.Writer, attachment Attachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	header := textproto.MIMEHeader{}
+	if attachment.Inline {
+		disposition = "inline"
+		contentID := attachment.ContentID
+		if contentID == "" {
+			contentID = attachment.Filename
+		}
+		header.Set("Content-ID", "<"+contentID+">")
+	}
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename}))
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	// Base64 lines are limited to 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(part, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = fmt.Fprintf(part, "%s\r\n", encoded)
+	return err
+}
+
+// writeHeader writes header fields in a stable order followed by a blank line
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+// writeQuotedPrintable writes s with quoted-printable encoding
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// parseAddress returns the bare address of raw, without a display name
+func parseAddress(raw string) (string, error) {
+	address, err := netmail.ParseAddress(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: address %q: %v", ErrInvalidMessage, raw, err)
+	}
+	return address.Address, nil
+}
+
+// formatAddressList parses and formats a list of addresses
+func formatAddressList(list []string) (string, error) {
+	formatted := make([]string, 0, len(list))
+	for _, raw := range list {
+		address, err := netmail.ParseAddress(raw)
+		if err != nil {
+			return "", fmt.Errorf("%w: address %q: %v", ErrInvalidMessage, raw, err)
+		}
+		formatted = append(formatted, address.String())
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(id), domain)
+}
+
+// hasLineBreak checks for characters that would start a new header line
+func hasLineBreak(s string) bool {
+	return strings.ContainsAny(s, "\r\n")
+}
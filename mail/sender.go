@@ -0,0 +1,242 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Senders
+// Sender delivers messages. SMTPSender speaks SMTP with TLS;
+// API-based providers implement Sender directly, and
+// MemorySender records messages for tests and development.
+// **************************************************
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SenderFunc adapts a function to Sender
+type SenderFunc func(ctx context.Context, msg *Message) error
+
+// Send calls f
+func (f SenderFunc) Send(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// TLSMode selects how an SMTP connection is secured
+type TLSMode int
+
+const (
+	// TLSStartTLS upgrades a plain connection with STARTTLS and fails if
+	// the server does not support it (usually port 587)
+	TLSStartTLS TLSMode = iota
+	// TLSImplicit connects over TLS from the start (usually port 465)
+	TLSImplicit
+	// TLSOpportunistic uses STARTTLS when the server offers it
+	TLSOpportunistic
+	// TLSNone never uses TLS; only for local relays and test servers
+	TLSNone
+)
+
+// ErrTLSRequired is returned when the server does not offer STARTTLS
+var ErrTLSRequired = errors.New("smtp server does not support STARTTLS")
+
+// SMTPConfig configures an SMTPSender
+type SMTPConfig struct {
+	Host      string        // server host name
+	Port      int           // server port; 0 uses 587, or 465 for TLSImplicit
+	Username  string        // user for PLAIN authentication; empty disables auth
+	Password  string        // password for PLAIN authentication
+	TLSMode   TLSMode       // how the connection is secured
+	TLSConfig *tls.Config   // optional TLS settings; ServerName defaults to Host
+	LocalName string        // name sent in HELO/EHLO; empty uses "localhost"
+	Timeout   time.Duration // limit for the whole exchange when ctx has no deadline; 0 uses 30s
+}
+
+// SMTPSender sends messages through an SMTP server
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates a new SMTP sender
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	if config.Port == 0 {
+		config.Port = 587
+		if config.TLSMode == TLSImplicit {
+			config.Port = 465
+		}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &SMTPSender{config: config}
+}
+
+// Send delivers msg to all of its recipients
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	recipients, err := msg.Recipients()
+	if err != nil {
+		return err
+	}
+	from, err := parseAddress(msg.From)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		defer cancel()
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	// Abort the exchange if ctx is canceled before the deadline
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if err := s.deliver(client, from, recipients, data); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// dial opens the connection, over TLS for TLSImplicit
+func (s *SMTPSender) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	if s.config.TLSMode == TLSImplicit {
+		dialer := &tls.Dialer{Config: s.tlsConfig()}
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// deliver runs the SMTP exchange on an open client
+func (s *SMTPSender) deliver(client *smtp.Client, from string, recipients []string, data []byte) error {
+	localName := s.config.LocalName
+	if localName == "" {
+		localName = "localhost"
+	}
+	if err := client.Hello(localName); err != nil {
+		return err
+	}
+
+	if s.config.TLSMode == TLSStartTLS || s.config.TLSMode == TLSOpportunistic {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(s.tlsConfig()); err != nil {
+				return fmt.Errorf("failed to start tls: %w", err)
+			}
+		} else if s.config.TLSMode == TLSStartTLS {
+			return ErrTLSRequired
+		}
+	}
+
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// tlsConfig returns the TLS settings with ServerName defaulted
+func (s *SMTPSender) tlsConfig() *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.config.TLSConfig != nil {
+		config = s.config.TLSConfig.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = s.config.Host
+	}
+	return config
+}
+
+// MemorySender records sent messages instead of delivering them
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemorySender creates a new in-memory sender
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+// Send validates and records msg
+func (s *MemorySender) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, *msg)
+	return nil
+}
+
+// Messages returns the recorded messages
+func (s *MemorySender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Reset removes the recorded messages
+func (s *MemorySender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+}
@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// **************************************************
+// Templates
+// A Template renders a subject, a plain-text body and an HTML
+// body from the same data. HTML is rendered with html/template so
+// values are escaped.
+// **************************************************
+
+// Template renders the subject and bodies of a message
+type Template struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// ParseTemplate parses a subject template and text and HTML body templates.
+// Either body may be empty, but not both.
+func ParseTemplate(subject, text, html string) (*Template, error) {
+	if text == "" && html == "" {
+		return nil, errors.New("mail template needs a text or HTML body")
+	}
+
+	t := &Template{}
+	var err error
+	if t.subject, err = texttemplate.New("subject").Parse(subject); err != nil {
+		return nil, fmt.Errorf("failed to parse subject template: %w", err)
+	}
+	if text != "" {
+		if t.text, err = texttemplate.New("text").Parse(text); err != nil {
+			return nil, fmt.Errorf("failed to parse text template: %w", err)
+		}
+	}
+	if html != "" {
+		if t.html, err = htmltemplate.New("html").Parse(html); err != nil {
+			return nil, fmt.Errorf("failed to parse html template: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// ParseTemplateFS parses the templates of name from fsys: name.subject,
+// and name.txt and/or name.html. Use it with embed.FS.
+func ParseTemplateFS(fsys fs.FS, name string) (*Template, error) {
+	subject, err := fs.ReadFile(fsys, name+".subject")
+	if err != nil {
+		return nil, err
+	}
+	text, err := readOptional(fsys, name+".txt")
+	if err != nil {
+		return nil, err
+	}
+	html, err := readOptional(fsys, name+".html")
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplate(strings.TrimSpace(string(subject)), text, html)
+}
+
+// Render executes the templates with data and sets the subject and bodies
+// of msg
+func (t *Template) Render(msg *Message, data any) error {
+	var buf bytes.Buffer
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render subject: %w", err)
+	}
+	// A subject must be a single line
+	msg.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	msg.Text, msg.HTML = "", ""
+	if t.text != nil {
+		buf.Reset()
+		if err := t.text.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to render text body: %w", err)
+		}
+		msg.Text = buf.String()
+	}
+	if t.html != nil {
+		buf.Reset()
+		if err := t.html.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to render html body: %w", err)
+		}
+		msg.HTML = buf.String()
+	}
+	return nil
+}
+
+// readOptional reads a file, returning "" if it does not exist
+func readOptional(fsys fs.FS, name string) (string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
@@ -2,9 +2,14 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/arbenlabs/stoner/assert"
+	apperrors "github.com/arbenlabs/stoner/errors"
+	"github.com/arbenlabs/stoner/i18n"
 	"github.com/arbenlabs/stoner/logger"
 
 	"github.com/gorilla/csrf"
@@ -128,3 +133,59 @@ func (m *Middleware) LogHTTRequest(next http.Handler) http.Handler {
 func (m *Middleware) CSRFMiddleware(authKey []byte, secure bool) func(http.Handler) http.Handler {
 	return csrf.Protect(authKey, csrf.Secure(secure))
 }
+
+// WriteProblem writes an RFC 7807 problem body with its status code.
+func WriteProblem(w http.ResponseWriter, problem assert.ProblemDetails) {
+	w.Header().Set("Content-Type", assert.ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// WriteValidationError writes a validation failure from assert.ValidateStruct
+// as a 422 problem body, or a 400 problem body for any other error.
+func WriteValidationError(w http.ResponseWriter, err error) {
+	var validationErrs assert.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		WriteProblem(w, validationErrs.Problem())
+		return
+	}
+	WriteProblem(w, assert.ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	})
+}
+
+// WriteError writes err as a problem body. Validation failures from
+// assert.ValidateStruct are written as 422; coded errors from the errors
+// package use their code's status and public message; any other error is
+// written as a 500 without exposing its message.
+func WriteError(w http.ResponseWriter, err error) {
+	WriteProblem(w, errorProblem(err))
+}
+
+// WriteLocalizedError writes err as WriteError does, translated into the
+// request's locale from catalog. The locale is the one stored by
+// catalog.Middleware, or is negotiated from the Accept-Language header.
+func WriteLocalizedError(w http.ResponseWriter, r *http.Request, catalog *i18n.Catalog, err error) {
+	locale := catalog.RequestLocale(r)
+	w.Header().Set("Content-Language", locale)
+	WriteProblem(w, catalog.TranslateProblem(locale, errorProblem(err), err))
+}
+
+// errorProblem builds the problem body of err
+func errorProblem(err error) assert.ProblemDetails {
+	var validationErrs assert.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return validationErrs.Problem()
+	}
+
+	status := apperrors.HTTPStatus(err)
+	return assert.ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: apperrors.PublicMessage(err),
+	}
+}
@@ -0,0 +1,61 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arbenlabs/stoner/assert"
+)
+
+// **************************************************
+// Currencies
+// Currencies are ISO 4217 codes. The exponent is the number of
+// minor-unit digits: 2 for USD cents, 0 for JPY, 3 for KWD.
+// **************************************************
+
+// currencyExponents lists the currencies whose exponent is not 2. Codes
+// without a minor unit in ISO 4217, such as precious metals, use 0.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"XAG": 0, "XAU": 0, "XBA": 0, "XBB": 0, "XBC": 0, "XBD": 0, "XDR": 0,
+	"XPD": 0, "XPT": 0, "XSU": 0, "XTS": 0, "XUA": 0, "XXX": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Exponent returns the number of minor-unit digits of a currency
+func Exponent(currency string) (int, error) {
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return 0, err
+	}
+	return exponent(currency), nil
+}
+
+// exponent returns the exponent of a normalized currency code
+func exponent(currency string) int {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// normalizeCurrency upper-cases and validates a currency code
+func normalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if err := assert.AssertValidCurrencyCode(currency); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCurrency, err)
+	}
+	return currency, nil
+}
+
+// pow10 returns 10^n for small n
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// Parsing, Formatting and Encoding
+// Amounts are written as exact decimal strings in major units:
+// "19.99 USD" as text and {"amount":"19.99","currency":"USD"} in
+// JSON. In SQL they are stored as integer minor units.
+// **************************************************
+
+// ParseAmount parses a decimal amount in major units, e.g. "19.99" or
+// "-1,234.5". Commas are ignored; more decimal places than the currency
+// allows is an error.
+func ParseAmount(amount, currency string) (Money, error) {
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	minor, err := parseMinor(amount, exponent(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: currency}, nil
+}
+
+// Parse parses an amount with its currency, as "19.99 USD" or "USD 19.99"
+func Parse(s string) (Money, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Money{}, fmt.Errorf("%w: %q, expected amount and currency", ErrInvalidAmount, s)
+	}
+	if _, err := normalizeCurrency(fields[0]); err == nil {
+		return ParseAmount(fields[1], fields[0])
+	}
+	return ParseAmount(fields[0], fields[1])
+}
+
+// parseMinor converts a decimal string to minor units with exp digits
+func parseMinor(amount string, exp int) (int64, error) {
+	raw := amount
+	amount = strings.ReplaceAll(strings.TrimSpace(amount), ",", "")
+
+	negative := false
+	switch {
+	case strings.HasPrefix(amount, "-"):
+		negative, amount = true, amount[1:]
+	case strings.HasPrefix(amount, "+"):
+		amount = amount[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(amount, ".")
+	if whole == "" && fraction == "" || hasPoint && fraction == "" || len(fraction) > exp ||
+		strings.Trim(whole, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", exp-len(fraction))
+	if negative {
+		digits = "-" + digits
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, raw)
+	}
+	return minor, nil
+}
+
+// Amount returns the amount in major units as a decimal string, e.g. "19.99"
+func (m Money) Amount() string {
+	return m.Format("", ".")
+}
+
+// Format returns the amount in major units with a thousands separator and
+// decimal mark, e.g. Format(",", ".") gives "1,234.50" and
+// Format(".", ",") gives "1.234,50"
+func (m Money) Format(thousands, decimal string) string {
+	exp := exponent(m.currency)
+
+	digits := strconv.FormatInt(m.minor, 10)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-exp], digits[len(digits)-exp:]
+	if thousands != "" {
+		var grouped strings.Builder
+		for i, digit := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				grouped.WriteString(thousands)
+			}
+			grouped.WriteRune(digit)
+		}
+		whole = grouped.String()
+	}
+
+	if exp == 0 {
+		return sign + whole
+	}
+	return sign + whole + decimal + fraction
+}
+
+// String returns the amount and currency, e.g. "19.99 USD"
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.Amount()
+	}
+	return m.Amount() + " " + m.currency
+}
+
+// jsonMoney is the JSON form of Money
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string with its currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: m.Amount(), Currency: m.currency})
+}
+
+// UnmarshalJSON decodes {"amount":"19.99","currency":"USD"}; the amount may
+// also be a JSON number, which is read as an exact decimal
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var decoded jsonMoney
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		var text string
+		if json.Unmarshal(data, &text) != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+		}
+		return m.UnmarshalText([]byte(text))
+	}
+
+	parsed, err := ParseAmount(decoded.Amount.String(), decoded.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalText encodes the amount as "19.99 USD"
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes "19.99 USD" or "USD 19.99"
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as its minor units, so SQL comparisons and range
+// filters on the column are numeric. The currency is not stored: keep it
+// in a column of its own, or fix it per field, and restore it after a read
+// with WithCurrency. The zero Money is stored as NULL.
+func (m Money) Value() (driver.Value, error) {
+	if m.currency == "" && m.minor == 0 {
+		return nil, nil
+	}
+	return m.minor, nil
+}
+
+// Scan reads minor units stored by Value, keeping the currency of m, which
+// is empty unless m was set before the scan. Text such as "19.99 USD", as
+// stored by earlier versions, is still read with its currency.
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case int64:
+		m.minor = v
+		return nil
+	case string:
+		return m.scanText(v)
+	case []byte:
+		return m.scanText(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into money.Money", value)
+	}
+}
+
+// scanText reads integer minor units returned as text by some drivers, or
+// an amount with its currency
+func (m *Money) scanText(text string) error {
+	if minor, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64); err == nil {
+		m.minor = minor
+		return nil
+	}
+	return m.UnmarshalText([]byte(text))
+}
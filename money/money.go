@@ -0,0 +1,367 @@
+// Package money represents amounts of money as integer minor units with an
+// ISO 4217 currency, so prices are never rounded by floating point.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// **************************************************
+// Money
+// Money is an immutable amount in minor units (cents for USD).
+// Operations on two amounts require the same currency, and every
+// operation that can lose precision takes a RoundingMode.
+// **************************************************
+
+var (
+	// ErrInvalidCurrency is returned for unknown currency codes
+	ErrInvalidCurrency = errors.New("invalid currency")
+	// ErrCurrencyMismatch is returned when combining amounts in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrOverflow is returned when a result does not fit in int64 minor units
+	ErrOverflow = errors.New("money overflow")
+	// ErrInvalidAmount is returned for amounts that cannot be parsed or
+	// represented in the currency's minor units
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// RoundingMode selects how results between two minor units are rounded
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest unit, ties to even (banker's rounding)
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest unit, ties away from zero
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest unit, ties toward zero
+	RoundHalfDown
+	// RoundDown rounds toward zero (truncates)
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+)
+
+// Money is an amount in a currency's minor units
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// New creates an amount from minor units, e.g. New(1999, "USD") is $19.99
+func New(minor int64, currency string) (Money, error) {
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: currency}, nil
+}
+
+// MustNew creates an amount from minor units or panics, for constants
+func MustNew(minor int64, currency string) Money {
+	m, err := New(minor, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Zero returns a zero amount in currency
+func Zero(currency string) (Money, error) {
+	return New(0, currency)
+}
+
+// FromFloat converts a float amount in major units, rounding to minor units
+// with mode. It is meant for migrating float data; prefer ParseAmount.
+func FromFloat(amount float64, currency string, mode RoundingMode) (Money, error) {
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidAmount, amount)
+	}
+
+	// Round the shortest decimal form of the float, so 0.145 is not
+	// treated as 0.14499999...
+	r, ok := new(big.Rat).SetString(fmt.Sprint(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidAmount, amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(pow10(exponent(currency))))
+	minor, err := round(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: currency}, nil
+}
+
+// Minor returns the amount in minor units
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Currency returns the ISO 4217 currency code
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// WithCurrency returns the amount in currency, e.g. to restore the currency
+// of an amount read from SQL from its own column
+func (m Money) WithCurrency(currency string) (Money, error) {
+	return New(m.minor, currency)
+}
+
+// Float returns the amount in major units as a float, for display and
+// statistics only
+func (m Money) Float() float64 {
+	return float64(m.minor) / float64(pow10(exponent(m.currency)))
+}
+
+// IsZero checks if the amount is zero
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+// IsPositive checks if the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.minor > 0
+}
+
+// IsNegative checks if the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Abs returns the absolute amount
+func (m Money) Abs() (Money, error) {
+	if m.minor < 0 {
+		return m.Neg()
+	}
+	return m, nil
+}
+
+// Neg returns the negated amount
+func (m Money) Neg() (Money, error) {
+	if m.minor == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return Money{minor: -m.minor, currency: m.currency}, nil
+}
+
+// SameCurrency checks if two amounts have the same currency
+func (m Money) SameCurrency(other Money) bool {
+	return m.currency == other.currency
+}
+
+// Compare returns -1, 0 or 1 as m is less than, equal to or greater than other
+func (m Money) Compare(other Money) (int, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.minor < other.minor:
+		return -1, nil
+	case m.minor > other.minor:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Equal checks if two amounts have the same currency and value
+func (m Money) Equal(other Money) bool {
+	return m.currency == other.currency && m.minor == other.minor
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.minor + other.minor
+	if (other.minor > 0 && sum < m.minor) || (other.minor < 0 && sum > m.minor) {
+		return Money{}, ErrOverflow
+	}
+	return Money{minor: sum, currency: m.currency}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	diff := m.minor - other.minor
+	if (other.minor < 0 && diff < m.minor) || (other.minor > 0 && diff > m.minor) {
+		return Money{}, ErrOverflow
+	}
+	return Money{minor: diff, currency: m.currency}, nil
+}
+
+// Mul returns m multiplied by an integer, e.g. a unit price by a quantity
+func (m Money) Mul(factor int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(factor))
+	if !product.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{minor: product.Int64(), currency: m.currency}, nil
+}
+
+// MulRat returns m multiplied by a fraction, rounded with mode, e.g.
+// MulRat(big.NewRat(15, 100), RoundHalfUp) for 15%
+func (m Money) MulRat(factor *big.Rat, mode RoundingMode) (Money, error) {
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(m.minor), factor)
+	minor, err := round(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: minor, currency: m.currency}, nil
+}
+
+// Percent returns pct percent of m, rounded with mode. pct is a decimal
+// string such as "7.25" so the rate is exact.
+func (m Money) Percent(pct string, mode RoundingMode) (Money, error) {
+	rate, ok := new(big.Rat).SetString(pct)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: percentage %q", ErrInvalidAmount, pct)
+	}
+	return m.MulRat(rate.Quo(rate, big.NewRat(100, 1)), mode)
+}
+
+// Div returns m divided by an integer, rounded with mode. Use Split to
+// divide an amount into parts that add up to it.
+func (m Money) Div(divisor int64, mode RoundingMode) (Money, error) {
+	if divisor == 0 {
+		return Money{}, errors.New("money division by zero")
+	}
+	return m.MulRat(big.NewRat(1, divisor), mode)
+}
+
+// Allocate splits m into parts proportional to ratios without losing minor
+// units: the remainder is handed out one unit at a time, starting with the
+// first part. Allocate(1, 1, 1) of $100 gives $33.34, $33.33, $33.33.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("allocate needs at least one ratio")
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("allocate ratio %d is negative", ratio)
+		}
+		total.Add(total, big.NewInt(int64(ratio)))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("allocate ratios sum to zero")
+	}
+
+	parts := make([]Money, len(ratios))
+	remainder := m.minor
+	for i, ratio := range ratios {
+		// Truncating toward zero keeps every part's sign and leaves a
+		// remainder with the sign of m
+		share := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(int64(ratio)))
+		share.Quo(share, total)
+		parts[i] = Money{minor: share.Int64(), currency: m.currency}
+		remainder -= share.Int64()
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].minor += unit
+		remainder -= unit
+	}
+	return parts, nil
+}
+
+// Split divides m into n equal parts that add up to m
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("cannot split into %d parts", n)
+	}
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// Sum adds amounts of the same currency
+func Sum(amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, errors.New("sum needs at least one amount")
+	}
+	total := amounts[0]
+	for _, amount := range amounts[1:] {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// checkCurrency fails if two amounts have different currencies
+func (m Money) checkCurrency(other Money) error {
+	if m.currency != other.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+	return nil
+}
+
+// round rounds a rational number of minor units to an integer with mode
+func round(r *big.Rat, mode RoundingMode) (int64, error) {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		negative := r.Sign() < 0
+		// Compare twice the remainder with the denominator to find ties
+		half := new(big.Int).Abs(rem)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(r.Denom())
+
+		awayFromZero := false
+		switch mode {
+		case RoundHalfEven:
+			awayFromZero = cmp > 0 || (cmp == 0 && quo.Bit(0) == 1)
+		case RoundHalfUp:
+			awayFromZero = cmp >= 0
+		case RoundHalfDown:
+			awayFromZero = cmp > 0
+		case RoundDown:
+		case RoundUp:
+			awayFromZero = true
+		case RoundFloor:
+			awayFromZero = negative
+		case RoundCeiling:
+			awayFromZero = !negative
+		default:
+			return 0, fmt.Errorf("unknown rounding mode %d", mode)
+		}
+
+		if awayFromZero {
+			if negative {
+				quo.Sub(quo, big.NewInt(1))
+			} else {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+
+	if !quo.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return quo.Int64(), nil
+}
@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arbenlabs/stoner/crypto"
+)
+
+// **************************************************
+// Cursors
+// A cursor token is the JSON of the last item's sort key, base64url
+// encoded and signed with HMAC-SHA256, so clients can pass it back
+// but cannot forge or alter it.
+// **************************************************
+
+// ErrInvalidCursor is returned for malformed or tampered cursor tokens
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrCursorKeyTooShort is returned by NewCursorCodec for keys shorter than
+// MinCursorKeyLength
+var ErrCursorKeyTooShort = errors.New("cursor key too short")
+
+// MinCursorKeyLength is the minimum length in bytes of a cursor signing key
+const MinCursorKeyLength = 32
+
+// CursorCodec encodes and decodes signed cursor tokens
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec creates a codec signing cursors with key, which must be at
+// least MinCursorKeyLength random bytes and kept secret
+func NewCursorCodec(key []byte) (*CursorCodec, error) {
+	if len(key) < MinCursorKeyLength {
+		return nil, fmt.Errorf("%w: got %d bytes, need at least %d", ErrCursorKeyTooShort, len(key), MinCursorKeyLength)
+	}
+	return &CursorCodec{key: key}, nil
+}
+
+// Encode signs v, typically the sort key of the last item on a page
+func (c *CursorCodec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + crypto.SignHMAC(c.key, []byte(encoded)), nil
+}
+
+// Decode verifies token and decodes its value into v
+func (c *CursorCodec) Decode(token string, v any) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !crypto.VerifyHMAC(c.key, []byte(encoded), signature) {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// Link Headers
+// RFC 8288 Link headers let clients follow pages without knowing
+// the query parameters.
+// **************************************************
+
+// LinkHeader returns the Link header value for a page of results served
+// at base. Offset pages link first, prev, next and last; cursor pages link
+// next. Other query parameters of base are kept.
+func LinkHeader[T any](base *url.URL, page PageResponse[T]) string {
+	links := make([]string, 0, 4)
+	add := func(rel string, params map[string]string) {
+		u := *base
+		query := u.Query()
+		for key, value := range params {
+			if value == "" {
+				query.Del(key)
+			} else {
+				query.Set(key, value)
+			}
+		}
+		u.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+	pageSize := strconv.Itoa(page.PageSize)
+
+	if page.NextCursor != "" {
+		add("next", map[string]string{CursorParam: page.NextCursor, PageParam: "", PageSizeParam: pageSize})
+		return strings.Join(links, ", ")
+	}
+
+	if page.Page < 1 || page.TotalPages < 1 {
+		return ""
+	}
+	pageLink := func(rel string, n int) {
+		add(rel, map[string]string{PageParam: strconv.Itoa(n), PageSizeParam: pageSize, CursorParam: ""})
+	}
+	pageLink("first", 1)
+	if page.Page > 1 {
+		pageLink("prev", min(page.Page-1, page.TotalPages))
+	}
+	if page.Page < page.TotalPages {
+		pageLink("next", page.Page+1)
+	}
+	pageLink("last", page.TotalPages)
+	return strings.Join(links, ", ")
+}
+
+// SetLinkHeader sets the Link header for a page of results served for r,
+// along with an X-Total-Count header for offset pages
+func SetLinkHeader[T any](w http.ResponseWriter, r *http.Request, page PageResponse[T]) {
+	if link := LinkHeader(r.URL, page); link != "" {
+		w.Header().Set("Link", link)
+	}
+	if page.NextCursor == "" && page.Page > 0 {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(page.TotalItems, 10))
+	}
+}
@@ -0,0 +1,152 @@
+// Package pagination defines the page requests and responses shared by gq,
+// db.QueryBuilder and HTTP handlers: offset pages, signed cursor tokens
+// for keyset pagination, and Link headers.
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// **************************************************
+// Pages
+// A PageRequest selects either an offset page (Page and PageSize)
+// or, when Cursor is set, the page after a cursor. A PageResponse
+// returns the items with enough metadata to request the next page.
+// **************************************************
+
+// Page size limits
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 1000
+)
+
+// Query parameters read by FromRequest and written to Link headers
+const (
+	PageParam     = "page"
+	PageSizeParam = "page_size"
+	CursorParam   = "cursor"
+)
+
+// ErrInvalidPagination is returned for out-of-range page requests
+var ErrInvalidPagination = errors.New("invalid pagination")
+
+// PageRequest is a request for one page of results
+type PageRequest struct {
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+	Cursor   string `json:"cursor,omitempty"`
+}
+
+// NewPageRequest creates an offset page request
+func NewPageRequest(page, pageSize int) PageRequest {
+	return PageRequest{Page: page, PageSize: pageSize}
+}
+
+// Validate checks that Page is at least 1 and PageSize is between 1 and
+// MaxPageSize
+func (r PageRequest) Validate() error {
+	if r.Page < 1 {
+		return fmt.Errorf("%w: page must be >= 1", ErrInvalidPagination)
+	}
+	if r.PageSize < 1 || r.PageSize > MaxPageSize {
+		return fmt.Errorf("%w: page size must be between 1 and %d", ErrInvalidPagination, MaxPageSize)
+	}
+	return nil
+}
+
+// Offset returns the number of rows before the page
+func (r PageRequest) Offset() int {
+	if r.Page < 1 {
+		return 0
+	}
+	return (r.Page - 1) * r.PageSize
+}
+
+// Limit returns the number of rows in the page
+func (r PageRequest) Limit() int {
+	return r.PageSize
+}
+
+// FromRequest reads page, page_size and cursor query parameters. Missing
+// values default to page 1 and DefaultPageSize.
+func FromRequest(r *http.Request) (PageRequest, error) {
+	query := r.URL.Query()
+	req := PageRequest{
+		Page:     1,
+		PageSize: DefaultPageSize,
+		Cursor:   query.Get(CursorParam),
+	}
+
+	if value := query.Get(PageParam); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil {
+			return PageRequest{}, fmt.Errorf("%w: page must be an integer", ErrInvalidPagination)
+		}
+		req.Page = page
+	}
+	if value := query.Get(PageSizeParam); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil {
+			return PageRequest{}, fmt.Errorf("%w: page size must be an integer", ErrInvalidPagination)
+		}
+		req.PageSize = pageSize
+	}
+
+	if err := req.Validate(); err != nil {
+		return PageRequest{}, err
+	}
+	return req, nil
+}
+
+// PageResponse is one page of results. For offset pages, TotalItems and
+// TotalPages are set; for cursor pages, NextCursor is set when HasNext is.
+type PageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	TotalItems int64  `json:"total_items,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPageResponse creates the response to an offset page request
+func NewPageResponse[T any](items []T, req PageRequest, totalItems int64) PageResponse[T] {
+	if items == nil {
+		items = make([]T, 0)
+	}
+	totalPages := TotalPages(totalItems, req.PageSize)
+	return PageResponse[T]{
+		Items:      items,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+		HasNext:    req.Page < totalPages,
+	}
+}
+
+// NewCursorResponse creates the response to a cursor page request. An empty
+// nextCursor means there are no more pages.
+func NewCursorResponse[T any](items []T, req PageRequest, nextCursor string) PageResponse[T] {
+	if items == nil {
+		items = make([]T, 0)
+	}
+	return PageResponse[T]{
+		Items:      items,
+		PageSize:   req.PageSize,
+		HasNext:    nextCursor != "",
+		NextCursor: nextCursor,
+	}
+}
+
+// TotalPages returns the number of pages needed for totalItems
+func TotalPages(totalItems int64, pageSize int) int {
+	if pageSize < 1 || totalItems <= 0 {
+		return 0
+	}
+	return int((totalItems + int64(pageSize) - 1) / int64(pageSize))
+}
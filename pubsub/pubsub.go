@@ -0,0 +1,229 @@
+// Package pubsub is a small typed in-process event bus. Publishers and
+// subscribers share only the event type, so components such as pollers,
+// cron jobs and audit logging can react to each other without direct
+// coupling.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/arbenlabs/stoner/logger"
+	"github.com/arbenlabs/stoner/worker"
+)
+
+// **************************************************
+// Bus
+// Subscribers are registered per event type. Publish delivers an
+// event to every subscriber of its type on a worker pool;
+// PublishSync delivers it inline and returns the handlers' errors.
+// **************************************************
+
+// ErrBusClosed is returned when publishing to a bus that has been closed
+var ErrBusClosed = errors.New("event bus is closed")
+
+// BusConfig configures a Bus
+type BusConfig struct {
+	Workers   int // goroutines delivering async events; 0 uses runtime.NumCPU
+	QueueSize int // maximum number of pending deliveries; 0 means unbounded
+}
+
+// Bus is an in-process event bus
+type Bus struct {
+	pool *worker.Pool
+
+	mu           sync.RWMutex
+	subscribers  map[reflect.Type][]*Subscription
+	nextID       uint64
+	closed       bool
+	errorHandler func(event any, err error)
+	logger       *logger.Logger
+}
+
+// Subscription is a registered handler
+type Subscription struct {
+	bus       *Bus
+	eventType reflect.Type
+	id        uint64
+	deliver   func(ctx context.Context, event any) error
+}
+
+// delivery is one event for one subscriber, run on the bus's pool
+type delivery struct {
+	ctx          context.Context
+	event        any
+	subscription *Subscription
+}
+
+// Run delivers the event
+func (d *delivery) Run(context.Context) error {
+	return d.subscription.deliver(d.ctx, d.event)
+}
+
+// NewBus creates a new event bus with its delivery pool
+func NewBus(config BusConfig) *Bus {
+	b := &Bus{
+		pool:        worker.NewPool(worker.PoolConfig{Workers: config.Workers, QueueSize: config.QueueSize}),
+		subscribers: make(map[reflect.Type][]*Subscription),
+	}
+	b.pool.SetErrorHandler(func(job worker.Job, err error) {
+		var event any
+		if d, ok := job.(*delivery); ok {
+			event = d.event
+		}
+		b.reportError(event, err)
+	})
+	return b
+}
+
+// SetErrorHandler sets a function called with every error returned or
+// panic raised by an async handler. Without one, errors are logged if a
+// logger is set.
+func (b *Bus) SetErrorHandler(handler func(event any, err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errorHandler = handler
+}
+
+// SetLogger sets the logger used to report handler errors when no error
+// handler is set
+func (b *Bus) SetLogger(l *logger.Logger) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logger = l
+}
+
+// Subscribe registers handler for events of type T
+func Subscribe[T any](b *Bus, handler func(ctx context.Context, event T) error) *Subscription {
+	eventType := reflect.TypeFor[T]()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	sub := &Subscription{
+		bus:       b,
+		eventType: eventType,
+		id:        b.nextID,
+		deliver: func(ctx context.Context, event any) error {
+			return handler(ctx, event.(T))
+		},
+	}
+	b.subscribers[eventType] = append(b.subscribers[eventType], sub)
+	return sub
+}
+
+// Unsubscribe removes the subscription. Deliveries already queued still run.
+func (s *Subscription) Unsubscribe() {
+	b := s.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subscribers[s.eventType]
+	for i, sub := range subs {
+		if sub.id == s.id {
+			b.subscribers[s.eventType] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.subscribers[s.eventType]) == 0 {
+		delete(b.subscribers, s.eventType)
+	}
+}
+
+// Publish queues event for every subscriber of type T and returns without
+// waiting for them. Handler errors go to the bus's error handler. The
+// handlers receive ctx, so it should outlive the publisher's request; use
+// context.WithoutCancel to keep its values without its cancellation.
+func Publish[T any](ctx context.Context, b *Bus, event T) error {
+	subs, err := b.subscriptions(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		if err := b.pool.Submit(&delivery{ctx: ctx, event: event, subscription: sub}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue %T event: %w", event, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PublishSync delivers event to every subscriber of type T in turn and
+// returns their errors joined. Panics are recovered and returned as errors.
+func PublishSync[T any](ctx context.Context, b *Bus, event T) error {
+	subs, err := b.subscriptions(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		if err := deliverSync(ctx, sub, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SubscriberCount returns the number of subscribers for events of type T
+func SubscriberCount[T any](b *Bus) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[reflect.TypeFor[T]()])
+}
+
+// Close stops accepting events and waits for pending deliveries, or until
+// ctx is done
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	return b.pool.Shutdown(ctx)
+}
+
+// subscriptions returns a snapshot of the subscribers of an event type
+func (b *Bus) subscriptions(eventType reflect.Type) ([]*Subscription, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+	subs := b.subscribers[eventType]
+	return append([]*Subscription(nil), subs...), nil
+}
+
+// deliverSync runs one handler inline, recovering panics
+func deliverSync(ctx context.Context, sub *Subscription, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := make([]byte, 4096)
+			length := runtime.Stack(stack, false)
+			err = &worker.PanicError{Value: r, Stack: stack[:length]}
+		}
+	}()
+	return sub.deliver(ctx, event)
+}
+
+// reportError forwards a handler error to the error handler, or the logger
+func (b *Bus) reportError(event any, err error) {
+	b.mu.RLock()
+	handler := b.errorHandler
+	l := b.logger
+	b.mu.RUnlock()
+
+	if handler != nil {
+		handler(event, err)
+		return
+	}
+	if l != nil {
+		l.Error("Event handler failed", "event", fmt.Sprintf("%T", event), "error", err.Error())
+	}
+}
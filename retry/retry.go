@@ -0,0 +1,149 @@
+// Package retry runs operations with exponential backoff, jitter,
+// attempt and elapsed-time limits, and retryable-error classification.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	stonertime "github.com/arbenlabs/stoner/time"
+)
+
+// **************************************************
+// Retry
+// Do calls an operation until it succeeds, fails with an error
+// the policy does not retry, or the policy gives up. Delays come
+// from a time.Backoff built from the policy.
+// **************************************************
+
+// Default policy settings used by DefaultPolicy
+const (
+	DefaultMaxAttempts     = 5
+	DefaultInitialInterval = 100 * time.Millisecond
+	DefaultMultiplier      = 2.0
+	DefaultMaxInterval     = 10 * time.Second
+	DefaultJitter          = 0.2
+)
+
+// Policy configures how an operation is retried
+type Policy struct {
+	MaxAttempts     int           // total attempts including the first; 0 means no limit
+	InitialInterval time.Duration // delay before the first retry
+	Multiplier      float64       // growth factor per retry; values <= 0 are treated as 1
+	MaxInterval     time.Duration // upper bound for a single delay; 0 means no bound
+	MaxElapsedTime  time.Duration // stop once this much time has passed; 0 means no limit
+	Jitter          float64       // randomization factor from 0 (none) to 1
+
+	// Retryable reports whether an error should be retried. When nil, every
+	// error is retried except those marked with Permanent.
+	Retryable func(err error) bool
+
+	// OnRetry is called after a failed attempt, before waiting delay
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultPolicy returns a policy of 5 attempts starting at 100ms and doubling
+// up to 10s, with 20% jitter
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:     DefaultMaxAttempts,
+		InitialInterval: DefaultInitialInterval,
+		Multiplier:      DefaultMultiplier,
+		MaxInterval:     DefaultMaxInterval,
+		Jitter:          DefaultJitter,
+	}
+}
+
+// ExhaustedError is returned when the policy gives up while the operation
+// is still failing with a retryable error
+type ExhaustedError struct {
+	Attempts int
+	Err      error // error of the last attempt
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("retry failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that Do stops immediately and returns it,
+// regardless of the policy's Retryable function
+func Permanent(err error) error {
+	return stonertime.Permanent(err)
+}
+
+// Do calls fn until it succeeds or the policy stops retrying. A
+// non-retryable error is returned as is; when the policy gives up the last
+// error is returned in an *ExhaustedError; when ctx is done while waiting,
+// the context error and the last error are both wrapped.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is like Do for operations that return a value
+func DoValue[T any](ctx context.Context, policy Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	backoff := policy.backoff()
+
+	for attempt := 1; ; attempt++ {
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		var zero T
+		if !policy.retryable(err) {
+			return zero, err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return zero, fmt.Errorf("retry aborted after %d attempts: %w: %w", attempt, ctxErr, err)
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return zero, &ExhaustedError{Attempts: attempt, Err: err}
+		}
+		delay, ok := backoff.Next()
+		if !ok {
+			return zero, &ExhaustedError{Attempts: attempt, Err: err}
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, fmt.Errorf("retry aborted after %d attempts: %w: %w", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff builds the delay sequence of one Do call
+func (p Policy) backoff() *stonertime.Backoff {
+	return &stonertime.Backoff{
+		InitialInterval: p.InitialInterval,
+		Multiplier:      p.Multiplier,
+		MaxInterval:     p.MaxInterval,
+		MaxElapsedTime:  p.MaxElapsedTime,
+		Jitter:          p.Jitter,
+	}
+}
+
+// retryable classifies an error under the policy
+func (p Policy) retryable(err error) bool {
+	if stonertime.IsPermanent(err) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return true
+}
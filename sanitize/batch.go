@@ -0,0 +1,146 @@
+package sanitize
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Batch Sanitization
+// --------------------------------------------------
+// **************************************************
+
+// CheckedSanitizer is a sanitization step that can reject its input,
+// e.g. SanitizeURLWithOptions or a FilenamePolicy
+type CheckedSanitizer func(string) (string, error)
+
+// Checked adapts a Sanitizer to a CheckedSanitizer that never fails
+func Checked(s Sanitizer) CheckedSanitizer {
+	return func(value string) (string, error) {
+		return s(value), nil
+	}
+}
+
+// ItemError is the error for a single value in a batch
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the error message
+func (e ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError collects the per-item errors of a batch, ordered by index
+type BatchError struct {
+	Errors []ItemError
+}
+
+// Error returns the error message
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, itemErr := range e.Errors {
+		messages = append(messages, itemErr.Error())
+	}
+	return fmt.Sprintf("%d items failed to sanitize: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the item errors, so errors.Is and errors.As see each of them
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, itemErr := range e.Errors {
+		errs[i] = itemErr
+	}
+	return errs
+}
+
+// SanitizeAll applies a sanitizer to every value, returning a new slice
+func SanitizeAll(values []string, s Sanitizer) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = s(value)
+	}
+	return result
+}
+
+// SanitizeAllConcurrent applies a checked sanitizer to every value using a
+// pool of workers, for large datasets such as a column cleaned before a
+// gq.BatchInsert. A workers value of zero or less uses GOMAXPROCS.
+//
+// The result always has the same length as values. Items that fail, panic
+// or are not processed because ctx was cancelled are left empty and
+// reported in a *BatchError.
+func SanitizeAllConcurrent(ctx context.Context, values []string, s CheckedSanitizer, workers int) ([]string, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(values) {
+		workers = len(values)
+	}
+
+	result := make([]string, len(values))
+	itemErrs := make([]error, len(values))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result[i], itemErrs[i] = sanitizeItem(s, values[i])
+			}
+		}()
+	}
+
+	next := 0
+dispatch:
+	for ; next < len(values); next++ {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- next:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := next; i < len(values); i++ {
+		itemErrs[i] = ctx.Err()
+	}
+
+	batchErr := &BatchError{}
+	for i, err := range itemErrs {
+		if err != nil {
+			result[i] = ""
+			batchErr.Errors = append(batchErr.Errors, ItemError{Index: i, Err: err})
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return result, batchErr
+	}
+	return result, nil
+}
+
+// sanitizeItem runs a sanitizer, turning a panic into an error
+func sanitizeItem(s CheckedSanitizer, value string) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sanitizer panicked: %v", r)
+		}
+	}()
+	return s(value)
+}
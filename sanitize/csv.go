@@ -0,0 +1,47 @@
+package sanitize
+
+import (
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// CSV and Spreadsheet Sanitization
+// --------------------------------------------------
+// **************************************************
+
+// csvFormulaPrefixes are leading characters that make spreadsheet
+// applications interpret a cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// SanitizeForCSV neutralizes a value for export to CSV so spreadsheet
+// applications such as Excel and Google Sheets do not evaluate it as a
+// formula. Values starting with =, +, -, @, tab or carriage return are
+// prefixed with a single quote, and other control characters are removed.
+// Plain numbers such as "-42" or "+1.5" are left unchanged.
+func SanitizeForCSV(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if (r < 32 && r != '\t' && r != '\r' && r != '\n') || r == 127 {
+			return -1
+		}
+		return r
+	}, s)
+
+	if s == "" || !strings.ContainsRune(csvFormulaPrefixes, rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
+
+// SanitizeCSVRow applies SanitizeForCSV to every cell of a row
+func SanitizeCSVRow(row []string) []string {
+	result := make([]string, len(row))
+	for i, cell := range row {
+		result[i] = SanitizeForCSV(cell)
+	}
+	return result
+}
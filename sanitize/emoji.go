@@ -0,0 +1,443 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Emoji Functions
+// --------------------------------------------------
+// **************************************************
+
+// emojiPresentation covers characters that render as emoji by default, the
+// Emoji_Presentation property of Unicode 15.0 emoji-data.txt
+var emojiPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23ec, Stride: 1},
+		{Lo: 0x23f0, Hi: 0x23f3, Stride: 3},
+		{Lo: 0x25fd, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2615, Stride: 1},
+		{Lo: 0x2648, Hi: 0x2653, Stride: 1},
+		{Lo: 0x267f, Hi: 0x267f, Stride: 1},
+		{Lo: 0x2693, Hi: 0x2693, Stride: 1},
+		{Lo: 0x26a1, Hi: 0x26a1, Stride: 1},
+		{Lo: 0x26aa, Hi: 0x26ab, Stride: 1},
+		{Lo: 0x26bd, Hi: 0x26be, Stride: 1},
+		{Lo: 0x26c4, Hi: 0x26c5, Stride: 1},
+		{Lo: 0x26ce, Hi: 0x26ce, Stride: 1},
+		{Lo: 0x26d4, Hi: 0x26d4, Stride: 1},
+		{Lo: 0x26ea, Hi: 0x26ea, Stride: 1},
+		{Lo: 0x26f2, Hi: 0x26f3, Stride: 1},
+		{Lo: 0x26f5, Hi: 0x26f5, Stride: 1},
+		{Lo: 0x26fa, Hi: 0x26fa, Stride: 1},
+		{Lo: 0x26fd, Hi: 0x26fd, Stride: 1},
+		{Lo: 0x2705, Hi: 0x2705, Stride: 1},
+		{Lo: 0x270a, Hi: 0x270b, Stride: 1},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x274c, Hi: 0x274c, Stride: 1},
+		{Lo: 0x274e, Hi: 0x274e, Stride: 1},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27b0, Hi: 0x27b0, Stride: 1},
+		{Lo: 0x27bf, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 5},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f004, Hi: 0x1f004, Stride: 1},
+		{Lo: 0x1f0cf, Hi: 0x1f0cf, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f201, Hi: 0x1f201, Stride: 1},
+		{Lo: 0x1f21a, Hi: 0x1f21a, Stride: 1},
+		{Lo: 0x1f22f, Hi: 0x1f22f, Stride: 1},
+		{Lo: 0x1f232, Hi: 0x1f236, Stride: 1},
+		{Lo: 0x1f238, Hi: 0x1f23a, Stride: 1},
+		{Lo: 0x1f250, Hi: 0x1f251, Stride: 1},
+		{Lo: 0x1f300, Hi: 0x1f320, Stride: 1},
+		{Lo: 0x1f32d, Hi: 0x1f335, Stride: 1},
+		{Lo: 0x1f337, Hi: 0x1f37c, Stride: 1},
+		{Lo: 0x1f37e, Hi: 0x1f393, Stride: 1},
+		{Lo: 0x1f3a0, Hi: 0x1f3ca, Stride: 1},
+		{Lo: 0x1f3cf, Hi: 0x1f3d3, Stride: 1},
+		{Lo: 0x1f3e0, Hi: 0x1f3f0, Stride: 1},
+		{Lo: 0x1f3f4, Hi: 0x1f3f4, Stride: 1},
+		{Lo: 0x1f3f8, Hi: 0x1f43e, Stride: 1},
+		{Lo: 0x1f440, Hi: 0x1f440, Stride: 1},
+		{Lo: 0x1f442, Hi: 0x1f4fc, Stride: 1},
+		{Lo: 0x1f4ff, Hi: 0x1f53d, Stride: 1},
+		{Lo: 0x1f54b, Hi: 0x1f54e, Stride: 1},
+		{Lo: 0x1f550, Hi: 0x1f567, Stride: 1},
+		{Lo: 0x1f57a, Hi: 0x1f57a, Stride: 1},
+		{Lo: 0x1f595, Hi: 0x1f596, Stride: 1},
+		{Lo: 0x1f5a4, Hi: 0x1f5a4, Stride: 1},
+		{Lo: 0x1f5fb, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f680, Hi: 0x1f6c5, Stride: 1},
+		{Lo: 0x1f6cc, Hi: 0x1f6cc, Stride: 1},
+		{Lo: 0x1f6d0, Hi: 0x1f6d2, Stride: 1},
+		{Lo: 0x1f6d5, Hi: 0x1f6d7, Stride: 1},
+		{Lo: 0x1f6dc, Hi: 0x1f6df, Stride: 1},
+		{Lo: 0x1f6eb, Hi: 0x1f6ec, Stride: 1},
+		{Lo: 0x1f6f4, Hi: 0x1f6fc, Stride: 1},
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f7f0, Hi: 0x1f7f0, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f93a, Stride: 1},
+		{Lo: 0x1f93c, Hi: 0x1f945, Stride: 1},
+		{Lo: 0x1f947, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x1fa70, Hi: 0x1fa7c, Stride: 1},
+		{Lo: 0x1fa80, Hi: 0x1fa88, Stride: 1},
+		{Lo: 0x1fa90, Hi: 0x1fabd, Stride: 1},
+		{Lo: 0x1fabf, Hi: 0x1fac5, Stride: 1},
+		{Lo: 0x1face, Hi: 0x1fadb, Stride: 1},
+		{Lo: 0x1fae0, Hi: 0x1fae8, Stride: 1},
+		{Lo: 0x1faf0, Hi: 0x1faf8, Stride: 1},
+	},
+}
+
+// emojiTextDefault covers characters that render as text unless followed by
+// the emoji variation selector, e.g. "©" versus "©️"
+var emojiTextDefault = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00ae, Stride: 5},
+		{Lo: 0x203c, Hi: 0x203c, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x23cf, Hi: 0x23cf, Stride: 1},
+		{Lo: 0x23ed, Hi: 0x23ef, Stride: 1},
+		{Lo: 0x23f1, Hi: 0x23f2, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25c0, Stride: 10},
+		{Lo: 0x25fb, Hi: 0x25fc, Stride: 1},
+		{Lo: 0x2600, Hi: 0x2604, Stride: 1},
+		{Lo: 0x260e, Hi: 0x260e, Stride: 1},
+		{Lo: 0x2611, Hi: 0x2611, Stride: 1},
+		{Lo: 0x2618, Hi: 0x2618, Stride: 1},
+		{Lo: 0x261d, Hi: 0x261d, Stride: 1},
+		{Lo: 0x2620, Hi: 0x2620, Stride: 1},
+		{Lo: 0x2622, Hi: 0x2623, Stride: 1},
+		{Lo: 0x2626, Hi: 0x2626, Stride: 1},
+		{Lo: 0x262a, Hi: 0x262a, Stride: 1},
+		{Lo: 0x262e, Hi: 0x262f, Stride: 1},
+		{Lo: 0x2638, Hi: 0x263a, Stride: 1},
+		{Lo: 0x2640, Hi: 0x2640, Stride: 1},
+		{Lo: 0x2642, Hi: 0x2642, Stride: 1},
+		{Lo: 0x265f, Hi: 0x2660, Stride: 1},
+		{Lo: 0x2663, Hi: 0x2663, Stride: 1},
+		{Lo: 0x2665, Hi: 0x2666, Stride: 1},
+		{Lo: 0x2668, Hi: 0x2668, Stride: 1},
+		{Lo: 0x267b, Hi: 0x267b, Stride: 1},
+		{Lo: 0x267e, Hi: 0x267e, Stride: 1},
+		{Lo: 0x2692, Hi: 0x2692, Stride: 1},
+		{Lo: 0x2694, Hi: 0x2697, Stride: 1},
+		{Lo: 0x2699, Hi: 0x2699, Stride: 1},
+		{Lo: 0x269b, Hi: 0x269c, Stride: 1},
+		{Lo: 0x26a0, Hi: 0x26a0, Stride: 1},
+		{Lo: 0x26a7, Hi: 0x26a7, Stride: 1},
+		{Lo: 0x26b0, Hi: 0x26b1, Stride: 1},
+		{Lo: 0x26c8, Hi: 0x26c8, Stride: 1},
+		{Lo: 0x26cf, Hi: 0x26cf, Stride: 1},
+		{Lo: 0x26d1, Hi: 0x26d1, Stride: 1},
+		{Lo: 0x26d3, Hi: 0x26d3, Stride: 1},
+		{Lo: 0x26e9, Hi: 0x26e9, Stride: 1},
+		{Lo: 0x26f0, Hi: 0x26f1, Stride: 1},
+		{Lo: 0x26f4, Hi: 0x26f4, Stride: 1},
+		{Lo: 0x26f7, Hi: 0x26f9, Stride: 1},
+		{Lo: 0x2702, Hi: 0x2702, Stride: 1},
+		{Lo: 0x2708, Hi: 0x2709, Stride: 1},
+		{Lo: 0x270c, Hi: 0x270d, Stride: 1},
+		{Lo: 0x270f, Hi: 0x270f, Stride: 1},
+		{Lo: 0x2712, Hi: 0x2712, Stride: 1},
+		{Lo: 0x2714, Hi: 0x2714, Stride: 1},
+		{Lo: 0x2716, Hi: 0x2716, Stride: 1},
+		{Lo: 0x271d, Hi: 0x271d, Stride: 1},
+		{Lo: 0x2721, Hi: 0x2721, Stride: 1},
+		{Lo: 0x2733, Hi: 0x2734, Stride: 1},
+		{Lo: 0x2744, Hi: 0x2744, Stride: 1},
+		{Lo: 0x2747, Hi: 0x2747, Stride: 1},
+		{Lo: 0x2763, Hi: 0x2764, Stride: 1},
+		{Lo: 0x27a1, Hi: 0x27a1, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x3030, Hi: 0x303d, Stride: 13},
+		{Lo: 0x3297, Hi: 0x3299, Stride: 2},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f170, Hi: 0x1f171, Stride: 1},
+		{Lo: 0x1f17e, Hi: 0x1f17f, Stride: 1},
+		{Lo: 0x1f202, Hi: 0x1f202, Stride: 1},
+		{Lo: 0x1f237, Hi: 0x1f237, Stride: 1},
+		{Lo: 0x1f321, Hi: 0x1f321, Stride: 1},
+		{Lo: 0x1f324, Hi: 0x1f32c, Stride: 1},
+		{Lo: 0x1f336, Hi: 0x1f336, Stride: 1},
+		{Lo: 0x1f37d, Hi: 0x1f37d, Stride: 1},
+		{Lo: 0x1f396, Hi: 0x1f397, Stride: 1},
+		{Lo: 0x1f399, Hi: 0x1f39b, Stride: 1},
+		{Lo: 0x1f39e, Hi: 0x1f39f, Stride: 1},
+		{Lo: 0x1f3cb, Hi: 0x1f3ce, Stride: 1},
+		{Lo: 0x1f3d4, Hi: 0x1f3df, Stride: 1},
+		{Lo: 0x1f3f3, Hi: 0x1f3f3, Stride: 1},
+		{Lo: 0x1f3f5, Hi: 0x1f3f5, Stride: 1},
+		{Lo: 0x1f3f7, Hi: 0x1f3f7, Stride: 1},
+		{Lo: 0x1f43f, Hi: 0x1f43f, Stride: 1},
+		{Lo: 0x1f441, Hi: 0x1f441, Stride: 1},
+		{Lo: 0x1f4fd, Hi: 0x1f4fd, Stride: 1},
+		{Lo: 0x1f549, Hi: 0x1f54a, Stride: 1},
+		{Lo: 0x1f56f, Hi: 0x1f570, Stride: 1},
+		{Lo: 0x1f573, Hi: 0x1f579, Stride: 1},
+		{Lo: 0x1f587, Hi: 0x1f587, Stride: 1},
+		{Lo: 0x1f58a, Hi: 0x1f58d, Stride: 1},
+		{Lo: 0x1f590, Hi: 0x1f590, Stride: 1},
+		{Lo: 0x1f5a5, Hi: 0x1f5a5, Stride: 1},
+		{Lo: 0x1f5a8, Hi: 0x1f5a8, Stride: 1},
+		{Lo: 0x1f5b1, Hi: 0x1f5b2, Stride: 1},
+		{Lo: 0x1f5bc, Hi: 0x1f5bc, Stride: 1},
+		{Lo: 0x1f5c2, Hi: 0x1f5c4, Stride: 1},
+		{Lo: 0x1f5d1, Hi: 0x1f5d3, Stride: 1},
+		{Lo: 0x1f5dc, Hi: 0x1f5de, Stride: 1},
+		{Lo: 0x1f5e1, Hi: 0x1f5e1, Stride: 1},
+		{Lo: 0x1f5e3, Hi: 0x1f5e3, Stride: 1},
+		{Lo: 0x1f5e8, Hi: 0x1f5e8, Stride: 1},
+		{Lo: 0x1f5ef, Hi: 0x1f5ef, Stride: 1},
+		{Lo: 0x1f5f3, Hi: 0x1f5f3, Stride: 1},
+		{Lo: 0x1f5fa, Hi: 0x1f5fa, Stride: 1},
+		{Lo: 0x1f6cb, Hi: 0x1f6cb, Stride: 1},
+		{Lo: 0x1f6cd, Hi: 0x1f6cf, Stride: 1},
+		{Lo: 0x1f6e0, Hi: 0x1f6e5, Stride: 1},
+		{Lo: 0x1f6e9, Hi: 0x1f6e9, Stride: 1},
+		{Lo: 0x1f6f0, Hi: 0x1f6f0, Stride: 1},
+		{Lo: 0x1f6f3, Hi: 0x1f6f3, Stride: 1},
+	},
+}
+
+// emojiModifier covers characters that attach to a preceding emoji
+var emojiModifier = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x20e3, Hi: 0x20e3, Stride: 1}, // combining enclosing keycap
+		{Lo: 0xfe0e, Hi: 0xfe0f, Stride: 1}, // variation selectors
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f3fb, Hi: 0x1f3ff, Stride: 1}, // skin tones
+		{Lo: 0xe0020, Hi: 0xe007f, Stride: 1}, // tags, used by subdivision flags
+	},
+}
+
+const (
+	zeroWidthJoiner     = '\u200D'
+	emojiVariation      = '\uFE0F'
+	keycapCombiner      = '\u20E3'
+	regionalIndicatorLo = 0x1f1e6
+	regionalIndicatorHi = 0x1f1ff
+)
+
+// emojiAliases maps common emojis to their shortcode aliases
+var emojiAliases = map[string]string{
+	"😀": ":grinning:", "😃": ":smiley:", "😄": ":smile:", "😁": ":grin:",
+	"😆": ":laughing:", "😅": ":sweat_smile:", "🤣": ":rofl:", "😂": ":joy:",
+	"🙂": ":slightly_smiling_face:", "🙃": ":upside_down_face:", "😉": ":wink:",
+	"😊": ":blush:", "😇": ":innocent:", "🥰": ":smiling_face_with_three_hearts:",
+	"😍": ":heart_eyes:", "🤩": ":star_struck:", "😘": ":kissing_heart:",
+	"😋": ":yum:", "😛": ":stuck_out_tongue:", "😜": ":stuck_out_tongue_winking_eye:",
+	"🤔": ":thinking:", "🤐": ":zipper_mouth_face:", "😐": ":neutral_face:",
+	"😑": ":expressionless:", "😶": ":no_mouth:", "😏": ":smirk:", "😒": ":unamused:",
+	"🙄": ":roll_eyes:", "😬": ":grimacing:", "😌": ":relieved:", "😔": ":pensive:",
+	"😪": ":sleepy:", "😴": ":sleeping:", "😷": ":mask:", "🤒": ":face_with_thermometer:",
+	"🤢": ":nauseated_face:", "🤮": ":vomiting_face:", "🥵": ":hot_face:",
+	"🥶": ":cold_face:", "😵": ":dizzy_face:", "🤯": ":exploding_head:",
+	"😎": ":sunglasses:", "🤓": ":nerd_face:", "😕": ":confused:", "😟": ":worried:",
+	"😮": ":open_mouth:", "😲": ":astonished:", "😳": ":flushed:", "🥺": ":pleading_face:",
+	"😢": ":cry:", "😭": ":sob:", "😱": ":scream:", "😤": ":triumph:", "😡": ":rage:",
+	"😠": ":angry:", "🤬": ":cursing_face:", "😈": ":smiling_imp:", "💀": ":skull:",
+	"💩": ":poop:", "🤡": ":clown_face:", "👻": ":ghost:", "👽": ":alien:", "🤖": ":robot:",
+	"👋": ":wave:", "🤚": ":raised_back_of_hand:", "✋": ":raised_hand:", "👌": ":ok_hand:",
+	"✌": ":v:", "🤞": ":crossed_fingers:", "🤘": ":metal:", "👈": ":point_left:",
+	"👉": ":point_right:", "👆": ":point_up_2:", "👇": ":point_down:", "👍": ":+1:",
+	"👎": ":-1:", "✊": ":fist:", "👊": ":punch:", "👏": ":clap:", "🙌": ":raised_hands:",
+	"🙏": ":pray:", "💪": ":muscle:", "👀": ":eyes:", "🧠": ":brain:",
+	"❤": ":heart:", "🧡": ":orange_heart:", "💛": ":yellow_heart:", "💚": ":green_heart:",
+	"💙": ":blue_heart:", "💜": ":purple_heart:", "🖤": ":black_heart:", "💔": ":broken_heart:",
+	"💯": ":100:", "💥": ":boom:", "💫": ":dizzy:", "💬": ":speech_balloon:", "💤": ":zzz:",
+	"🔥": ":fire:", "✨": ":sparkles:", "⭐": ":star:", "🌟": ":star2:", "⚡": ":zap:",
+	"☀": ":sunny:", "🌈": ":rainbow:", "☔": ":umbrella:", "❄": ":snowflake:",
+	"🎉": ":tada:", "🎊": ":confetti_ball:", "🎁": ":gift:", "🎂": ":birthday:",
+	"🏆": ":trophy:", "🚀": ":rocket:", "✅": ":white_check_mark:", "❌": ":x:",
+	"⚠": ":warning:", "❓": ":question:", "❗": ":exclamation:", "🔒": ":lock:",
+	"🔑": ":key:", "💡": ":bulb:", "📌": ":pushpin:", "📎": ":paperclip:", "📅": ":date:",
+	"📈": ":chart_with_upwards_trend:", "📉": ":chart_with_downwards_trend:",
+	"💰": ":moneybag:", "☕": ":coffee:", "🍕": ":pizza:", "🍺": ":beer:", "🐛": ":bug:",
+	"🐶": ":dog:", "🐱": ":cat:", "🦄": ":unicorn:", "🌍": ":earth_africa:",
+}
+
+// EmojiMode selects what HandleEmojis does with the emojis it finds
+type EmojiMode int
+
+const (
+	// EmojiRemove removes emojis
+	EmojiRemove EmojiMode = iota
+	// EmojiAlias replaces emojis with shortcode aliases such as ":smile:"
+	EmojiAlias
+	// EmojiReplace replaces each emoji with a fixed replacement string
+	EmojiReplace
+	// EmojiKeep leaves the text unchanged, for counting only
+	EmojiKeep
+)
+
+// EmojiOptions configures HandleEmojis
+type EmojiOptions struct {
+	Mode EmojiMode
+	// Replacement is used by EmojiReplace, and by EmojiAlias for emojis
+	// without a known alias; an empty Replacement in EmojiAlias mode keeps them
+	Replacement string
+}
+
+// HandleEmojis finds emojis in s, treating multi-character sequences such as
+// flags, keycaps, skin tones and joined family emojis as single emojis. It
+// returns the processed string and the number of emojis found.
+func HandleEmojis(s string, opts EmojiOptions) (string, int) {
+	runes := []rune(s)
+	var result strings.Builder
+	result.Grow(len(s))
+
+	count := 0
+	for i := 0; i < len(runes); {
+		end := emojiSequenceEnd(runes, i)
+		if end == i {
+			result.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		count++
+		emoji := string(runes[i:end])
+		switch opts.Mode {
+		case EmojiAlias:
+			if alias, ok := lookupEmojiAlias(emoji); ok {
+				result.WriteString(alias)
+			} else if opts.Replacement != "" {
+				result.WriteString(opts.Replacement)
+			} else {
+				result.WriteString(emoji)
+			}
+		case EmojiReplace:
+			result.WriteString(opts.Replacement)
+		case EmojiKeep:
+			result.WriteString(emoji)
+		}
+		i = end
+	}
+	return result.String(), count
+}
+
+// RemoveEmojis removes emoji characters and sequences
+func RemoveEmojis(s string) string {
+	result, _ := HandleEmojis(s, EmojiOptions{Mode: EmojiRemove})
+	return result
+}
+
+// ReplaceEmojisWithAliases replaces known emojis with shortcode aliases,
+// e.g. "great 👍" becomes "great :+1:"
+func ReplaceEmojisWithAliases(s string) string {
+	result, _ := HandleEmojis(s, EmojiOptions{Mode: EmojiAlias})
+	return result
+}
+
+// CountEmojis counts the emojis in s
+func CountEmojis(s string) int {
+	_, count := HandleEmojis(s, EmojiOptions{Mode: EmojiKeep})
+	return count
+}
+
+// ContainsEmoji checks if s contains any emoji
+func ContainsEmoji(s string) bool {
+	runes := []rune(s)
+	for i := range runes {
+		if emojiSequenceEnd(runes, i) > i {
+			return true
+		}
+	}
+	return false
+}
+
+// emojiSequenceEnd returns the end of the emoji sequence starting at i, or i
+// if no emoji starts there
+func emojiSequenceEnd(runes []rune, i int) int {
+	r := runes[i]
+	n := len(runes)
+
+	// Flags are pairs of regional indicators
+	if isRegionalIndicator(r) {
+		if i+1 < n && isRegionalIndicator(runes[i+1]) {
+			return i + 2
+		}
+		return i + 1
+	}
+
+	// Keycaps are a digit, '#' or '*', an optional variation selector and U+20E3
+	if (r >= '0' && r <= '9') || r == '#' || r == '*' {
+		j := i + 1
+		if j < n && runes[j] == emojiVariation {
+			j++
+		}
+		if j < n && runes[j] == keycapCombiner {
+			return j + 1
+		}
+		return i
+	}
+
+	if !isEmojiBase(runes, i) {
+		return i
+	}
+
+	j := i + 1
+	for {
+		for j < n && unicode.Is(emojiModifier, runes[j]) {
+			j++
+		}
+		// Joined sequences such as family and profession emojis
+		if j+1 < n && runes[j] == zeroWidthJoiner && isEmojiBase(runes, j+1) {
+			j += 2
+			continue
+		}
+		return j
+	}
+}
+
+// isEmojiBase checks if the rune at i starts an emoji, taking text-default
+// characters into account
+func isEmojiBase(runes []rune, i int) bool {
+	r := runes[i]
+	if unicode.Is(emojiPresentation, r) {
+		return true
+	}
+	return unicode.Is(emojiTextDefault, r) && i+1 < len(runes) && runes[i+1] == emojiVariation
+}
+
+// isRegionalIndicator checks if r is a regional indicator symbol
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorLo && r <= regionalIndicatorHi
+}
+
+// lookupEmojiAlias finds the alias of an emoji, ignoring variation selectors
+// and skin tones when the exact sequence has no alias
+func lookupEmojiAlias(emoji string) (string, bool) {
+	if alias, ok := emojiAliases[emoji]; ok {
+		return alias, true
+	}
+	base := strings.Map(func(r rune) rune {
+		if unicode.Is(emojiModifier, r) {
+			return -1
+		}
+		return r
+	}, emoji)
+	alias, ok := emojiAliases[base]
+	return alias, ok
+}
@@ -0,0 +1,189 @@
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Filename Policies
+// --------------------------------------------------
+// **************************************************
+
+// ErrEmptyFilename is returned when nothing usable remains of a filename
+var ErrEmptyFilename = errors.New("filename is empty after sanitization")
+
+// filenameDangerousChars are path separators and characters reserved on Windows
+const filenameDangerousChars = `/\:*?"<>|`
+
+// maxUniqueFilenameAttempts bounds the search for an unused filename
+const maxUniqueFilenameAttempts = 10000
+
+// windowsReservedNames are device names Windows refuses as file names,
+// with or without an extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// FilenamePolicy configures filename sanitization
+type FilenamePolicy struct {
+	// Replacement replaces path separators and reserved characters
+	Replacement string
+	// MaxLength limits the filename length, in bytes unless LengthInGraphemes is
+	// set. The extension is kept when the name is shortened. Zero means no limit.
+	MaxLength int
+	// LengthInGraphemes counts MaxLength in grapheme clusters, the characters
+	// a user sees, instead of bytes
+	LengthInGraphemes bool
+	// AllowDotfiles keeps a leading dot, as in ".env"; otherwise leading
+	// dots are removed so files are not hidden
+	AllowDotfiles bool
+}
+
+// DefaultFilenamePolicy returns a policy that replaces reserved characters
+// with "_", limits names to 255 bytes and removes leading dots
+func DefaultFilenamePolicy() FilenamePolicy {
+	return FilenamePolicy{
+		Replacement: "_",
+		MaxLength:   255,
+	}
+}
+
+// Sanitize returns a filename that is safe on common file systems. It
+// returns ErrEmptyFilename if nothing but replacements, dots and spaces
+// remains.
+func (p FilenamePolicy) Sanitize(filename string) (string, error) {
+	// Remove control and invisible characters, normalize composed characters
+	filename = NormalizeUnicode(RemoveInvisibleChars(filename))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename)
+
+	// Replace path separators and characters reserved on Windows
+	var b strings.Builder
+	for _, r := range filename {
+		if strings.ContainsRune(filenameDangerousChars, r) {
+			b.WriteString(p.Replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	filename = b.String()
+
+	// Windows ignores trailing dots and spaces; leading dots hide files
+	filename = strings.TrimRight(strings.TrimLeft(filename, " "), ". ")
+	if !p.AllowDotfiles {
+		filename = strings.TrimLeft(filename, ". ")
+	}
+
+	// Device names such as "CON" or "nul.txt"
+	base, ext := splitExtension(filename)
+	if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		replacement := p.Replacement
+		if replacement == "" {
+			replacement = "_"
+		}
+		filename = base + replacement + ext
+	}
+
+	filename = p.truncate(filename)
+
+	if !hasSalvageableChars(filename, p.Replacement) {
+		return "", ErrEmptyFilename
+	}
+	return filename, nil
+}
+
+// Unique sanitizes a filename and appends a counter, as in "report-2.pdf",
+// until exists reports the name as unused. The counter fits within MaxLength.
+func (p FilenamePolicy) Unique(filename string, exists func(name string) bool) (string, error) {
+	sanitized, err := p.Sanitize(filename)
+	if err != nil {
+		return "", err
+	}
+	if !exists(sanitized) {
+		return sanitized, nil
+	}
+
+	base, ext := splitExtension(sanitized)
+	for i := 1; i <= maxUniqueFilenameAttempts; i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		candidate := p.truncateBase(base, ext+suffix) + suffix + ext
+		if !exists(candidate) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no unused filename for %q after %d attempts", sanitized, maxUniqueFilenameAttempts)
+}
+
+// truncate shortens a filename to MaxLength, keeping its extension
+func (p FilenamePolicy) truncate(filename string) string {
+	if p.MaxLength <= 0 || p.length(filename) <= p.MaxLength {
+		return filename
+	}
+
+	base, ext := splitExtension(filename)
+	if p.length(ext) >= p.MaxLength {
+		return p.cut(filename, p.MaxLength)
+	}
+	return p.truncateBase(base, ext) + ext
+}
+
+// truncateBase shortens base so base+reserved fits within MaxLength
+func (p FilenamePolicy) truncateBase(base, reserved string) string {
+	if p.MaxLength <= 0 {
+		return base
+	}
+	room := p.MaxLength - p.length(reserved)
+	if room < 1 {
+		room = 1
+	}
+	return strings.TrimRight(p.cut(base, room), ". ")
+}
+
+// length measures s in the policy's length unit
+func (p FilenamePolicy) length(s string) int {
+	if p.LengthInGraphemes {
+		return uniseg.GraphemeClusterCount(s)
+	}
+	return len(s)
+}
+
+// cut truncates s to n of the policy's length unit
+func (p FilenamePolicy) cut(s string, n int) string {
+	if p.LengthInGraphemes {
+		return Truncate(s, n)
+	}
+	return TruncateBytes(s, n)
+}
+
+// splitExtension splits "archive.tar.gz" into "archive.tar" and ".gz".
+// A leading dot, as in ".env", is not treated as an extension.
+func splitExtension(filename string) (string, string) {
+	i := strings.LastIndex(filename, ".")
+	if i <= 0 {
+		return filename, ""
+	}
+	return filename[:i], filename[i:]
+}
+
+// hasSalvageableChars checks if a filename has anything besides
+// replacements, dots and spaces
+func hasSalvageableChars(filename, replacement string) bool {
+	if replacement != "" {
+		filename = strings.ReplaceAll(filename, replacement, "")
+	}
+	return strings.Trim(filename, ". ") != ""
+}
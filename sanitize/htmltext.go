@@ -0,0 +1,289 @@
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// **************************************************
+// --------------------------------------------------
+// HTML to Text Conversion
+// --------------------------------------------------
+// **************************************************
+
+var (
+	textExtraBlankLines   = regexp.MustCompile(`\n{3,}`)
+	textTrailingLineSpace = regexp.MustCompile(`[ \t]+\n`)
+)
+
+// htmlSkippedElements have content that is never shown as text
+var htmlSkippedElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true,
+	"template": true, "iframe": true, "object": true, "svg": true,
+}
+
+// htmlBlockElements start and end on their own paragraph
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"details": true, "dl": true, "div": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "ul": true,
+}
+
+// HTMLToText converts HTML to readable plain text, keeping paragraph
+// breaks, list bullets and numbering, table rows and link targets, e.g.
+// `<a href="https://x.io">docs</a>` becomes "docs (https://x.io)".
+// Scripts, styles and other non-visible content are dropped.
+func HTMLToText(s string) string {
+	w := &htmlTextWriter{}
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+
+	skipDepth := 0
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break // io.EOF or malformed input; keep what was converted
+		}
+
+		token := tokenizer.Token()
+		name := token.Data
+
+		if skipDepth > 0 {
+			switch {
+			case tokenType == html.StartTagToken && htmlSkippedElements[name]:
+				skipDepth++
+			case tokenType == html.EndTagToken && htmlSkippedElements[name]:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch tokenType {
+		case html.TextToken:
+			w.text(token.Data)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if htmlSkippedElements[name] {
+				if tokenType == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			w.start(token, tokenType == html.SelfClosingTagToken)
+		case html.EndTagToken:
+			w.end(name)
+		}
+	}
+
+	return w.String()
+}
+
+// htmlList tracks an open list while converting
+type htmlList struct {
+	ordered bool
+	count   int
+}
+
+// htmlLink tracks an open link while converting
+type htmlLink struct {
+	href  string
+	start int
+}
+
+// htmlTextWriter accumulates text with collapsed whitespace and pending line breaks
+type htmlTextWriter struct {
+	b         strings.Builder
+	breaks    int // newlines to write before the next text
+	lastSpace bool
+	pre       int
+	lists     []htmlList
+	links     []htmlLink
+	cells     int
+}
+
+// String returns the converted text
+func (w *htmlTextWriter) String() string {
+	text := textTrailingLineSpace.ReplaceAllString(w.b.String(), "\n")
+	text = textExtraBlankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// lineBreak requests at least n newlines before the next text
+func (w *htmlTextWriter) lineBreak(n int) {
+	if w.b.Len() > 0 && n > w.breaks {
+		w.breaks = n
+	}
+}
+
+// write writes s after any pending line breaks
+func (w *htmlTextWriter) write(s string) {
+	if w.breaks > 0 {
+		w.b.WriteString(strings.Repeat("\n", w.breaks))
+		w.breaks = 0
+		w.lastSpace = true
+	}
+	w.b.WriteString(s)
+	if s != "" {
+		last := s[len(s)-1]
+		w.lastSpace = last == ' ' || last == '\n'
+	}
+}
+
+// text writes character data, collapsing whitespace outside <pre>
+func (w *htmlTextWriter) text(s string) {
+	if w.pre > 0 {
+		w.write(s)
+		return
+	}
+
+	collapsed := strings.Join(strings.Fields(s), " ")
+	if collapsed == "" {
+		if s != "" && !w.lastSpace && w.breaks == 0 {
+			w.write(" ")
+		}
+		return
+	}
+	if startsWithSpace(s) && !w.lastSpace && w.breaks == 0 {
+		collapsed = " " + collapsed
+	}
+	if endsWithSpace(s) {
+		collapsed += " "
+	}
+	w.write(collapsed)
+}
+
+// start handles an opening tag
+func (w *htmlTextWriter) start(token html.Token, selfClosing bool) {
+	name := token.Data
+	if htmlBlockElements[name] && !w.nestedList(name, true) {
+		w.lineBreak(2)
+	}
+
+	switch name {
+	case "br":
+		w.write("\n")
+	case "hr":
+		w.lineBreak(2)
+		w.write("----")
+		w.lineBreak(2)
+	case "pre":
+		if !selfClosing {
+			w.pre++
+		}
+	case "ul", "ol":
+		w.lineBreak(1)
+		if !selfClosing {
+			w.lists = append(w.lists, htmlList{ordered: name == "ol"})
+		}
+	case "li":
+		w.lineBreak(1)
+		indent := ""
+		marker := "- "
+		if depth := len(w.lists); depth > 0 {
+			indent = strings.Repeat("  ", depth-1)
+			list := &w.lists[depth-1]
+			list.count++
+			if list.ordered {
+				marker = fmt.Sprintf("%d. ", list.count)
+			}
+		}
+		w.write(indent + marker)
+	case "tr":
+		w.lineBreak(1)
+		w.cells = 0
+	case "td", "th":
+		if w.cells > 0 {
+			w.write(" | ")
+		}
+		w.cells++
+	case "dt", "dd":
+		w.lineBreak(1)
+	case "a":
+		if !selfClosing {
+			w.links = append(w.links, htmlLink{href: attribute(token, "href"), start: w.b.Len()})
+		}
+	case "img":
+		if alt := strings.TrimSpace(attribute(token, "alt")); alt != "" {
+			w.text("[" + alt + "]")
+		}
+	}
+}
+
+// end handles a closing tag
+func (w *htmlTextWriter) end(name string) {
+	if htmlBlockElements[name] && !w.nestedList(name, false) {
+		w.lineBreak(2)
+	}
+
+	switch name {
+	case "pre":
+		if w.pre > 0 {
+			w.pre--
+		}
+	case "ul", "ol":
+		if len(w.lists) > 0 {
+			w.lists = w.lists[:len(w.lists)-1]
+		}
+		w.lineBreak(1)
+	case "li", "tr", "dt", "dd":
+		w.lineBreak(1)
+	case "a":
+		if len(w.links) == 0 {
+			return
+		}
+		link := w.links[len(w.links)-1]
+		w.links = w.links[:len(w.links)-1]
+
+		// Only absolute web and mail links are useful outside the page
+		target := strings.TrimSpace(link.href)
+		if _, absolute := linkScheme(target); !absolute || !isAllowedLink(target, []string{"http", "https", "mailto"}) {
+			return
+		}
+		label := strings.TrimSpace(w.b.String()[link.start:])
+		target = strings.TrimPrefix(target, "mailto:")
+		if label != target {
+			separator := " "
+			if w.lastSpace {
+				separator = ""
+			}
+			w.write(separator + "(" + target + ")")
+		}
+	}
+}
+
+// nestedList checks if a list tag opens or closes a list inside another
+// list, which continues on the next line rather than a new paragraph.
+// It is called before the list stack is updated for the tag.
+func (w *htmlTextWriter) nestedList(name string, opening bool) bool {
+	if name != "ul" && name != "ol" {
+		return false
+	}
+	if opening {
+		return len(w.lists) > 0
+	}
+	return len(w.lists) > 1
+}
+
+// attribute returns the value of a token's attribute
+func attribute(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// startsWithSpace checks if s starts with whitespace
+func startsWithSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n\f") != s
+}
+
+// endsWithSpace checks if s ends with whitespace
+func endsWithSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n\f") != s
+}
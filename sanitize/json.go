@@ -0,0 +1,247 @@
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// JSON Sanitization
+// --------------------------------------------------
+// **************************************************
+
+// JSON sanitization errors
+var (
+	ErrJSONTooLarge = errors.New("JSON document too large")
+	ErrJSONTooDeep  = errors.New("JSON document nested too deeply")
+)
+
+// DefaultRedaction replaces redacted JSON values
+const DefaultRedaction = "[REDACTED]"
+
+// defaultRedactKeys are keys that commonly hold credentials or personal data
+var defaultRedactKeys = []string{
+	"password", "passwd", "secret", "client_secret", "token", "access_token",
+	"refresh_token", "id_token", "api_key", "apikey", "authorization",
+	"cookie", "session", "private_key", "credit_card", "card_number", "cvv",
+	"ssn",
+}
+
+// JSONPolicy configures SanitizeJSON
+type JSONPolicy struct {
+	// StringSanitizer is applied to every string value; keys are left as is
+	StringSanitizer Sanitizer
+	// RedactKeys lists object keys whose values are redacted at any depth,
+	// compared case-insensitively
+	RedactKeys []string
+	// RedactPaths lists dotted paths whose values are redacted, e.g.
+	// "user.credentials". A "*" segment matches any key or array index,
+	// e.g. "accounts.*.iban".
+	RedactPaths []string
+	// Redaction replaces redacted values. Defaults to DefaultRedaction.
+	Redaction string
+	// MaxDepth limits object and array nesting; zero means no limit
+	MaxDepth int
+	// MaxBytes limits the size of the input document; zero means no limit
+	MaxBytes int
+}
+
+// DefaultJSONPolicy returns a policy for logging request bodies: common
+// credential keys redacted, control characters removed from strings,
+// nesting limited to 32 levels and documents limited to 1 MiB
+func DefaultJSONPolicy() JSONPolicy {
+	return JSONPolicy{
+		StringSanitizer: RemoveControlChars,
+		RedactKeys:      defaultRedactKeys,
+		Redaction:       DefaultRedaction,
+		MaxDepth:        32,
+		MaxBytes:        1 << 20,
+	}
+}
+
+// SanitizeJSON walks an arbitrary JSON document, sanitizing string values,
+// redacting configured keys and paths, and enforcing size and depth limits.
+// Numbers are preserved exactly.
+func SanitizeJSON(raw []byte, policy JSONPolicy) ([]byte, error) {
+	if policy.MaxBytes > 0 && len(raw) > policy.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d", ErrJSONTooLarge, len(raw), policy.MaxBytes)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("invalid JSON: unexpected data after document")
+	}
+
+	sanitized, err := SanitizeJSONValue(value, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(sanitized); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// SanitizeJSONValue applies a policy to a decoded JSON value, such as a
+// map[string]interface{} read from a JSON column. Other maps with string
+// keys and slices are walked too, and returned as map[string]interface{}
+// and []interface{}. The input is not modified.
+// MaxBytes is not enforced on decoded values.
+func SanitizeJSONValue(value interface{}, policy JSONPolicy) (interface{}, error) {
+	w := &jsonWalker{
+		policy:    policy,
+		redaction: policy.Redaction,
+		keys:      make(map[string]bool, len(policy.RedactKeys)),
+		paths:     make([][]string, 0, len(policy.RedactPaths)),
+	}
+	if w.redaction == "" {
+		w.redaction = DefaultRedaction
+	}
+	for _, key := range policy.RedactKeys {
+		w.keys[strings.ToLower(key)] = true
+	}
+	for _, path := range policy.RedactPaths {
+		w.paths = append(w.paths, strings.Split(path, "."))
+	}
+
+	return w.walk(value, nil)
+}
+
+// jsonWalker holds the prepared policy while walking a document
+type jsonWalker struct {
+	policy    JSONPolicy
+	redaction string
+	keys      map[string]bool
+	paths     [][]string
+}
+
+// walk sanitizes a value found at path
+func (w *jsonWalker) walk(value interface{}, path []string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := w.checkDepth(path); err != nil {
+			return nil, err
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			itemPath := append(path[:len(path):len(path)], key)
+			if w.redacted(key, itemPath) {
+				result[key] = w.redaction
+				continue
+			}
+			sanitized, err := w.walk(item, itemPath)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = sanitized
+		}
+		return result, nil
+	case []interface{}:
+		if err := w.checkDepth(path); err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			itemPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if w.pathRedacted(itemPath) {
+				result[i] = w.redaction
+				continue
+			}
+			sanitized, err := w.walk(item, itemPath)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = sanitized
+		}
+		return result, nil
+	case string:
+		if w.policy.StringSanitizer != nil {
+			return w.policy.StringSanitizer(v), nil
+		}
+		return v, nil
+	default:
+		if generic, ok := genericJSONValue(v); ok {
+			return w.walk(generic, path)
+		}
+		return v, nil
+	}
+}
+
+// genericJSONValue converts maps with string keys and slices of other types,
+// such as gq.InterfaceMap or []map[string]interface{}, to the
+// map[string]interface{} and []interface{} the walk handles
+func genericJSONValue(value interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String || rv.IsNil() {
+			return nil, false
+		}
+		generic := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			generic[iter.Key().String()] = iter.Value().Interface()
+		}
+		return generic, true
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 || rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, false // Bytes are encoded as a string
+		}
+		generic := make([]interface{}, rv.Len())
+		for i := range generic {
+			generic[i] = rv.Index(i).Interface()
+		}
+		return generic, true
+	default:
+		return nil, false
+	}
+}
+
+// checkDepth enforces MaxDepth for a container found at path
+func (w *jsonWalker) checkDepth(path []string) error {
+	if w.policy.MaxDepth > 0 && len(path)+1 > w.policy.MaxDepth {
+		return fmt.Errorf("%w: exceeds %d levels at %q", ErrJSONTooDeep, w.policy.MaxDepth, strings.Join(path, "."))
+	}
+	return nil
+}
+
+// redacted checks if an object member should be redacted
+func (w *jsonWalker) redacted(key string, path []string) bool {
+	return w.keys[strings.ToLower(key)] || w.pathRedacted(path)
+}
+
+// pathRedacted checks a path against the configured redaction paths
+func (w *jsonWalker) pathRedacted(path []string) bool {
+	for _, pattern := range w.paths {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
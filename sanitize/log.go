@@ -0,0 +1,59 @@
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Log Sanitization Functions
+// --------------------------------------------------
+// **************************************************
+
+// DefaultLogValueLength is the length SanitizeForLog truncates values to
+const DefaultLogValueLength = 2048
+
+// ansiEscape matches ANSI CSI and OSC escape sequences and other escapes
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)?|\x1b[@-_]?`)
+
+// logLineBreaks are escaped so a value cannot start a forged log line
+var logLineBreaks = strings.NewReplacer(
+	"\r\n", `\r\n`,
+	"\r", `\r`,
+	"\n", `\n`,
+	"\u0085", `\u0085`,
+	"\u2028", `\u2028`,
+	"\u2029", `\u2029`,
+)
+
+// SanitizeForLog makes an untrusted value safe to write to a log line: line
+// breaks are escaped to prevent log forging, ANSI escape sequences and other
+// control characters are removed, and values longer than
+// DefaultLogValueLength characters are truncated with a marker
+func SanitizeForLog(s string) string {
+	return SanitizeForLogWithLimit(s, DefaultLogValueLength)
+}
+
+// SanitizeForLogWithLimit is SanitizeForLog with a custom length limit;
+// a maxLength of zero or less disables truncation
+func SanitizeForLogWithLimit(s string, maxLength int) string {
+	s = ansiEscape.ReplaceAllString(s, "")
+	s = logLineBreaks.Replace(s)
+	s = strings.Map(func(r rune) rune {
+		if (r < 32 && r != '\t') || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if maxLength <= 0 {
+		return s
+	}
+	truncated := Truncate(s, maxLength)
+	if len(truncated) == len(s) {
+		return s
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]", truncated, len(s)-len(truncated))
+}
@@ -0,0 +1,205 @@
+package sanitize
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Markdown Sanitization Functions
+// --------------------------------------------------
+// **************************************************
+
+var (
+	mdFencedCode    = regexp.MustCompile("(?m)^[ \t]*(```|~~~).*$")
+	mdInlineCode    = regexp.MustCompile("`+([^`]+)`+")
+	mdImage         = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?((?:[^()\s>]|\([^()\s]*\))*)>?(?:\s+"[^"]*")?\s*\)`)
+	mdLink          = regexp.MustCompile(`\[([^\]]*)\]\(\s*<?((?:[^()\s>]|\([^()\s]*\))*)>?(?:\s+"[^"]*")?\s*\)`)
+	mdReferenceLink = regexp.MustCompile(`!?\[([^\]]+)\]\[[^\]]*\]`)
+	mdReferenceDef  = regexp.MustCompile(`(?m)^[ \t]{0,3}\[[^\]]+\]:[ \t]*<?(\S*?)>?(?:[ \t]+.*)?$`)
+	mdAutolink      = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9+.-]*:[^<>\s]*)>`)
+	mdHeading       = regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]+(.*?)[ \t#]*$`)
+	mdSetextRule    = regexp.MustCompile(`(?m)^[ \t]*(=+|-+)[ \t]*$`)
+	mdRule          = regexp.MustCompile(`(?m)^[ \t]*([*_-][ \t]*){3,}$`)
+	mdBlockquote    = regexp.MustCompile(`(?m)^[ \t]*(>[ \t]?)+`)
+	mdListMarker    = regexp.MustCompile(`(?m)^[ \t]*([-*+]|\d+[.)])[ \t]+(\[[ xX]\][ \t]+)?`)
+	mdStrong        = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	mdEmphasis      = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]`)
+	mdStrikethrough = regexp.MustCompile(`~~(.+?)~~`)
+	mdTablePipe     = regexp.MustCompile(`(?m)^[ \t]*\|?[ \t]*:?-{3,}:?[ \t]*(\|[ \t]*:?-{3,}:?[ \t]*)*\|?[ \t]*$`)
+	htmlScriptBlock = regexp.MustCompile(`(?is)<(script|style|iframe|object|embed)\b[^>]*>.*?</(script|style|iframe|object|embed)\s*>`)
+	htmlTag         = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9-]*(\s[^>]*)?/?>`)
+	htmlComment     = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// MarkdownOptions configures SanitizeMarkdown
+type MarkdownOptions struct {
+	// AllowedLinkSchemes lists the URL schemes links may use. Relative links
+	// are always allowed. Defaults to http, https and mailto.
+	AllowedLinkSchemes []string
+	// AllowedImageHosts lists the hosts images may be loaded from; images
+	// from other hosts, and relative images, are replaced by their alt text.
+	// A leading dot allows subdomains, e.g. ".example.com".
+	AllowedImageHosts []string
+	// AllowRawHTML keeps raw HTML tags instead of removing them. Script,
+	// style and embedded content blocks are removed regardless.
+	AllowRawHTML bool
+}
+
+// DefaultMarkdownOptions returns options that allow http, https and mailto
+// links, no images and no raw HTML
+func DefaultMarkdownOptions() MarkdownOptions {
+	return MarkdownOptions{
+		AllowedLinkSchemes: []string{"http", "https", "mailto"},
+	}
+}
+
+// SanitizeMarkdown makes user-supplied Markdown safe to render: it removes
+// raw HTML, unwraps links with disallowed schemes such as javascript: and
+// replaces images from disallowed hosts with their alt text
+func SanitizeMarkdown(s string, opts MarkdownOptions) string {
+	if opts.AllowedLinkSchemes == nil {
+		opts.AllowedLinkSchemes = DefaultMarkdownOptions().AllowedLinkSchemes
+	}
+
+	s = htmlScriptBlock.ReplaceAllString(s, "")
+	s = htmlComment.ReplaceAllString(s, "")
+	if !opts.AllowRawHTML {
+		s = htmlTag.ReplaceAllString(s, "")
+	}
+
+	s = mdImage.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdImage.FindStringSubmatch(m)
+		if isAllowedImage(parts[2], opts.AllowedImageHosts) {
+			return m
+		}
+		return parts[1]
+	})
+
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdLink.FindStringSubmatch(m)
+		if isAllowedLink(parts[2], opts.AllowedLinkSchemes) {
+			return m
+		}
+		return parts[1]
+	})
+
+	s = mdAutolink.ReplaceAllStringFunc(s, func(m string) string {
+		target := mdAutolink.FindStringSubmatch(m)[1]
+		if isAllowedLink(target, opts.AllowedLinkSchemes) {
+			return m
+		}
+		return ""
+	})
+
+	s = mdReferenceDef.ReplaceAllStringFunc(s, func(m string) string {
+		target := mdReferenceDef.FindStringSubmatch(m)[1]
+		if isAllowedLink(target, opts.AllowedLinkSchemes) {
+			return m
+		}
+		return ""
+	})
+
+	return s
+}
+
+// StripMarkdown removes Markdown syntax and HTML, leaving the plain text
+func StripMarkdown(s string) string {
+	s = htmlScriptBlock.ReplaceAllString(s, "")
+	s = htmlComment.ReplaceAllString(s, "")
+	s = htmlTag.ReplaceAllString(s, "")
+
+	s = mdFencedCode.ReplaceAllString(s, "")
+	s = mdReferenceDef.ReplaceAllString(s, "")
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdReferenceLink.ReplaceAllString(s, "$1")
+	s = mdAutolink.ReplaceAllString(s, "$1")
+	s = mdInlineCode.ReplaceAllString(s, "$1")
+
+	s = mdHeading.ReplaceAllString(s, "$1")
+	s = mdRule.ReplaceAllString(s, "")
+	s = mdSetextRule.ReplaceAllString(s, "")
+	s = mdTablePipe.ReplaceAllString(s, "")
+	s = mdBlockquote.ReplaceAllString(s, "")
+	s = mdListMarker.ReplaceAllString(s, "")
+
+	s = mdStrong.ReplaceAllString(s, "$2")
+	s = mdStrikethrough.ReplaceAllString(s, "$1")
+	s = mdEmphasis.ReplaceAllString(s, "$1$2")
+
+	s = strings.ReplaceAll(s, "|", " ")
+	return html.UnescapeString(s)
+}
+
+// ExtractPlainText returns a single-line plain text preview of Markdown
+// content, cut at a word boundary with an ellipsis when longer than
+// maxLength characters. A maxLength of zero or less disables truncation.
+func ExtractPlainText(s string, maxLength int) string {
+	text := NormalizeWhitespace(RemoveControlChars(StripMarkdown(s)))
+	if maxLength <= 0 {
+		return text
+	}
+	return TruncateWithEllipsis(text, maxLength)
+}
+
+// isAllowedLink checks a link target against the allowed schemes. Targets
+// are entity-decoded and stripped of whitespace first, as renderers do,
+// so "jav&#x61;script:" and "java\nscript:" are caught.
+func isAllowedLink(target string, schemes []string) bool {
+	target = cleanLinkTarget(target)
+	scheme, ok := linkScheme(target)
+	if !ok {
+		return true // relative link
+	}
+	for _, allowed := range schemes {
+		if strings.EqualFold(scheme, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowedImage checks an image URL against the allowed hosts
+func isAllowedImage(target string, hosts []string) bool {
+	parsed, err := url.Parse(cleanLinkTarget(target))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range hosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanLinkTarget decodes entities and removes whitespace and control characters
+func cleanLinkTarget(target string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(target))
+}
+
+// linkScheme returns the scheme of a URL, if it has one
+func linkScheme(target string) (string, bool) {
+	for i, r := range target {
+		switch {
+		case r == ':':
+			return target[:i], i > 0
+		case r == '/' || r == '?' || r == '#':
+			return "", false
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,122 @@
+package sanitize
+
+import (
+	"strings"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Sanitization Pipelines
+// --------------------------------------------------
+// **************************************************
+
+// Sanitizer is a function that sanitizes a string
+type Sanitizer func(string) string
+
+// Chain combines sanitizers into one that applies them in order
+func Chain(sanitizers ...Sanitizer) Sanitizer {
+	steps := make([]Sanitizer, len(sanitizers))
+	copy(steps, sanitizers)
+
+	return func(s string) string {
+		for _, step := range steps {
+			s = step(s)
+		}
+		return s
+	}
+}
+
+// Pipeline builds a reusable sanitizer from ordered steps. Each builder
+// method returns a new pipeline, so a shared base pipeline can be extended
+// without affecting other users of it.
+//
+//	clean := sanitize.NewPipeline().Trim().NormalizeWhitespace().StripHTML().MaxLength(255).Build()
+//	name := clean(input)
+type Pipeline struct {
+	steps []Sanitizer
+}
+
+// NewPipeline creates a new empty pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Then adds a custom sanitizer step
+func (p *Pipeline) Then(step Sanitizer) *Pipeline {
+	steps := make([]Sanitizer, len(p.steps), len(p.steps)+1)
+	copy(steps, p.steps)
+	return &Pipeline{steps: append(steps, step)}
+}
+
+// Trim adds a step that trims leading and trailing whitespace
+func (p *Pipeline) Trim() *Pipeline {
+	return p.Then(strings.TrimSpace)
+}
+
+// Lowercase adds a step that converts to lowercase
+func (p *Pipeline) Lowercase() *Pipeline {
+	return p.Then(strings.ToLower)
+}
+
+// Uppercase adds a step that converts to uppercase
+func (p *Pipeline) Uppercase() *Pipeline {
+	return p.Then(strings.ToUpper)
+}
+
+// NormalizeWhitespace adds a step that collapses runs of whitespace
+func (p *Pipeline) NormalizeWhitespace() *Pipeline {
+	return p.Then(NormalizeWhitespace)
+}
+
+// StripHTML adds a step that removes HTML comments and tags
+func (p *Pipeline) StripHTML() *Pipeline {
+	return p.Then(func(s string) string {
+		return RemoveHTMLTags(StripHTMLComments(s))
+	})
+}
+
+// EscapeHTML adds a step that escapes HTML special characters
+func (p *Pipeline) EscapeHTML() *Pipeline {
+	return p.Then(EscapeHTML)
+}
+
+// RemoveControlChars adds a step that removes control characters
+func (p *Pipeline) RemoveControlChars() *Pipeline {
+	return p.Then(RemoveControlChars)
+}
+
+// RemoveSpecialChars adds a step that keeps only letters, digits and spaces
+func (p *Pipeline) RemoveSpecialChars() *Pipeline {
+	return p.Then(RemoveSpecialChars)
+}
+
+// NormalizeUnicode adds a step that normalizes to NFC
+func (p *Pipeline) NormalizeUnicode() *Pipeline {
+	return p.Then(NormalizeUnicode)
+}
+
+// RemoveInvisibleChars adds a step that removes zero-width and bidi control characters
+func (p *Pipeline) RemoveInvisibleChars() *Pipeline {
+	return p.Then(RemoveInvisibleChars)
+}
+
+// MaxLength adds a step that truncates to at most n characters, never
+// splitting a multi-byte character or grapheme cluster
+func (p *Pipeline) MaxLength(n int) *Pipeline {
+	return p.Then(func(s string) string {
+		return Truncate(s, n)
+	})
+}
+
+// Build returns the pipeline as a reusable sanitizer
+func (p *Pipeline) Build() Sanitizer {
+	return Chain(p.steps...)
+}
+
+// Apply runs the pipeline on a string
+func (p *Pipeline) Apply(s string) string {
+	for _, step := range p.steps {
+		s = step(s)
+	}
+	return s
+}
@@ -0,0 +1,345 @@
+package sanitize
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Profanity Filtering
+// --------------------------------------------------
+// **************************************************
+
+// DefaultProfanityLocale is the locale of the built-in word list
+const DefaultProfanityLocale = "en"
+
+// defaultProfanity is the built-in English word list
+var defaultProfanity = []string{
+	"arse", "arsehole", "ass", "asshole", "bastard", "bitch", "bollocks",
+	"bullshit", "cock", "crap", "cunt", "damn", "dick", "dickhead", "fuck",
+	"motherfucker", "piss", "prick", "pussy", "shit", "slut", "twat",
+	"wanker", "whore",
+}
+
+// leetSubstitutions maps common character substitutions back to letters
+var leetSubstitutions = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't',
+}
+
+// profanitySuffixes are inflections matched on top of dictionary words,
+// so "fucking" and "shitty" match "fuck" and "shit"
+var profanitySuffixes = []string{"ing", "in", "ers", "er", "ed", "es", "s", "y"}
+
+// maxSpacedRun bounds how many single-character tokens are joined when
+// looking for spaced-out words such as "f u c k"
+const maxSpacedRun = 24
+
+// ProfanityFilter detects and masks profanity using per-locale word lists.
+// Matching is whole-word and resists common evasions: letter substitutions
+// ("sh1t"), repeated letters ("fuuuck"), simple inflections ("fucking") and
+// spacing ("f u c k", "f.u.c.k").
+type ProfanityFilter struct {
+	mu           sync.RWMutex
+	dictionaries map[string]map[string]string // locale -> collapsed form -> term
+	allowlist    map[string]bool
+	mask         rune
+}
+
+// NewProfanityFilter creates a new profanity filter with the built-in
+// English word list
+func NewProfanityFilter() *ProfanityFilter {
+	pf := &ProfanityFilter{
+		dictionaries: make(map[string]map[string]string),
+		allowlist:    make(map[string]bool),
+		mask:         '*',
+	}
+	pf.AddWords(DefaultProfanityLocale, defaultProfanity...)
+	return pf
+}
+
+// AddWords adds words to a locale's dictionary
+func (pf *ProfanityFilter) AddWords(locale string, words ...string) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	dict, ok := pf.dictionaries[locale]
+	if !ok {
+		dict = make(map[string]string)
+		pf.dictionaries[locale] = dict
+	}
+	for _, word := range words {
+		term := normalizeProfanityToken(word)
+		if term != "" {
+			dict[collapseRepeats(term)] = term
+		}
+	}
+}
+
+// RemoveWords removes words from a locale's dictionary
+func (pf *ProfanityFilter) RemoveWords(locale string, words ...string) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	dict := pf.dictionaries[locale]
+	for _, word := range words {
+		delete(dict, collapseRepeats(normalizeProfanityToken(word)))
+	}
+}
+
+// ClearLocale removes a locale's dictionary, including the built-in one
+func (pf *ProfanityFilter) ClearLocale(locale string) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	delete(pf.dictionaries, locale)
+}
+
+// Allow adds words that are never treated as profanity, even when they
+// match a dictionary word after normalization
+func (pf *ProfanityFilter) Allow(words ...string) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	for _, word := range words {
+		pf.allowlist[normalizeProfanityToken(word)] = true
+	}
+}
+
+// SetMask sets the character used to mask profanity
+func (pf *ProfanityFilter) SetMask(mask rune) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	pf.mask = mask
+}
+
+// ContainsProfanity checks if text contains profanity from the given
+// locales, or from all locales when none are given
+func (pf *ProfanityFilter) ContainsProfanity(text string, locales ...string) bool {
+	_, matches := pf.Filter(text, locales...)
+	return len(matches) > 0
+}
+
+// Filter masks profanity from the given locales, or from all locales when
+// none are given. It returns the cleaned text and the dictionary terms that
+// matched, in order of first occurrence.
+func (pf *ProfanityFilter) Filter(text string, locales ...string) (string, []string) {
+	pf.mu.RLock()
+	defer pf.mu.RUnlock()
+
+	runes := []rune(text)
+	tokens := profanityTokens(runes)
+	masked := make([]bool, len(runes))
+	matches := make([]string, 0)
+	seen := make(map[string]bool)
+
+	record := func(term string, start, end int) {
+		for i := start; i < end; i++ {
+			if !unicode.IsSpace(runes[i]) {
+				masked[i] = true
+			}
+		}
+		if !seen[term] {
+			seen[term] = true
+			matches = append(matches, term)
+		}
+	}
+
+	// Whole words, then without edge symbols, which are more often
+	// punctuation than substitutions, e.g. "shit!!!"
+	for _, tok := range tokens {
+		if term, ok := pf.match(tok.normalized, locales); ok {
+			record(term, tok.start, tok.end)
+			continue
+		}
+		for _, span := range trimmedSpans(runes, tok.start, tok.end) {
+			if term, ok := pf.match(normalizeProfanityToken(string(runes[span[0]:span[1]])), locales); ok {
+				record(term, span[0], span[1])
+				break
+			}
+		}
+	}
+
+	// Runs of single characters, e.g. "f u c k"
+	for i := 0; i < len(tokens); {
+		j := i
+		for j < len(tokens) && j-i < maxSpacedRun && len([]rune(tokens[j].normalized)) == 1 &&
+			(j == i || tokens[j].start-tokens[j-1].end <= 2) {
+			j++
+		}
+		if j-i >= 3 {
+			pf.matchSpacedRun(tokens[i:j], locales, record)
+		}
+		if j == i {
+			j++
+		}
+		i = j
+	}
+
+	if len(matches) == 0 {
+		return text, matches
+	}
+
+	for i := range runes {
+		if masked[i] {
+			runes[i] = pf.mask
+		}
+	}
+	return string(runes), matches
+}
+
+// matchSpacedRun checks joined sub-runs of single-character tokens, longest first.
+// Callers must hold pf.mu.
+func (pf *ProfanityFilter) matchSpacedRun(run []profanityToken, locales []string, record func(string, int, int)) {
+	for start := 0; start < len(run); start++ {
+		for end := len(run); end-start >= 3; end-- {
+			var joined strings.Builder
+			for _, tok := range run[start:end] {
+				joined.WriteString(tok.normalized)
+			}
+			if term, ok := pf.match(joined.String(), locales); ok {
+				record(term, run[start].start, run[end-1].end)
+				start = end - 1
+				break
+			}
+		}
+	}
+}
+
+// match looks a normalized token up in the dictionaries. Callers must hold pf.mu.
+func (pf *ProfanityFilter) match(token string, locales []string) (string, bool) {
+	if token == "" || pf.allowlist[token] {
+		return "", false
+	}
+
+	if term, ok := pf.lookup(token, locales); ok {
+		return term, true
+	}
+	for _, suffix := range profanitySuffixes {
+		stem := strings.TrimSuffix(token, suffix)
+		if stem != token && len(stem) >= 3 && !pf.allowlist[stem] {
+			if term, ok := pf.lookup(stem, locales); ok {
+				return term, true
+			}
+		}
+	}
+	return "", false
+}
+
+// lookup finds a token in the dictionaries. A token matches a term when both
+// collapse to the same letters and the token is at least as long as the term,
+// so "fuuuck" matches "fuck" but "as" does not match "ass".
+// Callers must hold pf.mu.
+func (pf *ProfanityFilter) lookup(token string, locales []string) (string, bool) {
+	collapsed := collapseRepeats(token)
+	check := func(dict map[string]string) (string, bool) {
+		term, ok := dict[collapsed]
+		if ok && len(token) >= len(term) {
+			return term, true
+		}
+		return "", false
+	}
+
+	if len(locales) == 0 {
+		for _, dict := range pf.dictionaries {
+			if term, ok := check(dict); ok {
+				return term, true
+			}
+		}
+		return "", false
+	}
+
+	for _, locale := range locales {
+		if term, ok := check(pf.dictionaries[locale]); ok {
+			return term, true
+		}
+	}
+	return "", false
+}
+
+// profanityToken is a word in the input, as rune offsets and normalized text
+type profanityToken struct {
+	start      int
+	end        int
+	normalized string
+}
+
+// profanityTokens splits text into words made of letters, digits and
+// substitution symbols
+func profanityTokens(runes []rune) []profanityToken {
+	tokens := make([]profanityToken, 0)
+	for i := 0; i < len(runes); {
+		if !isProfanityChar(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && isProfanityChar(runes[i]) {
+			i++
+		}
+		tokens = append(tokens, profanityToken{
+			start:      start,
+			end:        i,
+			normalized: normalizeProfanityToken(string(runes[start:i])),
+		})
+	}
+	return tokens
+}
+
+// isProfanityChar checks if r can be part of a word
+func isProfanityChar(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return true
+	}
+	_, ok := leetSubstitutions[r]
+	return ok
+}
+
+// trimmedSpans returns the rune ranges of runes[start:end] without the
+// trailing substitution symbols, and without leading and trailing ones,
+// skipping those equal to the whole word or empty
+func trimmedSpans(runes []rune, start, end int) [][2]int {
+	isWordChar := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	trimmedEnd := end
+	for trimmedEnd > start && !isWordChar(runes[trimmedEnd-1]) {
+		trimmedEnd--
+	}
+	trimmedStart := start
+	for trimmedStart < trimmedEnd && !isWordChar(runes[trimmedStart]) {
+		trimmedStart++
+	}
+
+	spans := make([][2]int, 0, 2)
+	if trimmedEnd != end && trimmedEnd > start {
+		spans = append(spans, [2]int{start, trimmedEnd})
+	}
+	if trimmedStart != start && trimmedStart < trimmedEnd {
+		spans = append(spans, [2]int{trimmedStart, trimmedEnd})
+	}
+	return spans
+}
+
+// normalizeProfanityToken lowercases a word and undoes letter substitutions
+func normalizeProfanityToken(s string) string {
+	s = NormalizeUnicodeNFKC(strings.ToLower(strings.TrimSpace(s)))
+	return strings.Map(func(r rune) rune {
+		if letter, ok := leetSubstitutions[r]; ok {
+			return letter
+		}
+		return r
+	}, s)
+}
+
+// collapseRepeats reduces runs of the same character to one character
+func collapseRepeats(s string) string {
+	var b strings.Builder
+	var last rune = -1
+	for _, r := range s {
+		if r != last {
+			b.WriteRune(r)
+		}
+		last = r
+	}
+	return b.String()
+}
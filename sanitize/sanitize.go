@@ -2,7 +2,6 @@ package sanitize
 
 import (
 	"html"
-	"net/url"
 	"regexp"
 	"strings"
 	"unicode"
@@ -141,24 +140,15 @@ func SanitizeEmail(email string) string {
 	return email
 }
 
-// SanitizeURL cleans and validates URLs
+// SanitizeURL cleans and validates URLs, allowing only http and https.
+// It returns an empty string for rejected URLs; use SanitizeURLWithOptions
+// for host allowlists, tracking parameter removal and typed errors.
 func SanitizeURL(rawURL string) string {
-	// Trim whitespace
-	rawURL = strings.TrimSpace(rawURL)
-
-	// Parse URL to validate format
-	parsedURL, err := url.Parse(rawURL)
+	cleaned, err := SanitizeURLWithOptions(rawURL, URLOptions{})
 	if err != nil {
 		return ""
 	}
-
-	// Only allow http and https schemes
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return ""
-	}
-
-	// Return cleaned URL
-	return parsedURL.String()
+	return cleaned
 }
 
 // **************************************************
@@ -167,24 +157,14 @@ func SanitizeURL(rawURL string) string {
 // --------------------------------------------------
 // **************************************************
 
-// SanitizeFilename removes dangerous characters from filenames
+// SanitizeFilename removes dangerous characters from filenames using the
+// default filename policy, returning an empty string if nothing usable remains
 func SanitizeFilename(filename string) string {
-	// Remove path separators and other dangerous characters
-	dangerousChars := []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|"}
-
-	for _, char := range dangerousChars {
-		filename = strings.ReplaceAll(filename, char, "_")
-	}
-
-	// Remove leading/trailing dots and spaces
-	filename = strings.Trim(filename, ". ")
-
-	// Limit length
-	if len(filename) > 255 {
-		filename = filename[:255]
+	sanitized, err := DefaultFilenamePolicy().Sanitize(filename)
+	if err != nil {
+		return ""
 	}
-
-	return filename
+	return sanitized
 }
 
 // SanitizePath removes dangerous path components
@@ -242,40 +222,6 @@ func RemoveControlChars(s string) string {
 	return result.String()
 }
 
-// NormalizeUnicode normalizes Unicode characters
-func NormalizeUnicode(s string) string {
-	// This is a simplified version - in production you might want to use golang.org/x/text/unicode/norm
-	return strings.ToLower(s)
-}
-
-// RemoveEmojis removes emoji characters
-func RemoveEmojis(s string) string {
-	var result strings.Builder
-	for _, r := range s {
-		// Check if rune is an emoji (simplified check)
-		if r < 0x1F600 || r > 0x1F64F { // Not in emoji range
-			if r < 0x1F300 || r > 0x1F5FF { // Not in misc symbols range
-				if r < 0x1F680 || r > 0x1F6FF { // Not in transport range
-					if r < 0x1F700 || r > 0x1F77F { // Not in alchemical symbols
-						if r < 0x1F780 || r > 0x1F7FF { // Not in geometric shapes extended
-							if r < 0x1F800 || r > 0x1F8FF { // Not in supplemental arrows-C
-								if r < 0x1F900 || r > 0x1F9FF { // Not in supplemental symbols and pictographs
-									if r < 0x1FA00 || r > 0x1FA6F { // Not in chess symbols
-										if r < 0x1FA70 || r > 0x1FAFF { // Not in symbols and pictographs extended-A
-											result.WriteRune(r)
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-	return result.String()
-}
-
 // **************************************************
 // --------------------------------------------------
 // General Purpose Sanitization
@@ -0,0 +1,110 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Truncation Functions
+// Truncation counts user-perceived characters (grapheme clusters), so
+// accented letters, flags and joined emojis are never split.
+// --------------------------------------------------
+// **************************************************
+
+// Ellipsis is appended by TruncateWithEllipsis
+const Ellipsis = "…"
+
+// Truncate shortens s to at most n characters
+func Truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	graphemes := uniseg.NewGraphemes(s)
+	count := 0
+	for graphemes.Next() {
+		if count == n {
+			start, _ := graphemes.Positions()
+			return s[:start]
+		}
+		count++
+	}
+	return s
+}
+
+// TruncateBytes shortens s to at most n bytes without splitting a character,
+// for limits expressed in bytes such as file names and database columns
+func TruncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+
+	graphemes := uniseg.NewGraphemes(s)
+	end := 0
+	for graphemes.Next() {
+		_, to := graphemes.Positions()
+		if to > n {
+			break
+		}
+		end = to
+	}
+	return s[:end]
+}
+
+// TruncateWords shortens s to its first n words, keeping the original
+// spacing between them
+func TruncateWords(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	words := 0
+	inWord := false
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if inWord && words == n {
+				return s[:i]
+			}
+			inWord = false
+			continue
+		}
+		if !inWord {
+			inWord = true
+			words++
+		}
+	}
+	return s
+}
+
+// TruncateWithEllipsis shortens s to at most n characters including a
+// trailing ellipsis, preferring to cut at a word boundary when one falls
+// in the second half of the allowed length
+func TruncateWithEllipsis(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if uniseg.GraphemeClusterCount(s) <= n {
+		return s
+	}
+
+	ellipsisLength := uniseg.GraphemeClusterCount(Ellipsis)
+	if n <= ellipsisLength {
+		return Truncate(s, n)
+	}
+
+	cut := Truncate(s, n-ellipsisLength)
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 && uniseg.GraphemeClusterCount(cut[:i]) > (n-ellipsisLength)/2 {
+		cut = cut[:i]
+	}
+
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + Ellipsis
+}
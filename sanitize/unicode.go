@@ -0,0 +1,129 @@
+package sanitize
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// **************************************************
+// --------------------------------------------------
+// Unicode Normalization Functions
+// --------------------------------------------------
+// **************************************************
+
+// zeroWidthChars are invisible characters commonly used to disguise text
+var zeroWidthChars = map[rune]bool{
+	'\u00AD': true, // soft hyphen
+	'\u034F': true, // combining grapheme joiner
+	'\u180E': true, // mongolian vowel separator
+	'\u200B': true, // zero width space
+	'\u200C': true, // zero width non-joiner
+	'\u200D': true, // zero width joiner
+	'\u2060': true, // word joiner
+	'\u2061': true, // function application
+	'\u2062': true, // invisible times
+	'\u2063': true, // invisible separator
+	'\u2064': true, // invisible plus
+	'\uFEFF': true, // zero width no-break space (BOM)
+}
+
+// bidiControlChars are bidirectional formatting characters that can reorder
+// displayed text, e.g. to disguise a file extension
+var bidiControlChars = map[rune]bool{
+	'\u061C': true, // arabic letter mark
+	'\u200E': true, // left-to-right mark
+	'\u200F': true, // right-to-left mark
+	'\u202A': true, // left-to-right embedding
+	'\u202B': true, // right-to-left embedding
+	'\u202C': true, // pop directional formatting
+	'\u202D': true, // left-to-right override
+	'\u202E': true, // right-to-left override
+	'\u2066': true, // left-to-right isolate
+	'\u2067': true, // right-to-left isolate
+	'\u2068': true, // first strong isolate
+	'\u2069': true, // pop directional isolate
+}
+
+// homoglyphs maps Cyrillic and Greek letters to the Latin letters they are
+// visually indistinguishable from
+var homoglyphs = map[rune]rune{
+	// Cyrillic lowercase
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i',
+	'ј': 'j', 'ԁ': 'd', 'һ': 'h', 'ԛ': 'q', 'ԝ': 'w', 'ү': 'y', 'ӏ': 'l',
+	// Cyrillic uppercase
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I',
+	'Ј': 'J', 'Ԛ': 'Q', 'Ԝ': 'W', 'Ү': 'Y', 'Ӏ': 'I',
+	// Greek lowercase
+	'α': 'a', 'ο': 'o', 'ρ': 'p', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'υ': 'u',
+	'χ': 'x', 'γ': 'y',
+	// Greek uppercase
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K',
+	'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+	// Latin lookalikes
+	'ı': 'i', 'ȷ': 'j',
+}
+
+// NormalizeUnicode normalizes a string to NFC, the composed form used for
+// storage and comparison of user-visible text
+func NormalizeUnicode(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NormalizeUnicodeNFKC normalizes a string to NFKC, which also folds
+// compatibility characters such as fullwidth letters, ligatures and
+// mathematical alphanumerics into their plain forms
+func NormalizeUnicodeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// RemoveZeroWidthChars removes zero-width and other invisible characters.
+// Removing the zero width joiner also splits joined emoji sequences.
+func RemoveZeroWidthChars(s string) string {
+	return removeRunes(s, zeroWidthChars)
+}
+
+// RemoveBidiControls removes bidirectional text control characters
+func RemoveBidiControls(s string) string {
+	return removeRunes(s, bidiControlChars)
+}
+
+// RemoveInvisibleChars removes zero-width and bidirectional control characters
+func RemoveInvisibleChars(s string) string {
+	return RemoveBidiControls(RemoveZeroWidthChars(s))
+}
+
+// FoldHomoglyphs replaces Cyrillic and Greek letters that look like Latin
+// letters with their Latin counterparts, e.g. "раураl" becomes "paypal"
+func FoldHomoglyphs(s string) string {
+	return strings.Map(func(r rune) rune {
+		if latin, ok := homoglyphs[r]; ok {
+			return latin
+		}
+		return r
+	}, s)
+}
+
+// NormalizeIdentifier prepares a user-chosen identifier such as a username
+// for uniqueness checks: NFKC normalization, removal of invisible characters
+// and lowercasing, optionally folding homoglyphs so spoofed look-alikes of
+// an existing name collide with it
+func NormalizeIdentifier(s string, foldHomoglyphs bool) string {
+	s = RemoveInvisibleChars(NormalizeUnicodeNFKC(s))
+	if foldHomoglyphs {
+		s = FoldHomoglyphs(s)
+	}
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// removeRunes removes every rune in the set from s
+func removeRunes(s string, set map[rune]bool) string {
+	return strings.Map(func(r rune) rune {
+		if set[r] {
+			return -1
+		}
+		return r
+	}, s)
+}
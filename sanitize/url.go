@@ -0,0 +1,249 @@
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+// **************************************************
+// --------------------------------------------------
+// URL Sanitization
+// --------------------------------------------------
+// **************************************************
+
+// URL sanitization errors
+var (
+	ErrInvalidURL       = errors.New("invalid URL")
+	ErrURLTooLong       = errors.New("URL too long")
+	ErrDisallowedScheme = errors.New("URL scheme not allowed")
+	ErrDisallowedHost   = errors.New("URL host not allowed")
+	ErrSuspiciousHost   = errors.New("URL host looks like a homograph")
+)
+
+// trackingParams are query parameters added by ad and analytics platforms
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "gclsrc": true, "dclid": true, "gbraid": true,
+	"wbraid": true, "msclkid": true, "yclid": true, "twclid": true, "ttclid": true,
+	"li_fat_id": true, "igshid": true, "mc_cid": true, "mc_eid": true,
+	"_hsenc": true, "_hsmi": true, "mkt_tok": true, "vero_id": true,
+	"oly_anon_id": true, "oly_enc_id": true, "rb_clickid": true, "s_cid": true,
+}
+
+// trackingParamPrefixes are prefixes of tracking parameter families
+var trackingParamPrefixes = []string{"utm_", "pk_", "mtm_"}
+
+// URLOptions configures SanitizeURLWithOptions
+type URLOptions struct {
+	// AllowedSchemes lists the allowed URL schemes. Defaults to http and https.
+	AllowedSchemes []string
+	// AllowedHosts restricts URLs to these hosts. A leading dot allows a
+	// domain and its subdomains, e.g. ".example.com". Empty allows any host.
+	AllowedHosts []string
+	// StripTrackingParams removes known tracking query parameters such as
+	// utm_source, fbclid and gclid
+	StripTrackingParams bool
+	// RejectHomographs rejects internationalized hosts that mix scripts or
+	// imitate Latin names with look-alike letters, e.g. "аpple.com"
+	RejectHomographs bool
+	// ASCIIHost returns internationalized hosts in punycode form
+	ASCIIHost bool
+	// MaxLength rejects URLs longer than this many bytes; zero means no limit
+	MaxLength int
+}
+
+// DefaultURLOptions returns options for user-supplied links: http and https
+// only, tracking parameters stripped, homographs rejected, at most 2048 bytes
+func DefaultURLOptions() URLOptions {
+	return URLOptions{
+		AllowedSchemes:      []string{"http", "https"},
+		StripTrackingParams: true,
+		RejectHomographs:    true,
+		MaxLength:           2048,
+	}
+}
+
+// SanitizeURLWithOptions cleans and validates a URL, returning an error
+// wrapping one of the URL sanitization errors when it is rejected
+func SanitizeURLWithOptions(rawURL string, opts URLOptions) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if opts.MaxLength > 0 && len(rawURL) > opts.MaxLength {
+		return "", fmt.Errorf("%w: %d bytes exceeds %d", ErrURLTooLong, len(rawURL), opts.MaxLength)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	schemes := opts.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	if !containsFold(schemes, parsed.Scheme) {
+		return "", fmt.Errorf("%w: %q", ErrDisallowedScheme, parsed.Scheme)
+	}
+
+	if host := parsed.Hostname(); host != "" {
+		asciiHost, err := toASCIIHost(host)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid host %q: %v", ErrInvalidURL, host, err)
+		}
+
+		if opts.RejectHomographs {
+			unicodeHost, _ := idna.Lookup.ToUnicode(asciiHost)
+			if IsHomographHost(unicodeHost) {
+				return "", fmt.Errorf("%w: %q", ErrSuspiciousHost, host)
+			}
+		}
+
+		if len(opts.AllowedHosts) > 0 && !hostAllowed(asciiHost, opts.AllowedHosts) {
+			return "", fmt.Errorf("%w: %q", ErrDisallowedHost, host)
+		}
+
+		if opts.ASCIIHost && net.ParseIP(host) == nil {
+			if port := parsed.Port(); port != "" {
+				parsed.Host = asciiHost + ":" + port
+			} else {
+				parsed.Host = asciiHost
+			}
+		}
+	} else if len(opts.AllowedHosts) > 0 {
+		return "", fmt.Errorf("%w: URL has no host", ErrDisallowedHost)
+	}
+
+	if opts.StripTrackingParams && parsed.RawQuery != "" {
+		parsed.RawQuery = stripTrackingParams(parsed.RawQuery)
+	}
+
+	return parsed.String(), nil
+}
+
+// StripTrackingParams removes known tracking query parameters from a URL,
+// returning the URL unchanged if it cannot be parsed
+func StripTrackingParams(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.RawQuery == "" {
+		return rawURL
+	}
+	parsed.RawQuery = stripTrackingParams(parsed.RawQuery)
+	return parsed.String()
+}
+
+// IsHomographHost checks if a host in Unicode form has a label that mixes
+// Latin with Cyrillic, Greek or Armenian letters, or that consists of
+// non-Latin letters which all imitate Latin ones
+func IsHomographHost(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		if isASCII(label) {
+			continue
+		}
+
+		var latin, confusable bool
+		for _, r := range label {
+			switch {
+			case unicode.Is(unicode.Latin, r):
+				latin = true
+			case unicode.In(r, unicode.Cyrillic, unicode.Greek, unicode.Armenian):
+				confusable = true
+			}
+		}
+		if latin && confusable {
+			return true
+		}
+		if folded := FoldHomoglyphs(label); isASCII(folded) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTrackingParams removes tracking parameters from a raw query,
+// preserving the order and encoding of the remaining parameters
+func stripTrackingParams(rawQuery string) string {
+	kept := make([]string, 0)
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(pair, "=")
+		if name, err := url.QueryUnescape(key); err == nil && isTrackingParam(name) {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	return strings.Join(kept, "&")
+}
+
+// isTrackingParam checks if a query parameter name is a known tracking parameter
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	if trackingParams[name] {
+		return true
+	}
+	for _, prefix := range trackingParamPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostAllowed checks an ASCII host against an allowlist
+func hostAllowed(host string, allowed []string) bool {
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		domain := strings.TrimPrefix(entry, ".")
+		if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+			domain = ascii
+		}
+
+		if host == domain {
+			return true
+		}
+		if strings.HasPrefix(entry, ".") && strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// toASCIIHost lowercases host, converting it to punycode when it has
+// Unicode labels. IP literals and ASCII hosts are kept as they are, since
+// IDNA would reject those with characters such as '_' that resolvers
+// accept.
+func toASCIIHost(host string) (string, error) {
+	if net.ParseIP(host) != nil || isASCII(host) {
+		return strings.ToLower(host), nil
+	}
+	asciiHost, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(asciiHost), nil
+}
+
+// containsFold checks if list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isASCII checks if s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arbenlabs/stoner/cache"
+)
+
+// **************************************************
+// Manager
+// Manager caches secrets from a provider for a TTL and refreshes
+// them in the background, calling rotation callbacks when a
+// value changes so that keys and credentials can be swapped live.
+// **************************************************
+
+// DefaultSecretTTL is how long the manager caches a secret by default
+const DefaultSecretTTL = 5 * time.Minute
+
+// RotationFunc is called when a secret's value changes
+type RotationFunc func(name string, previous, current Secret)
+
+// Manager caches secrets and notifies subscribers of rotations
+type Manager struct {
+	provider Provider
+	cache    *cache.Cache[string, Secret]
+
+	mu       sync.Mutex
+	known    map[string]Secret
+	handlers map[string][]RotationFunc
+	stop     chan struct{}
+	done     chan struct{}
+
+	errorHandler func(name string, err error)
+}
+
+// NewManager creates a manager caching secrets from provider for ttl; 0
+// uses DefaultSecretTTL
+func NewManager(provider Provider, ttl time.Duration) *Manager {
+	if ttl <= 0 {
+		ttl = DefaultSecretTTL
+	}
+	return &Manager{
+		provider: provider,
+		cache:    cache.New(cache.Config[string, Secret]{TTL: ttl}),
+		known:    make(map[string]Secret),
+		handlers: make(map[string][]RotationFunc),
+	}
+}
+
+// SetErrorHandler sets a function called when a background refresh fails
+func (m *Manager) SetErrorHandler(handler func(name string, err error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errorHandler = handler
+}
+
+// Get returns a secret, loading it from the provider when it is not
+// cached. Concurrent loads of the same secret share one provider call.
+func (m *Manager) Get(ctx context.Context, name string) (Secret, error) {
+	return m.cache.GetOrLoad(ctx, name, func(ctx context.Context, name string) (Secret, error) {
+		secret, err := m.provider.GetSecret(ctx, name)
+		if err != nil {
+			return Secret{}, err
+		}
+		m.observe(name, secret)
+		return secret, nil
+	})
+}
+
+// MustGet returns a secret or panics, for loading required secrets at startup
+func (m *Manager) MustGet(ctx context.Context, name string) Secret {
+	secret, err := m.Get(ctx, name)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load secret %s: %v", name, err))
+	}
+	return secret
+}
+
+// OnRotate registers fn to be called when the value of name changes. The
+// secret is loaded if needed, and fn is called once with a zero previous
+// secret and the current value, so the same function can apply the
+// initial value and later rotations.
+func (m *Manager) OnRotate(ctx context.Context, name string, fn RotationFunc) error {
+	current, err := m.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.handlers[name] = append(m.handlers[name], fn)
+	m.mu.Unlock()
+
+	fn(name, Secret{}, current)
+	return nil
+}
+
+// Refresh reloads every secret that has been read, calling rotation
+// callbacks for those that changed, and returns the first error
+func (m *Manager) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.known))
+	for name := range m.known {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, name := range names {
+		secret, err := m.provider.GetSecret(ctx, name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to refresh secret %s: %w", name, err)
+			}
+			m.reportError(name, err)
+			continue
+		}
+		m.cache.Set(name, secret)
+		m.observe(name, secret)
+	}
+	return firstErr
+}
+
+// Invalidate drops a cached secret so the next Get reloads it
+func (m *Manager) Invalidate(name string) {
+	m.cache.Delete(name)
+}
+
+// Start refreshes all read secrets every interval until Stop is called
+func (m *Manager) Start(interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.refreshLoop(interval, m.stop, m.done)
+}
+
+// Stop stops the background refresh and waits for it to exit
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	if m.stop == nil {
+		m.mu.Unlock()
+		return
+	}
+	close(m.stop)
+	done := m.done
+	m.stop, m.done = nil, nil
+	m.mu.Unlock()
+
+	<-done
+}
+
+// refreshLoop calls Refresh on every tick
+func (m *Manager) refreshLoop(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			_ = m.Refresh(context.Background())
+		}
+	}
+}
+
+// observe records a loaded value and calls rotation callbacks if it changed
+func (m *Manager) observe(name string, secret Secret) {
+	m.mu.Lock()
+	previous, seen := m.known[name]
+	m.known[name] = secret
+	var handlers []RotationFunc
+	if seen && !previous.equalSecret(secret) {
+		handlers = append(handlers, m.handlers[name]...)
+	}
+	m.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(name, previous, secret)
+	}
+}
+
+// reportError forwards a refresh error to the error handler
+func (m *Manager) reportError(name string, err error) {
+	m.mu.Lock()
+	handler := m.errorHandler
+	m.mu.Unlock()
+
+	if handler != nil {
+		handler(name, err)
+	}
+}
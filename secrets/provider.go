@@ -0,0 +1,150 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// **************************************************
+// Providers
+// A Provider looks secrets up by name. EnvProvider and
+// FileProvider cover environment variables and mounted secret
+// files; other stores such as Vault or a cloud secret manager
+// implement Provider directly.
+// **************************************************
+
+// ErrNotFound is returned when a provider has no secret with the requested name
+var ErrNotFound = errors.New("secret not found")
+
+// Provider looks up secrets by name
+type Provider interface {
+	GetSecret(ctx context.Context, name string) (Secret, error)
+}
+
+// ProviderFunc adapts a function to Provider
+type ProviderFunc func(ctx context.Context, name string) (Secret, error)
+
+// GetSecret calls f
+func (f ProviderFunc) GetSecret(ctx context.Context, name string) (Secret, error) {
+	return f(ctx, name)
+}
+
+// EnvProvider reads secrets from environment variables. The name is
+// upper-cased, "-", "." and "/" become "_", and Prefix is prepended, so
+// "db.password" with prefix "APP_" reads APP_DB_PASSWORD.
+type EnvProvider struct {
+	Prefix string
+}
+
+// NewEnvProvider creates a new environment provider
+func NewEnvProvider(prefix string) *EnvProvider {
+	return &EnvProvider{Prefix: prefix}
+}
+
+// envNameReplacer maps secret names to environment variable names
+var envNameReplacer = strings.NewReplacer("-", "_", ".", "_", "/", "_")
+
+// GetSecret reads the secret's environment variable
+func (p *EnvProvider) GetSecret(ctx context.Context, name string) (Secret, error) {
+	key := p.Prefix + strings.ToUpper(envNameReplacer.Replace(name))
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return New(value), nil
+}
+
+// FileProvider reads secrets from files named after the secret in Dir, as
+// mounted by Docker and Kubernetes. A trailing newline is removed.
+type FileProvider struct {
+	Dir string
+}
+
+// NewFileProvider creates a new file provider
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{Dir: dir}
+}
+
+// GetSecret reads the secret's file
+func (p *FileProvider) GetSecret(ctx context.Context, name string) (Secret, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return Secret{}, fmt.Errorf("invalid secret name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+	secret := FromBytes(data)
+	clear(data)
+	return secret, nil
+}
+
+// StaticProvider serves secrets from memory, for tests and local development
+type StaticProvider struct {
+	mu      sync.RWMutex
+	secrets map[string]Secret
+}
+
+// NewStaticProvider creates a provider serving the given values
+func NewStaticProvider(values map[string]string) *StaticProvider {
+	p := &StaticProvider{secrets: make(map[string]Secret, len(values))}
+	for name, value := range values {
+		p.secrets[name] = New(value)
+	}
+	return p
+}
+
+// Set stores or replaces a secret
+func (p *StaticProvider) Set(name, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets[name] = New(value)
+}
+
+// GetSecret returns the stored secret
+func (p *StaticProvider) GetSecret(ctx context.Context, name string) (Secret, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	secret, ok := p.secrets[name]
+	if !ok {
+		return Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return secret, nil
+}
+
+// chainProvider tries providers in order
+type chainProvider []Provider
+
+// Chain returns a provider that tries each provider in order and returns
+// the first secret found. Errors other than ErrNotFound stop the chain.
+func Chain(providers ...Provider) Provider {
+	return chainProvider(providers)
+}
+
+// GetSecret returns the first secret found
+func (c chainProvider) GetSecret(ctx context.Context, name string) (Secret, error) {
+	for _, provider := range c {
+		secret, err := provider.GetSecret(ctx, name)
+		if err == nil {
+			return secret, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return Secret{}, err
+		}
+	}
+	return Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
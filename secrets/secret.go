@@ -0,0 +1,97 @@
+// Package secrets loads secrets from the environment, files or a custom
+// provider, caches them with a TTL, notifies subscribers when they rotate,
+// and wraps them in a Secret type that is redacted whenever it is printed,
+// logged or serialized.
+package secrets
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+)
+
+// **************************************************
+// Secret
+// A Secret only reveals its value through Reveal and Bytes. Every
+// other way of turning it into text prints Redacted.
+// **************************************************
+
+// Redacted is printed in place of a secret's value
+const Redacted = "[REDACTED]"
+
+// Secret holds a sensitive value
+type Secret struct {
+	value []byte
+}
+
+// New creates a secret from a value
+func New(value string) Secret {
+	return Secret{value: []byte(value)}
+}
+
+// FromBytes creates a secret from a copy of value
+func FromBytes(value []byte) Secret {
+	return Secret{value: append([]byte(nil), value...)}
+}
+
+// Reveal returns the secret's value
+func (s Secret) Reveal() string {
+	return string(s.value)
+}
+
+// Bytes returns a copy of the secret's value
+func (s Secret) Bytes() []byte {
+	return append([]byte(nil), s.value...)
+}
+
+// IsZero checks if the secret is empty
+func (s Secret) IsZero() bool {
+	return len(s.value) == 0
+}
+
+// Equal compares the secret's value with value in constant time
+func (s Secret) Equal(value string) bool {
+	return subtle.ConstantTimeCompare(s.value, []byte(value)) == 1
+}
+
+// equalSecret compares two secrets in constant time
+func (s Secret) equalSecret(other Secret) bool {
+	return subtle.ConstantTimeCompare(s.value, other.value) == 1
+}
+
+// String returns Redacted
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted, so %#v does not print the value
+func (s Secret) GoString() string {
+	return "secrets.Secret(" + Redacted + ")"
+}
+
+// Format prints Redacted for every verb, including %x and %+v
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+	fmt.Fprint(f, Redacted)
+}
+
+// LogValue logs Redacted
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// MarshalText encodes Redacted, so JSON, YAML and other encoders never
+// serialize the value
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// UnmarshalText sets the secret's value, so secrets can be loaded by the
+// config package and decoded from JSON or YAML
+func (s *Secret) UnmarshalText(text []byte) error {
+	s.value = append([]byte(nil), text...)
+	return nil
+}
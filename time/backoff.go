@@ -0,0 +1,175 @@
+package time
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// **************************************************
+// Backoff
+// Backoff computes exponential retry delays with jitter and
+// Retry runs an operation until it succeeds or the backoff
+// gives up.
+// **************************************************
+
+// Default backoff settings used by NewBackoff
+const (
+	DefaultInitialInterval = 500 * time.Millisecond
+	DefaultMultiplier      = 1.5
+	DefaultMaxInterval     = 60 * time.Second
+	DefaultMaxElapsedTime  = 15 * time.Minute
+	DefaultJitter          = 0.5
+)
+
+// Backoff represents an exponential backoff policy. A Backoff tracks the
+// progress of one retry sequence and is not safe for concurrent use.
+type Backoff struct {
+	InitialInterval time.Duration // delay before the first retry
+	Multiplier      float64       // growth factor per retry; values <= 0 are treated as 1
+	MaxInterval     time.Duration // upper bound for a single delay; 0 means no bound
+	MaxElapsedTime  time.Duration // stop once this much time has passed; 0 means no limit
+	MaxRetries      int           // stop after this many retries; 0 means no limit
+	Jitter          float64       // randomization factor from 0 (none) to 1
+
+	interval time.Duration
+	retries  int
+	start    time.Time
+}
+
+// NewBackoff creates a new backoff with the default settings
+func NewBackoff() *Backoff {
+	return &Backoff{
+		InitialInterval: DefaultInitialInterval,
+		Multiplier:      DefaultMultiplier,
+		MaxInterval:     DefaultMaxInterval,
+		MaxElapsedTime:  DefaultMaxElapsedTime,
+		Jitter:          DefaultJitter,
+	}
+}
+
+// Reset restarts the backoff sequence
+func (b *Backoff) Reset() {
+	b.interval = 0
+	b.retries = 0
+	b.start = time.Time{}
+}
+
+// Retries returns the number of delays handed out since the last reset
+func (b *Backoff) Retries() int {
+	return b.retries
+}
+
+// Next returns the delay before the next retry, or false when the backoff
+// has reached MaxRetries or MaxElapsedTime and the caller should give up
+func (b *Backoff) Next() (time.Duration, bool) {
+	now := time.Now()
+	if b.start.IsZero() {
+		b.start = now
+	}
+
+	if b.MaxRetries > 0 && b.retries >= b.MaxRetries {
+		return 0, false
+	}
+
+	if b.interval == 0 {
+		b.interval = b.InitialInterval
+	} else {
+		multiplier := b.Multiplier
+		if multiplier <= 0 {
+			multiplier = 1
+		}
+		b.interval = time.Duration(float64(b.interval) * multiplier)
+	}
+	if b.MaxInterval > 0 && (b.interval > b.MaxInterval || b.interval < 0) {
+		b.interval = b.MaxInterval
+	}
+
+	delay := b.jitter(b.interval)
+	if b.MaxElapsedTime > 0 && now.Sub(b.start)+delay > b.MaxElapsedTime {
+		return 0, false
+	}
+
+	b.retries++
+	return delay, true
+}
+
+// jitter randomizes d within ±Jitter of its value
+func (b *Backoff) jitter(d time.Duration) time.Duration {
+	factor := b.Jitter
+	if factor <= 0 || d <= 0 {
+		return d
+	}
+	if factor > 1 {
+		factor = 1
+	}
+
+	delta := factor * float64(d)
+	min := float64(d) - delta
+	return time.Duration(min + rand.Float64()*(2*delta))
+}
+
+// permanentError marks an error that should not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps err so that Retry stops immediately and returns it
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent checks if err or any error it wraps was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Retry calls fn until it succeeds, returns a Permanent error, the backoff
+// gives up, or ctx is done. The backoff is reset before the first attempt.
+func Retry(ctx context.Context, b *Backoff, fn func(ctx context.Context) error) error {
+	if b == nil {
+		b = NewBackoff()
+	}
+	b.Reset()
+
+	attempts := 0
+	for {
+		attempts++
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		delay, ok := b.Next()
+		if !ok {
+			return fmt.Errorf("retry failed after %d attempts: %w", attempts, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry aborted after %d attempts: %w: %w", attempts, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package time
+
+import (
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Calendar
+// Calendar provides business-day calculations with configurable
+// weekend days and holiday sets. Dates are evaluated in the
+// location of the times passed in.
+// **************************************************
+
+// Calendar represents a business calendar
+type Calendar struct {
+	mu                sync.RWMutex
+	weekend           map[time.Weekday]bool
+	holidays          map[Date]string
+	recurringHolidays map[recurringDay]string
+}
+
+// recurringDay is a month and day that repeats every year
+type recurringDay struct {
+	month int
+	day   int
+}
+
+// NewCalendar creates a new calendar. When no weekend days are given,
+// Saturday and Sunday are used.
+func NewCalendar(weekend ...time.Weekday) *Calendar {
+	if len(weekend) == 0 {
+		weekend = []time.Weekday{time.Saturday, time.Sunday}
+	}
+
+	c := &Calendar{
+		weekend:           make(map[time.Weekday]bool, len(weekend)),
+		holidays:          make(map[Date]string),
+		recurringHolidays: make(map[recurringDay]string),
+	}
+	for _, day := range weekend {
+		c.weekend[day] = true
+	}
+	return c
+}
+
+// SetWeekend replaces the calendar's weekend days
+func (c *Calendar) SetWeekend(days ...time.Weekday) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.weekend = make(map[time.Weekday]bool, len(days))
+	for _, day := range days {
+		c.weekend[day] = true
+	}
+}
+
+// AddHoliday adds a one-off holiday
+func (c *Calendar) AddHoliday(date Date, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.holidays[date] = name
+}
+
+// AddHolidays adds a set of one-off holidays
+func (c *Calendar) AddHolidays(holidays map[Date]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for date, name := range holidays {
+		c.holidays[date] = name
+	}
+}
+
+// AddRecurringHoliday adds a holiday that falls on the same month and day every year
+func (c *Calendar) AddRecurringHoliday(month, day int, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recurringHolidays[recurringDay{month: month, day: day}] = name
+}
+
+// RemoveHoliday removes a one-off holiday
+func (c *Calendar) RemoveHoliday(date Date) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.holidays, date)
+}
+
+// Holidays returns the one-off holidays in a year, keyed by date
+func (c *Calendar) Holidays(year int) map[Date]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[Date]string)
+	for date, name := range c.holidays {
+		if date.Year == year {
+			result[date] = name
+		}
+	}
+	for day, name := range c.recurringHolidays {
+		date := NewDate(year, day.month, day.day)
+		if _, ok := result[date]; !ok && date.IsValid() {
+			result[date] = name
+		}
+	}
+	return result
+}
+
+// HolidayName returns the name of the holiday on t's date, if any
+func (c *Calendar) HolidayName(t time.Time) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.holidayName(FromTime(t))
+}
+
+// holidayName looks up a holiday. Callers must hold c.mu.
+func (c *Calendar) holidayName(date Date) (string, bool) {
+	if name, ok := c.holidays[date]; ok {
+		return name, true
+	}
+	name, ok := c.recurringHolidays[recurringDay{month: date.Month, day: date.Day}]
+	return name, ok
+}
+
+// IsHoliday checks if a time falls on a holiday
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	_, ok := c.HolidayName(t)
+	return ok
+}
+
+// IsWeekend checks if a time falls on one of the calendar's weekend days
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.weekend[t.Weekday()]
+}
+
+// IsBusinessDay checks if a time falls on neither a weekend nor a holiday
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isBusinessDay(t)
+}
+
+// isBusinessDay checks a business day. Callers must hold c.mu.
+func (c *Calendar) isBusinessDay(t time.Time) bool {
+	if c.weekend[t.Weekday()] {
+		return false
+	}
+	_, holiday := c.holidayName(FromTime(t))
+	return !holiday
+}
+
+// hasBusinessDays reports whether any weekday is a working day, which guards
+// the searches below against looping forever. Callers must hold c.mu.
+func (c *Calendar) hasBusinessDays() bool {
+	return len(c.weekend) < 7
+}
+
+// NextBusinessDay returns the first business day after t, keeping t's time of day
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, 1)
+}
+
+// PreviousBusinessDay returns the last business day before t, keeping t's time of day
+func (c *Calendar) PreviousBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, -1)
+}
+
+// AddBusinessDays moves t forward (or backward, for negative n) by n business
+// days, keeping t's time of day. With n == 0, t is returned unchanged.
+// If the calendar has no working weekdays, t is returned unchanged.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.hasBusinessDays() {
+		return t
+	}
+
+	step := 1
+	if n < 0 {
+		step = -1
+		n = -n
+	}
+
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.isBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// BusinessDaysBetween counts the business days from start (inclusive) to end
+// (exclusive). The result is negative when end is before start.
+func (c *Calendar) BusinessDaysBetween(start, end time.Time) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	sign := 1
+	if end.Before(start) {
+		start, end = end, start
+		sign = -1
+	}
+
+	from := FromTime(start).ToTime()
+	to := FromTime(end).ToTime()
+
+	count := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		if c.isBusinessDay(d) {
+			count++
+		}
+	}
+	return sign * count
+}
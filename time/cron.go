@@ -0,0 +1,505 @@
+package time
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/arbenlabs/stoner/logger"
+)
+
+// **************************************************
+// Cron
+// Cron runs jobs on cron schedules with second-level precision.
+// Every run executes in its own goroutine with panic recovery,
+// an optional timeout, and a per-job overlap policy.
+// **************************************************
+
+// ErrJobTimeout is reported when a job run exceeds its timeout
+var ErrJobTimeout = errors.New("job timed out")
+
+// JobFunc is a job function that can observe cancellation and report errors
+type JobFunc func(ctx context.Context) error
+
+// OverlapPolicy controls what happens when a job is due while a previous
+// run of the same job is still in progress
+type OverlapPolicy int
+
+const (
+	// OverlapSkip drops the new run (default)
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue runs the new run as soon as the previous one finishes
+	OverlapQueue
+	// OverlapAllow starts the new run concurrently
+	OverlapAllow
+)
+
+// String returns the name of the overlap policy
+func (p OverlapPolicy) String() string {
+	switch p {
+	case OverlapSkip:
+		return "skip"
+	case OverlapQueue:
+		return "queue"
+	case OverlapAllow:
+		return "allow"
+	default:
+		return fmt.Sprintf("OverlapPolicy(%d)", int(p))
+	}
+}
+
+// JobOptions configures how a job is executed
+type JobOptions struct {
+	Overlap OverlapPolicy
+	// Timeout cancels the job's context after the given duration; zero means no timeout
+	Timeout time.Duration
+	// TimeZone evaluates the schedule in the given timezone instead of server-local time
+	TimeZone *TimeZone
+}
+
+// Cron represents a cron scheduler
+type Cron struct {
+	Jobs []Job
+
+	mu           sync.Mutex
+	running      bool
+	stop         chan struct{}
+	wake         chan struct{}
+	done         chan struct{}
+	state        map[string]*jobState
+	inFlight     sync.WaitGroup
+	errorHandler func(jobID string, err error)
+	logger       *logger.Logger
+	store        JobStore
+	owner        string
+}
+
+// Job represents a scheduled job
+type Job struct {
+	ID       string
+	Schedule string
+	Function func()
+	Overlap  OverlapPolicy
+	Timeout  time.Duration
+	Location *time.Location
+	LastRun  time.Time
+	NextRun  time.Time
+
+	schedule Schedule
+	task     JobFunc
+}
+
+// zonedSchedule evaluates a schedule in a fixed location
+type zonedSchedule struct {
+	schedule Schedule
+	location *time.Location
+}
+
+// Next returns the next activation of the wrapped schedule in the location
+func (z *zonedSchedule) Next(t time.Time) time.Time {
+	return z.schedule.Next(t.In(z.location))
+}
+
+// String returns the wrapped schedule with its location
+func (z *zonedSchedule) String() string {
+	return fmt.Sprintf("%v (%s)", z.schedule, z.location)
+}
+
+// jobState tracks in-progress and queued runs of a job
+type jobState struct {
+	running int
+	queued  int
+}
+
+// NewCron creates a new cron scheduler
+func NewCron() *Cron {
+	return &Cron{
+		Jobs:  make([]Job, 0),
+		wake:  make(chan struct{}, 1),
+		state: make(map[string]*jobState),
+	}
+}
+
+// SetErrorHandler sets a callback invoked whenever a job returns an error,
+// panics, or times out
+func (c *Cron) SetErrorHandler(handler func(jobID string, err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errorHandler = handler
+}
+
+// SetLogger sets a logger used to report job errors when no error handler is set
+func (c *Cron) SetLogger(l *logger.Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logger = l
+}
+
+// AddJob adds a job to the cron scheduler with default options. The schedule
+// is a cron expression accepted by ParseCron.
+func (c *Cron) AddJob(id, schedule string, fn func()) error {
+	if fn == nil {
+		return fmt.Errorf("job %s: function cannot be nil", id)
+	}
+
+	parsed, err := ParseCron(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	return c.addJob(id, parsed, fn, func(context.Context) error {
+		fn()
+		return nil
+	}, JobOptions{})
+}
+
+// AddJobWithOptions adds a context-aware job with the given execution options
+func (c *Cron) AddJobWithOptions(id, schedule string, fn JobFunc, opts JobOptions) error {
+	parsed, err := ParseCron(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	return c.AddScheduledJob(id, parsed, fn, opts)
+}
+
+// AddScheduledJob adds a context-aware job with an already parsed or custom schedule
+func (c *Cron) AddScheduledJob(id string, schedule Schedule, fn JobFunc, opts JobOptions) error {
+	return c.addJob(id, schedule, nil, fn, opts)
+}
+
+// addJob validates and registers a job
+func (c *Cron) addJob(id string, schedule Schedule, fn func(), task JobFunc, opts JobOptions) error {
+	if id == "" {
+		return fmt.Errorf("job id cannot be empty")
+	}
+	if schedule == nil {
+		return fmt.Errorf("job %s: schedule cannot be nil", id)
+	}
+	if task == nil {
+		return fmt.Errorf("job %s: function cannot be nil", id)
+	}
+	if opts.Timeout < 0 {
+		return fmt.Errorf("job %s: timeout cannot be negative", id)
+	}
+
+	var loc *time.Location
+	if opts.TimeZone != nil {
+		loc = opts.TimeZone.Location
+		schedule = &zonedSchedule{schedule: schedule, location: loc}
+	} else if cronSchedule, ok := schedule.(*CronSchedule); ok {
+		loc = cronSchedule.Location
+	}
+
+	c.mu.Lock()
+	for _, job := range c.Jobs {
+		if job.ID == id {
+			c.mu.Unlock()
+			return fmt.Errorf("job %s already exists", id)
+		}
+	}
+
+	job := Job{
+		ID:       id,
+		Schedule: fmt.Sprint(schedule),
+		Function: fn,
+		Overlap:  opts.Overlap,
+		Timeout:  opts.Timeout,
+		Location: loc,
+		NextRun:  schedule.Next(time.Now()),
+		schedule: schedule,
+		task:     task,
+	}
+	c.Jobs = append(c.Jobs, job)
+	if c.state == nil {
+		c.state = make(map[string]*jobState)
+	}
+	c.state[id] = &jobState{}
+	store := c.store
+	c.notify()
+	c.mu.Unlock()
+
+	if store != nil {
+		if err := c.persistJob(store, job); err != nil {
+			c.RemoveJob(id)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveJob removes a job from the cron scheduler. Runs already in
+// progress finish, but queued runs are dropped.
+func (c *Cron) RemoveJob(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, job := range c.Jobs {
+		if job.ID == id {
+			c.Jobs = append(c.Jobs[:i], c.Jobs[i+1:]...)
+			if state, ok := c.state[id]; ok {
+				state.queued = 0
+			}
+			delete(c.state, id)
+			c.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// Entry returns a snapshot of a job by ID
+func (c *Cron) Entry(id string) (Job, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, job := range c.Jobs {
+		if job.ID == id {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// IsRunning reports whether a run of the job is currently in progress
+func (c *Cron) IsRunning(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.state[id]
+	return ok && state.running > 0
+}
+
+// Start starts the cron scheduler
+func (c *Cron) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return
+	}
+	if c.wake == nil {
+		c.wake = make(chan struct{}, 1)
+	}
+
+	c.running = true
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+
+	go c.run(c.stop, c.done)
+}
+
+// Stop stops the cron scheduler and waits for the scheduling loop to exit.
+// Jobs that are already running are not interrupted; use Wait to wait for them.
+func (c *Cron) Stop() {
+	c.mu.Lock()
+	if !c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.running = false
+	close(c.stop)
+	done := c.done
+	c.mu.Unlock()
+
+	<-done
+}
+
+// Wait blocks until all in-progress and queued job runs have finished
+func (c *Cron) Wait() {
+	c.inFlight.Wait()
+}
+
+// run sleeps until the earliest next run, then dispatches every due job
+func (c *Cron) run(stop, done chan struct{}) {
+	defer close(done)
+
+	for {
+		c.mu.Lock()
+		next := c.nextWakeup()
+		c.mu.Unlock()
+
+		// With nothing scheduled, sleep until a job is added or the cron is stopped
+		wait := 24 * time.Hour
+		if !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(wait)
+
+		select {
+		case now := <-timer.C:
+			c.dispatchDue(now)
+		case <-c.wake:
+			timer.Stop()
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// dispatchDue starts every job whose next run is at or before now. When a
+// store is set, each run must be claimed in the store before it starts.
+func (c *Cron) dispatchDue(now time.Time) {
+	type dueRun struct {
+		job         Job
+		scheduledAt time.Time
+	}
+
+	c.mu.Lock()
+	store, owner := c.store, c.owner
+	var due []dueRun
+	for i := range c.Jobs {
+		job := &c.Jobs[i]
+		if job.NextRun.IsZero() || job.NextRun.After(now) {
+			continue
+		}
+		scheduledAt := job.NextRun
+		job.LastRun = now
+		job.NextRun = job.schedule.Next(now)
+		due = append(due, dueRun{job: *job, scheduledAt: scheduledAt})
+	}
+	c.mu.Unlock()
+
+	for _, run := range due {
+		if store != nil && !c.claimRun(store, owner, run.job, run.scheduledAt) {
+			continue
+		}
+		c.startRun(run.job)
+	}
+}
+
+// startRun starts a run of job, applying its overlap policy
+func (c *Cron) startRun(job Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.state[job.ID]
+	if !ok {
+		return // removed since it became due
+	}
+
+	if state.running > 0 {
+		switch job.Overlap {
+		case OverlapSkip:
+			return
+		case OverlapQueue:
+			state.queued++
+			return
+		}
+	}
+
+	state.running++
+	c.inFlight.Add(1)
+	go c.execute(job, state)
+}
+
+// execute runs a job, then any runs queued behind it
+func (c *Cron) execute(job Job, state *jobState) {
+	defer c.inFlight.Done()
+
+	for {
+		err := c.runOnce(job)
+		if err != nil {
+			c.reportError(job.ID, err)
+		}
+		c.completeRun(job, err)
+
+		c.mu.Lock()
+		if state.queued > 0 {
+			state.queued--
+			c.mu.Unlock()
+			continue
+		}
+		state.running--
+		c.mu.Unlock()
+		return
+	}
+}
+
+// runOnce runs a single invocation of a job, recovering panics and
+// enforcing the job timeout
+func (c *Cron) runOnce(job Job) (err error) {
+	ctx := context.Background()
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			stack := make([]byte, 4096)
+			length := runtime.Stack(stack, false)
+			err = fmt.Errorf("job %s panicked: %v\n%s", job.ID, r, stack[:length])
+		}
+	}()
+
+	err = job.task(ctx)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %s exceeded %s", ErrJobTimeout, job.ID, job.Timeout)
+		}
+		return fmt.Errorf("%w: %s exceeded %s: %w", ErrJobTimeout, job.ID, job.Timeout, err)
+	}
+
+	return err
+}
+
+// completeRun records the outcome of a run in the store, if one is set
+func (c *Cron) completeRun(job Job, runErr error) {
+	c.mu.Lock()
+	store, owner := c.store, c.owner
+	c.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	if err := store.CompleteRun(context.Background(), job.ID, owner, time.Now(), runErr); err != nil {
+		c.reportError(job.ID, fmt.Errorf("failed to complete run: %w", err))
+	}
+}
+
+// reportError forwards a job error to the error handler, or the logger
+func (c *Cron) reportError(jobID string, err error) {
+	c.mu.Lock()
+	handler := c.errorHandler
+	l := c.logger
+	c.mu.Unlock()
+
+	if handler != nil {
+		handler(jobID, err)
+		return
+	}
+	if l != nil {
+		l.Error("Cron job failed", "job_id", jobID, "error", err.Error())
+	}
+}
+
+// nextWakeup returns the earliest next run across all jobs. Callers must hold c.mu.
+func (c *Cron) nextWakeup() time.Time {
+	var next time.Time
+	for _, job := range c.Jobs {
+		if job.NextRun.IsZero() {
+			continue
+		}
+		if next.IsZero() || job.NextRun.Before(next) {
+			next = job.NextRun
+		}
+	}
+	return next
+}
+
+// notify wakes the scheduling loop so it picks up job changes
+func (c *Cron) notify() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
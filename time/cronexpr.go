@@ -0,0 +1,415 @@
+package time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// **************************************************
+// Cron Expressions
+// Cron expressions describe recurring schedules using the standard
+// 5-field (minute hour day-of-month month day-of-week) or 6-field
+// (second minute hour day-of-month month day-of-week) syntax.
+// **************************************************
+
+// Schedule describes when a job runs
+type Schedule interface {
+	// Next returns the first activation time strictly after t,
+	// or the zero time if the schedule never fires again
+	Next(t time.Time) time.Time
+}
+
+// CronSchedule is a parsed cron expression. Each field is a bit set
+// where bit n is set when value n matches.
+type CronSchedule struct {
+	Expression string
+	// Location is the timezone the expression is evaluated in; when nil,
+	// the location of the time passed to Next is used
+	Location *time.Location
+
+	second     uint64
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// A restricted day-of-month and day-of-week are OR-ed together,
+	// as in standard cron; if either is "*" they are AND-ed
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+
+	// Schedules with a wildcard hour field keep their normal cadence across
+	// DST transitions; fixed-hour schedules are adjusted (see Next)
+	hourStar bool
+}
+
+// cronField describes the bounds and value names of one cron field
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	secondField     = cronField{name: "second", min: 0, max: 59}
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day-of-month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day-of-week accepts 0-7 where both 0 and 7 are Sunday
+	dayOfWeekField = cronField{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronMacros maps the supported @ macros to their 6-field equivalents
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// ParseCron parses a cron expression. It accepts 5 fields (minute precision),
+// 6 fields (leading seconds field), or one of the macros @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly. Fields support "*", "?",
+// lists ("1,15"), ranges ("1-5"), steps ("*/15", "10-40/10") and
+// case-insensitive month and day-of-week names ("JAN", "mon-fri").
+// An optional "CRON_TZ=<zone>" or "TZ=<zone>" prefix sets the schedule's
+// location, e.g. "CRON_TZ=America/New_York 0 2 * * *".
+func ParseCron(expression string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expression)
+	if spec == "" {
+		return nil, fmt.Errorf("empty cron expression")
+	}
+
+	var loc *time.Location
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		prefix, rest, _ := strings.Cut(spec, " ")
+		_, zone, _ := strings.Cut(prefix, "=")
+
+		var err error
+		loc, err = time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load timezone %s: %w", zone, err)
+		}
+		spec = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		macro, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported cron macro: %s", spec)
+		}
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields, got %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{Expression: expression, Location: loc}
+
+	var err error
+	if schedule.second, err = parseCronField(fields[0], secondField); err != nil {
+		return nil, err
+	}
+	if schedule.minute, err = parseCronField(fields[1], minuteField); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseCronField(fields[2], hourField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = parseCronField(fields[3], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseCronField(fields[4], monthField); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = parseCronField(fields[5], dayOfWeekField); err != nil {
+		return nil, err
+	}
+
+	// Fold Sunday-as-7 into Sunday-as-0
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek = (schedule.dayOfWeek &^ (1 << 7)) | 1
+	}
+
+	schedule.dayOfMonthStar = isCronWildcard(fields[3])
+	schedule.dayOfWeekStar = isCronWildcard(fields[5])
+	schedule.hourStar = strings.HasPrefix(fields[2], "*")
+
+	return schedule, nil
+}
+
+// MustParseCron parses a cron expression or panics
+func MustParseCron(expression string) *CronSchedule {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+// isCronWildcard reports whether a field matches every value
+func isCronWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseCronField parses a comma separated list of cron terms into a bit set
+func parseCronField(field string, f cronField) (uint64, error) {
+	var set uint64
+	for _, term := range strings.Split(field, ",") {
+		bitsForTerm, err := parseCronTerm(term, f)
+		if err != nil {
+			return 0, err
+		}
+		set |= bitsForTerm
+	}
+	return set, nil
+}
+
+// parseCronTerm parses a single term: "*", "?", "n", "a-b", with an optional "/step"
+func parseCronTerm(term string, f cronField) (uint64, error) {
+	if term == "" {
+		return 0, fmt.Errorf("empty %s term", f.name)
+	}
+
+	rangePart, stepPart, hasStep := strings.Cut(term, "/")
+
+	var start, end int
+	switch {
+	case isCronWildcard(rangePart):
+		start, end = f.min, f.max
+		if f.name == dayOfWeekField.name {
+			end = 6 // "*" covers Sunday once, as 0
+		}
+	case strings.Contains(rangePart, "-"):
+		lo, hi, _ := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = parseCronValue(lo, f); err != nil {
+			return 0, err
+		}
+		if end, err = parseCronValue(hi, f); err != nil {
+			return 0, err
+		}
+	default:
+		value, err := parseCronValue(rangePart, f)
+		if err != nil {
+			return 0, err
+		}
+		start, end = value, value
+		// "n/step" means from n to the end of the field
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid %s range %q: start is after end", f.name, term)
+	}
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid %s step %q", f.name, stepPart)
+		}
+	}
+
+	var set uint64
+	for v := start; v <= end; v += step {
+		set |= 1 << uint(v)
+	}
+	return set, nil
+}
+
+// parseCronValue parses a numeric or named field value and checks its bounds
+func parseCronValue(value string, f cronField) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", f.name, value)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s value %d out of range [%d, %d]", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// String returns the original cron expression
+func (s *CronSchedule) String() string {
+	return s.Expression
+}
+
+// Next returns the first activation time strictly after t, or the zero time
+// if no activation exists within the next five years. The expression is
+// evaluated in s.Location when set, otherwise in t's location.
+//
+// Schedules with a fixed hour follow wall-clock time across DST transitions:
+// a run whose local time falls in the hour skipped when clocks spring forward
+// happens at the first instant after the gap, and a run whose local time is
+// repeated when clocks fall back happens only once, at the first occurrence.
+// Schedules with a wildcard hour ("*" or "*/n") keep their normal cadence.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	for {
+		next := s.next(t)
+		if next.IsZero() || s.hourStar || !isRepeatedWallClock(next) {
+			return next
+		}
+		t = next
+	}
+}
+
+// next computes the next activation time, handling the spring-forward gap
+func (s *CronSchedule) next(t time.Time) time.Time {
+	if s.Location != nil {
+		t = t.In(s.Location)
+	}
+	loc := t.Location()
+
+	// Start at the next whole second
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
+
+	// Once a field is advanced, all lower fields are reset to their minimum
+	adjusted := false
+	yearLimit := t.Year() + 5
+
+search:
+	for t.Year() <= yearLimit {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			if !adjusted {
+				adjusted = true
+				t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+			}
+			t = t.AddDate(0, 1, 0)
+			if t.Month() == time.January {
+				continue search
+			}
+		}
+
+		for !s.dayMatches(t) {
+			if !adjusted {
+				adjusted = true
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+			}
+			t = t.AddDate(0, 0, 1)
+
+			// In zones where DST starts at midnight, local midnight may not
+			// exist; move back to the start of the day
+			if t.Hour() != 0 {
+				if t.Hour() > 12 {
+					t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+				} else {
+					t = t.Add(-time.Duration(t.Hour()) * time.Hour)
+				}
+			}
+
+			if t.Day() == 1 {
+				continue search
+			}
+		}
+
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			if !adjusted {
+				adjusted = true
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+			}
+			previousHour := t.Hour()
+			t = t.Add(time.Hour)
+			if t.Hour() == 0 {
+				continue search
+			}
+
+			// Clocks sprang forward over an hour the schedule runs in;
+			// run at the first instant after the gap
+			if !s.hourStar && s.skippedHourMatches(previousHour, t.Hour()) {
+				return t
+			}
+		}
+
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			if !adjusted {
+				adjusted = true
+				t = t.Truncate(time.Minute)
+			}
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue search
+			}
+		}
+
+		for s.second&(1<<uint(t.Second())) == 0 {
+			if !adjusted {
+				adjusted = true
+				t = t.Truncate(time.Second)
+			}
+			t = t.Add(time.Second)
+			if t.Second() == 0 {
+				continue search
+			}
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// skippedHourMatches reports whether any hour strictly between from and to
+// matches the hour field
+func (s *CronSchedule) skippedHourMatches(from, to int) bool {
+	for h := from + 1; h < to; h++ {
+		if s.hour&(1<<uint(h)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isRepeatedWallClock reports whether the wall-clock time of t already occurred
+// earlier the same day, as happens during the hour repeated when clocks fall back
+func isRepeatedWallClock(t time.Time) bool {
+	_, offset := t.Zone()
+	_, earlierOffset := t.Add(-3 * time.Hour).Zone()
+	if earlierOffset <= offset {
+		return false
+	}
+
+	previous := t.Add(-time.Duration(earlierOffset-offset) * time.Second)
+	_, previousOffset := previous.Zone()
+	return previousOffset != offset &&
+		previous.Hour() == t.Hour() &&
+		previous.Minute() == t.Minute() &&
+		previous.Second() == t.Second()
+}
+
+// dayMatches reports whether t matches the day-of-month and day-of-week fields
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
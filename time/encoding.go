@@ -0,0 +1,299 @@
+package time
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// **************************************************
+// Encoding
+// JSON and SQL support for Date and Duration so they can be used
+// directly in gq models and API payloads.
+// **************************************************
+
+// dateLayout is the ISO-8601 calendar date layout used for Date
+const dateLayout = "2006-01-02"
+
+// IsZero checks if the date is the zero value
+func (d Date) IsZero() bool {
+	return d.Year == 0 && d.Month == 0 && d.Day == 0
+}
+
+// MarshalJSON encodes the date as "YYYY-MM-DD", or null for the zero date
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a date from "YYYY-MM-DD", an RFC3339 timestamp, or null
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("date must be a string: %w", err)
+	}
+
+	parsed, err := parseDateString(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores the date in a DATE column, or NULL for the zero date
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.ToTime(), nil
+}
+
+// Scan reads a date from a DATE, TIMESTAMP, or text column
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = FromTime(v)
+		return nil
+	case string:
+		parsed, err := parseDateString(v)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	case []byte:
+		parsed, err := parseDateString(string(v))
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	default:
+		return fmt.Errorf("invalid type for Date: %T", value)
+	}
+}
+
+// GormDataType tells GORM migrations to use a DATE column
+func (Date) GormDataType() string {
+	return "date"
+}
+
+// parseDateString parses "YYYY-MM-DD" or a full timestamp into a Date
+func parseDateString(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return FromTime(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return FromTime(t), nil
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+		return FromTime(t), nil
+	}
+	return Date{}, fmt.Errorf("invalid date: %q", s)
+}
+
+// MarshalJSON encodes the duration as an ISO-8601 duration string, e.g. "PT1H30M"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.ISO8601())
+}
+
+// UnmarshalJSON decodes a duration from an ISO-8601 string ("PT1H30M"), a
+// duration string accepted by ParseDuration ("1h30m", "2d"), or a number of seconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Duration{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := parseDurationString(s)
+		if err != nil {
+			return err
+		}
+		d.Duration = parsed
+		return nil
+	}
+
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("duration must be a string or number of seconds: %w", err)
+	}
+	d.Duration = time.Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// Value stores the duration as a whole number of seconds. Sub-second
+// precision is truncated.
+func (d Duration) Value() (driver.Value, error) {
+	return int64(d.Duration / time.Second), nil
+}
+
+// Scan reads a duration stored as seconds, or as a duration string
+func (d *Duration) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Duration{}
+		return nil
+	case int64:
+		d.Duration = time.Duration(v) * time.Second
+		return nil
+	case float64:
+		d.Duration = time.Duration(v * float64(time.Second))
+		return nil
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	default:
+		return fmt.Errorf("invalid type for Duration: %T", value)
+	}
+}
+
+// GormDataType tells GORM migrations to use an integer column holding seconds
+func (Duration) GormDataType() string {
+	return "bigint"
+}
+
+// scanString reads a duration from a numeric or duration string
+func (d *Duration) scanString(s string) error {
+	if seconds, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		d.Duration = time.Duration(seconds * float64(time.Second))
+		return nil
+	}
+
+	parsed, err := parseDurationString(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// parseDurationString parses ISO-8601 or ParseDuration syntax
+func parseDurationString(s string) (time.Duration, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "-")
+	if strings.HasPrefix(trimmed, "P") || strings.HasPrefix(trimmed, "p") {
+		return ParseISO8601Duration(s)
+	}
+	return ParseDuration(s)
+}
+
+// ISO8601 returns the duration as an ISO-8601 duration, using days as the
+// largest unit since months and years have no fixed length, e.g. "P1DT2H"
+func (d Duration) ISO8601() string {
+	remaining := d.Duration
+	if remaining == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	if remaining < 0 {
+		b.WriteByte('-')
+		remaining = -remaining
+	}
+	b.WriteByte('P')
+
+	if days := remaining / Day; days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		remaining -= days * Day
+	}
+	if remaining == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	if hours := remaining / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+		remaining -= hours * time.Hour
+	}
+	if minutes := remaining / time.Minute; minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+		remaining -= minutes * time.Minute
+	}
+	if remaining > 0 {
+		seconds := strconv.FormatFloat(remaining.Seconds(), 'f', -1, 64)
+		fmt.Fprintf(&b, "%sS", seconds)
+	}
+	return b.String()
+}
+
+// ParseISO8601Duration parses an ISO-8601 duration such as "P3DT4H30M" or
+// "PT0.5S". Years and months are approximated as 365 and 30 days.
+func ParseISO8601Duration(s string) (time.Duration, error) {
+	input := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	if !strings.HasPrefix(s, "P") || len(s) < 2 {
+		return 0, fmt.Errorf("invalid ISO-8601 duration %q", input)
+	}
+	s = s[1:]
+
+	dateUnits := map[byte]time.Duration{'Y': Year, 'M': Month, 'W': Week, 'D': Day}
+	timeUnits := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+
+	var total float64
+	inTime := false
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime || len(s) == 1 {
+				return 0, fmt.Errorf("invalid ISO-8601 duration %q", input)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(s) && (s[i] == '.' || s[i] == ',' || (s[i] >= '0' && s[i] <= '9')) {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q", input)
+		}
+
+		value, err := strconv.ParseFloat(strings.ReplaceAll(s[:i], ",", "."), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q: %w", input, err)
+		}
+
+		units := dateUnits
+		if inTime {
+			units = timeUnits
+		}
+		unit, ok := units[s[i]]
+		if !ok {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q: unexpected %q", input, s[i])
+		}
+
+		total += value * float64(unit)
+		s = s[i+1:]
+	}
+
+	if negative {
+		total = -total
+	}
+	return time.Duration(total), nil
+}
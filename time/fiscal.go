@@ -0,0 +1,65 @@
+package time
+
+import (
+	"fmt"
+	"time"
+)
+
+// **************************************************
+// Fiscal Calendar
+// FiscalCalendar computes fiscal years and quarters for
+// organizations whose fiscal year does not start in January.
+// **************************************************
+
+// FiscalCalendar represents a fiscal calendar starting on the first day of StartMonth.
+// Fiscal years are named after the calendar year in which they end, so with an
+// October start, October 2024 through September 2025 is fiscal year 2025.
+type FiscalCalendar struct {
+	StartMonth time.Month
+}
+
+// NewFiscalCalendar creates a new fiscal calendar
+func NewFiscalCalendar(startMonth time.Month) (*FiscalCalendar, error) {
+	if startMonth < time.January || startMonth > time.December {
+		return nil, fmt.Errorf("invalid fiscal year start month: %d", startMonth)
+	}
+	return &FiscalCalendar{StartMonth: startMonth}, nil
+}
+
+// StartOfFiscalYear returns the start of the fiscal year containing t
+func (fc *FiscalCalendar) StartOfFiscalYear(t time.Time) time.Time {
+	year := t.Year()
+	if t.Month() < fc.StartMonth {
+		year--
+	}
+	return time.Date(year, fc.StartMonth, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfFiscalYear returns the end of the fiscal year containing t
+func (fc *FiscalCalendar) EndOfFiscalYear(t time.Time) time.Time {
+	return fc.StartOfFiscalYear(t).AddDate(1, 0, 0).Add(-time.Nanosecond)
+}
+
+// FiscalYear returns the fiscal year containing t
+func (fc *FiscalCalendar) FiscalYear(t time.Time) int {
+	if fc.StartMonth == time.January {
+		return t.Year()
+	}
+	return fc.StartOfFiscalYear(t).Year() + 1
+}
+
+// FiscalQuarter returns the fiscal quarter containing t, from 1 to 4
+func (fc *FiscalCalendar) FiscalQuarter(t time.Time) int {
+	monthsIn := (int(t.Month()) - int(fc.StartMonth) + 12) % 12
+	return monthsIn/3 + 1
+}
+
+// StartOfFiscalQuarter returns the start of the fiscal quarter containing t
+func (fc *FiscalCalendar) StartOfFiscalQuarter(t time.Time) time.Time {
+	return fc.StartOfFiscalYear(t).AddDate(0, (fc.FiscalQuarter(t)-1)*3, 0)
+}
+
+// EndOfFiscalQuarter returns the end of the fiscal quarter containing t
+func (fc *FiscalCalendar) EndOfFiscalQuarter(t time.Time) time.Time {
+	return fc.StartOfFiscalQuarter(t).AddDate(0, 3, 0).Add(-time.Nanosecond)
+}
@@ -0,0 +1,215 @@
+package time
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// **************************************************
+// Duration Parsing and Humanization
+// Extended duration units and human-friendly formatting of
+// durations and relative times, past and future.
+// **************************************************
+
+// Calendar-free approximations of the extended duration units
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+// durationUnits maps every unit accepted by ParseDuration to its length
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"μs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  Day,
+	"w":  Week,
+	"mo": Month,
+	"y":  Year,
+}
+
+// ParseDuration parses a duration like time.ParseDuration, additionally
+// accepting d (day), w (week), mo (30-day month) and y (365-day year)
+// units, e.g. "3d12h", "2w", "1y6mo" or "-1.5d".
+func ParseDuration(s string) (time.Duration, error) {
+	input := s
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", input)
+	}
+
+	negative := false
+	if s[0] == '-' || s[0] == '+' {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", input)
+	}
+
+	var total float64
+	for s != "" {
+		// Number, possibly fractional
+		i := 0
+		for i < len(s) && (s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected number", input)
+		}
+		value, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", input, err)
+		}
+		s = s[i:]
+
+		// Unit: everything up to the next digit or dot
+		j := 0
+		for j < len(s) && s[j] != '.' && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+		unit, ok := durationUnits[s[:j]]
+		if !ok {
+			if j == 0 {
+				return 0, fmt.Errorf("invalid duration %q: missing unit", input)
+			}
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q", input, s[:j])
+		}
+		s = s[j:]
+
+		total += value * float64(unit)
+		if total > math.MaxInt64 {
+			return 0, fmt.Errorf("invalid duration %q: overflow", input)
+		}
+	}
+
+	if negative {
+		total = -total
+	}
+	return time.Duration(total), nil
+}
+
+// FromString creates a duration from a string, accepting the extended
+// units supported by ParseDuration
+func FromString(s string) (Duration, error) {
+	d, err := ParseDuration(s)
+	if err != nil {
+		return Duration{}, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	return Duration{Duration: d}, nil
+}
+
+// humanizeUnits are the units used by Humanize, largest first
+var humanizeUnits = []struct {
+	suffix string
+	length time.Duration
+}{
+	{"y", Year},
+	{"mo", Month},
+	{"w", Week},
+	{"d", Day},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// Humanize returns a compact form of the duration using its two most
+// significant units, e.g. "1h 23m", "3d 4h" or "45s"
+func (d Duration) Humanize() string {
+	remaining := d.Duration
+	sign := ""
+	if remaining < 0 {
+		sign = "-"
+		remaining = -remaining
+	}
+
+	if remaining < time.Second {
+		if remaining == 0 {
+			return "0s"
+		}
+		return sign + remaining.String()
+	}
+
+	parts := make([]string, 0, 2)
+	for _, unit := range humanizeUnits {
+		if remaining < unit.length {
+			if len(parts) > 0 {
+				break // only adjacent units, "1h 5s" reads poorly
+			}
+			continue
+		}
+		count := remaining / unit.length
+		remaining -= count * unit.length
+		parts = append(parts, fmt.Sprintf("%d%s", count, unit.suffix))
+		if len(parts) == 2 {
+			break
+		}
+	}
+
+	return sign + strings.Join(parts, " ")
+}
+
+// relativeUnits are the units used by Humanize on FormatTime, largest first
+var relativeUnits = []struct {
+	name   string
+	length time.Duration
+}{
+	{"year", Year},
+	{"month", Month},
+	{"week", Week},
+	{"day", Day},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+}
+
+// Humanize describes t relative to now, for past and future times,
+// e.g. "2 hours ago", "in 3 days" or "just now"
+func (ft *FormatTime) Humanize(t time.Time) string {
+	return ft.HumanizeFrom(t, time.Now())
+}
+
+// HumanizeFrom describes t relative to the given reference time
+func (ft *FormatTime) HumanizeFrom(t, now time.Time) string {
+	diff := t.Sub(now)
+	future := diff > 0
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if diff < time.Minute {
+		return "just now"
+	}
+
+	for _, unit := range relativeUnits {
+		if diff < unit.length {
+			continue
+		}
+
+		count := int(diff / unit.length)
+		label := fmt.Sprintf("%d %ss", count, unit.name)
+		if count == 1 {
+			label = fmt.Sprintf("1 %s", unit.name)
+		}
+
+		if future {
+			return "in " + label
+		}
+		return label + " ago"
+	}
+
+	return "just now"
+}
@@ -0,0 +1,220 @@
+package time
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Job Store
+// A JobStore persists cron schedules and run state so that several
+// replicas of a service can share one schedule. Before running a job,
+// each replica claims the run in the store; only the replica whose
+// claim succeeds executes it.
+// **************************************************
+
+// DefaultJobLease is how long a claimed run stays locked when the job has no
+// timeout. An instance that crashes mid-run releases its lock when the lease expires.
+const DefaultJobLease = 10 * time.Minute
+
+// JobRecord is the persisted state of a cron job
+type JobRecord struct {
+	ID          string
+	Schedule    string
+	LastRun     time.Time
+	NextRun     time.Time
+	LastError   string
+	LockedBy    string
+	LockedUntil time.Time
+}
+
+// JobStore persists cron jobs and arbitrates which instance runs each scheduled run
+type JobStore interface {
+	// SaveJob creates a job record or updates its schedule, preserving run state,
+	// and returns the stored record
+	SaveJob(ctx context.Context, record JobRecord) (JobRecord, error)
+
+	// ClaimRun atomically claims the run of jobID scheduled at scheduledAt for owner.
+	// The claim succeeds only if no other instance has claimed that run and, when
+	// lease is positive, the job is not locked by an unexpired lease. A successful
+	// claim advances the stored next run to nextRun.
+	ClaimRun(ctx context.Context, jobID, owner string, scheduledAt, nextRun time.Time, lease time.Duration) (bool, error)
+
+	// CompleteRun records the outcome of a claimed run and releases owner's lock
+	CompleteRun(ctx context.Context, jobID, owner string, finishedAt time.Time, runErr error) error
+}
+
+// SetStore makes the cron persist its jobs in store and claim every run
+// before executing it, so that only one instance with the same store runs
+// each job. The owner identifies this instance; when empty, the hostname
+// and process ID are used.
+func (c *Cron) SetStore(store JobStore, owner string) error {
+	if owner == "" {
+		owner = defaultJobOwner()
+	}
+
+	c.mu.Lock()
+	c.store = store
+	c.owner = owner
+	jobs := make([]Job, len(c.Jobs))
+	copy(jobs, c.Jobs)
+	c.mu.Unlock()
+
+	if store == nil {
+		return nil
+	}
+
+	for _, job := range jobs {
+		if err := c.persistJob(store, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistJob saves a job to the store and restores its last run
+func (c *Cron) persistJob(store JobStore, job Job) error {
+	record, err := store.SaveJob(context.Background(), JobRecord{
+		ID:       job.ID,
+		Schedule: job.Schedule,
+		NextRun:  job.NextRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist job %s: %w", job.ID, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.Jobs {
+		if c.Jobs[i].ID == job.ID && c.Jobs[i].LastRun.IsZero() {
+			c.Jobs[i].LastRun = record.LastRun
+		}
+	}
+	return nil
+}
+
+// claimRun claims a due run in the store
+func (c *Cron) claimRun(store JobStore, owner string, job Job, scheduledAt time.Time) bool {
+	// Concurrent runs across instances are only allowed for OverlapAllow jobs
+	var lease time.Duration
+	if job.Overlap != OverlapAllow {
+		lease = job.Timeout
+		if lease <= 0 {
+			lease = DefaultJobLease
+		}
+	}
+
+	claimed, err := store.ClaimRun(context.Background(), job.ID, owner, scheduledAt, job.NextRun, lease)
+	if err != nil {
+		c.reportError(job.ID, fmt.Errorf("failed to claim run: %w", err))
+		return false
+	}
+	return claimed
+}
+
+// defaultJobOwner identifies this process as hostname-pid
+func defaultJobOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// MemoryJobStore is an in-process JobStore, useful for tests and for
+// single-instance services that want the same code path as distributed ones
+type MemoryJobStore struct {
+	mu      sync.Mutex
+	records map[string]JobRecord
+}
+
+// NewMemoryJobStore creates a new in-memory job store
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		records: make(map[string]JobRecord),
+	}
+}
+
+// SaveJob creates or updates a job record
+func (s *MemoryJobStore) SaveJob(ctx context.Context, record JobRecord) (JobRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[record.ID]
+	if !ok {
+		s.records[record.ID] = record
+		return record, nil
+	}
+
+	if existing.Schedule != record.Schedule {
+		existing.Schedule = record.Schedule
+		existing.NextRun = record.NextRun
+	}
+	s.records[record.ID] = existing
+
+	return existing, nil
+}
+
+// ClaimRun claims a scheduled run for owner
+func (s *MemoryJobStore) ClaimRun(ctx context.Context, jobID, owner string, scheduledAt, nextRun time.Time, lease time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[jobID]
+	if !ok {
+		return false, fmt.Errorf("job %s not found", jobID)
+	}
+
+	now := time.Now()
+	if record.NextRun.After(scheduledAt) {
+		return false, nil
+	}
+	if lease > 0 && record.LockedUntil.After(now) {
+		return false, nil
+	}
+
+	record.LastRun = scheduledAt
+	record.NextRun = nextRun
+	if lease > 0 {
+		record.LockedBy = owner
+		record.LockedUntil = now.Add(lease)
+	}
+	s.records[jobID] = record
+
+	return true, nil
+}
+
+// CompleteRun records a run's outcome and releases owner's lock
+func (s *MemoryJobStore) CompleteRun(ctx context.Context, jobID, owner string, finishedAt time.Time, runErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[jobID]
+	if !ok {
+		return fmt.Errorf("job %s not found", jobID)
+	}
+
+	record.LastError = ""
+	if runErr != nil {
+		record.LastError = runErr.Error()
+	}
+	if record.LockedBy == owner {
+		record.LockedBy = ""
+		record.LockedUntil = time.Time{}
+	}
+	s.records[jobID] = record
+
+	return nil
+}
+
+// Get returns the stored record for a job
+func (s *MemoryJobStore) Get(jobID string) (JobRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[jobID]
+	return record, ok
+}
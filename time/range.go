@@ -0,0 +1,188 @@
+package time
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// **************************************************
+// Range
+// Range is a half-open time interval [Start, End) with
+// set operations for booking and reporting features.
+// **************************************************
+
+// Range represents the half-open time interval [Start, End)
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewRange creates a new range, rejecting ranges that end before they start
+func NewRange(start, end time.Time) (Range, error) {
+	if end.Before(start) {
+		return Range{}, fmt.Errorf("invalid range: end %v is before start %v", end, start)
+	}
+	return Range{Start: start, End: end}, nil
+}
+
+// RangeFrom creates a range starting at start and lasting d
+func RangeFrom(start time.Time, d time.Duration) Range {
+	if d < 0 {
+		return Range{Start: start.Add(d), End: start}
+	}
+	return Range{Start: start, End: start.Add(d)}
+}
+
+// Duration returns the length of the range
+func (r Range) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// IsEmpty checks if the range contains no instants
+func (r Range) IsEmpty() bool {
+	return !r.End.After(r.Start)
+}
+
+// Contains checks if t falls within the range
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// ContainsRange checks if other lies entirely within the range
+func (r Range) ContainsRange(other Range) bool {
+	return !other.Start.Before(r.Start) && !other.End.After(r.End)
+}
+
+// Overlaps checks if the two ranges share at least one instant
+func (r Range) Overlaps(other Range) bool {
+	return r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+// Adjacent checks if one range ends exactly where the other starts
+func (r Range) Adjacent(other Range) bool {
+	return r.End.Equal(other.Start) || other.End.Equal(r.Start)
+}
+
+// Intersection returns the overlap of the two ranges, if any
+func (r Range) Intersection(other Range) (Range, bool) {
+	if !r.Overlaps(other) {
+		return Range{}, false
+	}
+	return Range{
+		Start: latest(r.Start, other.Start),
+		End:   earliest(r.End, other.End),
+	}, true
+}
+
+// Union returns the range covering both ranges when they overlap or
+// are adjacent; disjoint ranges have no single-range union
+func (r Range) Union(other Range) (Range, bool) {
+	if !r.Overlaps(other) && !r.Adjacent(other) {
+		return Range{}, false
+	}
+	return Range{
+		Start: earliest(r.Start, other.Start),
+		End:   latest(r.End, other.End),
+	}, true
+}
+
+// Subtract returns the parts of the range not covered by other
+func (r Range) Subtract(other Range) []Range {
+	if !r.Overlaps(other) {
+		return []Range{r}
+	}
+
+	result := make([]Range, 0, 2)
+	if r.Start.Before(other.Start) {
+		result = append(result, Range{Start: r.Start, End: other.Start})
+	}
+	if other.End.Before(r.End) {
+		result = append(result, Range{Start: other.End, End: r.End})
+	}
+	return result
+}
+
+// Split divides the range into consecutive ranges of length d; the last
+// range is shorter when the duration does not divide evenly
+func (r Range) Split(d time.Duration) ([]Range, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("split duration must be positive")
+	}
+
+	result := make([]Range, 0)
+	for start := r.Start; start.Before(r.End); start = start.Add(d) {
+		result = append(result, Range{Start: start, End: earliest(start.Add(d), r.End)})
+	}
+	return result, nil
+}
+
+// Clamp returns t limited to the range's bounds
+func (r Range) Clamp(t time.Time) time.Time {
+	if t.Before(r.Start) {
+		return r.Start
+	}
+	if t.After(r.End) {
+		return r.End
+	}
+	return t
+}
+
+// ClampDuration shortens the range so it lasts at most max, keeping its start
+func (r Range) ClampDuration(max time.Duration) Range {
+	if max < 0 {
+		max = 0
+	}
+	if r.Duration() <= max {
+		return r
+	}
+	return Range{Start: r.Start, End: r.Start.Add(max)}
+}
+
+// String returns the string representation of the range
+func (r Range) String() string {
+	return fmt.Sprintf("[%s, %s)", r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
+}
+
+// MergeRanges returns the union of ranges as a sorted list of disjoint ranges,
+// merging ranges that overlap or are adjacent. Empty ranges are dropped.
+func MergeRanges(ranges []Range) []Range {
+	sorted := make([]Range, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	merged := make([]Range, 0, len(sorted))
+	for _, r := range sorted {
+		last := len(merged) - 1
+		if last >= 0 {
+			if union, ok := merged[last].Union(r); ok {
+				merged[last] = union
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// earliest returns the earlier of two times
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
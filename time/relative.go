@@ -0,0 +1,173 @@
+package time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// **************************************************
+// Relative Time Parsing
+// Relative parsing understands expressions such as "tomorrow",
+// "next monday", "in 3 hours" and "2 days ago".
+// **************************************************
+
+// weekdayNames maps full and abbreviated weekday names to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// FromStringInLocation parses time from a string using common formats,
+// interpreting times without an explicit offset in loc. Relative
+// expressions are resolved against the current time in loc.
+func (pt *ParseTime) FromStringInLocation(s string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		return time.Time{}, fmt.Errorf("location cannot be nil")
+	}
+
+	for _, format := range parseFormats {
+		if t, err := time.ParseInLocation(format, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, err := pt.Relative(s, time.Now().In(loc)); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse time: %s", s)
+}
+
+// Relative parses a relative time expression against now. Supported forms:
+//
+//	now, today, tomorrow, yesterday
+//	next monday, last friday, this sunday
+//	next week, last month, next year
+//	in 3 hours, in an hour, in 2 weeks
+//	2 days ago, a minute ago
+//
+// Day-based expressions ("tomorrow", "next monday") resolve to the start of
+// that day; unit-based expressions ("in 3 hours") keep the time of day.
+func (pt *ParseTime) Relative(s string, now time.Time) (time.Time, error) {
+	expr := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	calc := NewTimeCalculator()
+
+	switch expr {
+	case "":
+		return time.Time{}, fmt.Errorf("empty relative time expression")
+	case "now":
+		return now, nil
+	case "today":
+		return calc.StartOfDay(now), nil
+	case "tomorrow":
+		return calc.StartOfDay(now.AddDate(0, 0, 1)), nil
+	case "yesterday":
+		return calc.StartOfDay(now.AddDate(0, 0, -1)), nil
+	}
+
+	words := strings.Split(expr, " ")
+
+	// "next <weekday|unit>", "last <weekday|unit>", "this <weekday>"
+	if len(words) == 2 && (words[0] == "next" || words[0] == "last" || words[0] == "this") {
+		if weekday, ok := weekdayNames[words[1]]; ok {
+			return relativeWeekday(now, words[0], weekday), nil
+		}
+		if words[0] != "this" {
+			amount := 1
+			if words[0] == "last" {
+				amount = -1
+			}
+			return addRelativeUnit(now, amount, words[1])
+		}
+	}
+
+	// "in <n> <unit>"
+	if len(words) == 3 && words[0] == "in" {
+		amount, err := parseRelativeAmount(words[1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return addRelativeUnit(now, amount, words[2])
+	}
+
+	// "<n> <unit> ago"
+	if len(words) == 3 && words[2] == "ago" {
+		amount, err := parseRelativeAmount(words[0])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return addRelativeUnit(now, -amount, words[1])
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported relative time expression: %s", s)
+}
+
+// relativeWeekday resolves "next", "last" and "this" weekday expressions
+func relativeWeekday(now time.Time, direction string, weekday time.Weekday) time.Time {
+	start := NewTimeCalculator().StartOfDay(now)
+	diff := int(weekday) - int(now.Weekday())
+
+	switch direction {
+	case "next":
+		if diff <= 0 {
+			diff += 7
+		}
+	case "last":
+		if diff >= 0 {
+			diff -= 7
+		}
+	default: // "this": the given weekday within the current Monday-Sunday week
+		current := int(now.Weekday())
+		if current == 0 {
+			current = 7
+		}
+		target := int(weekday)
+		if target == 0 {
+			target = 7
+		}
+		diff = target - current
+	}
+
+	return start.AddDate(0, 0, diff)
+}
+
+// parseRelativeAmount parses a count such as "3", "a" or "an"
+func parseRelativeAmount(s string) (int, error) {
+	if s == "a" || s == "an" {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid relative amount: %s", s)
+	}
+	return n, nil
+}
+
+// addRelativeUnit adds amount units to t using calendar arithmetic for days and larger
+func addRelativeUnit(t time.Time, amount int, unit string) (time.Time, error) {
+	switch strings.TrimSuffix(unit, "s") {
+	case "second", "sec":
+		return t.Add(time.Duration(amount) * time.Second), nil
+	case "minute", "min":
+		return t.Add(time.Duration(amount) * time.Minute), nil
+	case "hour", "hr":
+		return t.Add(time.Duration(amount) * time.Hour), nil
+	case "day":
+		return t.AddDate(0, 0, amount), nil
+	case "week", "wk":
+		return t.AddDate(0, 0, 7*amount), nil
+	case "month":
+		return t.AddDate(0, amount, 0), nil
+	case "year", "yr":
+		return t.AddDate(amount, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported time unit: %s", unit)
+	}
+}
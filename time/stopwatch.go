@@ -0,0 +1,179 @@
+package time
+
+import (
+	"sync"
+	"time"
+
+	"github.com/arbenlabs/stoner/logger"
+)
+
+// **************************************************
+// Stopwatch
+// Stopwatch provides lightweight timing instrumentation with
+// laps and named sections, reported through the logger's
+// LogPerformance API.
+// **************************************************
+
+// Lap represents a recorded lap or named section
+type Lap struct {
+	Name     string
+	Duration time.Duration // length of the lap or section
+	Elapsed  time.Duration // stopwatch time when the lap ended
+}
+
+// Stopwatch represents a stopwatch that can be stopped, resumed and lapped
+type Stopwatch struct {
+	mu      sync.Mutex
+	started time.Time     // start of the current running period
+	elapsed time.Duration // time accumulated before the current running period
+	running bool
+	lastLap time.Duration
+	laps    []Lap
+	logger  *logger.Logger
+}
+
+// NewStopwatch creates a new stopwatch that has already started
+func NewStopwatch() *Stopwatch {
+	return &Stopwatch{
+		started: time.Now(),
+		running: true,
+	}
+}
+
+// SetLogger sets a logger that receives laps and sections via LogPerformance
+func (sw *Stopwatch) SetLogger(l *logger.Logger) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	sw.logger = l
+}
+
+// Start resumes a stopped stopwatch
+func (sw *Stopwatch) Start() {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if !sw.running {
+		sw.started = time.Now()
+		sw.running = true
+	}
+}
+
+// Stop pauses the stopwatch and returns the elapsed time
+func (sw *Stopwatch) Stop() time.Duration {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.running {
+		sw.elapsed += time.Since(sw.started)
+		sw.running = false
+	}
+	return sw.elapsed
+}
+
+// Reset clears the elapsed time and laps and restarts the stopwatch
+func (sw *Stopwatch) Reset() {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.started = time.Now()
+	sw.elapsed = 0
+	sw.running = true
+	sw.lastLap = 0
+	sw.laps = nil
+}
+
+// IsRunning checks if the stopwatch is running
+func (sw *Stopwatch) IsRunning() bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.running
+}
+
+// Elapsed returns the total running time
+func (sw *Stopwatch) Elapsed() time.Duration {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.elapsedLocked()
+}
+
+// elapsedLocked returns the total running time. Callers must hold sw.mu.
+func (sw *Stopwatch) elapsedLocked() time.Duration {
+	if sw.running {
+		return sw.elapsed + time.Since(sw.started)
+	}
+	return sw.elapsed
+}
+
+// Split returns the time since the previous lap without recording a new one
+func (sw *Stopwatch) Split() time.Duration {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.elapsedLocked() - sw.lastLap
+}
+
+// Lap records a named lap covering the time since the previous lap
+func (sw *Stopwatch) Lap(name string) Lap {
+	sw.mu.Lock()
+	elapsed := sw.elapsedLocked()
+	lap := Lap{Name: name, Duration: elapsed - sw.lastLap, Elapsed: elapsed}
+	sw.lastLap = elapsed
+	sw.laps = append(sw.laps, lap)
+	l := sw.logger
+	sw.mu.Unlock()
+
+	if l != nil {
+		l.LogPerformance(name, lap.Duration, nil)
+	}
+	return lap
+}
+
+// Laps returns the recorded laps and sections in order
+func (sw *Stopwatch) Laps() []Lap {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	laps := make([]Lap, len(sw.laps))
+	copy(laps, sw.laps)
+	return laps
+}
+
+// Measure runs fn as a named section and records it alongside the laps.
+// Sections do not move the lap marker, so they can nest within laps.
+func (sw *Stopwatch) Measure(name string, fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	duration := time.Since(start)
+
+	sw.mu.Lock()
+	sw.laps = append(sw.laps, Lap{Name: name, Duration: duration, Elapsed: sw.elapsedLocked()})
+	l := sw.logger
+	sw.mu.Unlock()
+
+	if l != nil {
+		l.LogPerformance(name, duration, nil)
+	}
+	return duration
+}
+
+// Measure runs fn and reports its duration under name to the default
+// logger's LogPerformance API, if a default logger has been created
+func Measure(name string, fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	duration := time.Since(start)
+
+	if l := defaultLogger(); l != nil {
+		l.LogPerformance(name, duration, nil)
+	}
+	return duration
+}
+
+// defaultLogger returns the default logger, or nil if none has been created
+func defaultLogger() (l *logger.Logger) {
+	defer func() {
+		if recover() != nil {
+			l = nil
+		}
+	}()
+	return logger.GetLogger()
+}
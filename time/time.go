@@ -2,8 +2,6 @@ package time
 
 import (
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -74,131 +72,138 @@ func (d Date) IsValid() bool {
 	return t.Year() == d.Year && int(t.Month()) == d.Month && t.Day() == d.Day
 }
 
-// Duration represents a duration utility
-type Duration struct {
-	Duration time.Duration
+// ParseDate parses a date in "YYYY-MM-DD" format
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("failed to parse date %q: %w", s, err)
+	}
+	return FromTime(t), nil
 }
 
-// NewDuration creates a new duration
-func NewDuration(d time.Duration) Duration {
-	return Duration{Duration: d}
+// Today returns the current date in the given location
+func Today(loc *time.Location) Date {
+	return FromTime(time.Now().In(loc))
 }
 
-// FromString creates a duration from a string
-func FromString(s string) (Duration, error) {
-	d, err := time.ParseDuration(s)
-	if err != nil {
-		return Duration{}, fmt.Errorf("failed to parse duration: %w", err)
-	}
-	return Duration{Duration: d}, nil
+// In returns midnight of the date in the given location
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, loc)
 }
 
-// Add adds another duration
-func (d Duration) Add(other Duration) Duration {
-	return Duration{Duration: d.Duration + other.Duration}
+// AddDays adds days to the date
+func (d Date) AddDays(days int) Date {
+	return FromTime(d.ToTime().AddDate(0, 0, days))
 }
 
-// Subtract subtracts another duration
-func (d Duration) Subtract(other Duration) Duration {
-	return Duration{Duration: d.Duration - other.Duration}
+// AddMonths adds months to the date. The day is clamped to the last day of
+// the resulting month, so Jan 31 plus one month is the last day of February.
+func (d Date) AddMonths(months int) Date {
+	first := time.Date(d.Year, time.Month(d.Month)+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	day := d.Day
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return Date{Year: first.Year(), Month: int(first.Month()), Day: day}
 }
 
-// Multiply multiplies the duration by a factor
-func (d Duration) Multiply(factor float64) Duration {
-	return Duration{Duration: time.Duration(float64(d.Duration) * factor)}
+// AddYears adds years to the date, clamping Feb 29 to Feb 28 in non-leap years
+func (d Date) AddYears(years int) Date {
+	return d.AddMonths(12 * years)
 }
 
-// String returns the string representation of the duration
-func (d Duration) String() string {
-	return d.Duration.String()
+// Sub returns the number of days from other to d
+func (d Date) Sub(other Date) int {
+	return int(d.ToTime().Sub(other.ToTime()) / Day)
 }
 
-// Cron represents a cron-like scheduler
-type Cron struct {
-	Jobs []Job
+// Before checks if the date is before another date
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
 }
 
-// Job represents a scheduled job
-type Job struct {
-	ID       string
-	Schedule string
-	Function func()
-	LastRun  time.Time
-	NextRun  time.Time
+// After checks if the date is after another date
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
 }
 
-// NewCron creates a new cron scheduler
-func NewCron() *Cron {
-	return &Cron{
-		Jobs: make([]Job, 0),
-	}
+// Equal checks if two dates are the same day
+func (d Date) Equal(other Date) bool {
+	return d.Compare(other) == 0
 }
 
-// AddJob adds a job to the cron scheduler
-func (c *Cron) AddJob(id, schedule string, fn func()) error {
-	job := Job{
-		ID:       id,
-		Schedule: schedule,
-		Function: fn,
+// Compare returns -1, 0, or 1 depending on whether d is before, equal to, or after other
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return compareInts(d.Year, other.Year)
+	case d.Month != other.Month:
+		return compareInts(d.Month, other.Month)
+	default:
+		return compareInts(d.Day, other.Day)
 	}
+}
 
-	// Parse schedule and calculate next run
-	nextRun, err := c.parseSchedule(schedule)
-	if err != nil {
-		return fmt.Errorf("failed to parse schedule: %w", err)
-	}
+// DayOfWeek returns the weekday of the date
+func (d Date) DayOfWeek() time.Weekday {
+	return d.ToTime().Weekday()
+}
 
-	job.NextRun = nextRun
-	c.Jobs = append(c.Jobs, job)
+// ISOWeek returns the ISO 8601 year and week number of the date
+func (d Date) ISOWeek() (year, week int) {
+	return d.ToTime().ISOWeek()
+}
 
-	return nil
+// DayOfYear returns the day of the year, from 1 to 366
+func (d Date) DayOfYear() int {
+	return d.ToTime().YearDay()
 }
 
-// Start starts the cron scheduler
-func (c *Cron) Start() {
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
+// daysIn returns the number of days in a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
 
-		for range ticker.C {
-			now := time.Now()
-			for i := range c.Jobs {
-				if c.Jobs[i].NextRun.Before(now) || c.Jobs[i].NextRun.Equal(now) {
-					c.Jobs[i].Function()
-					c.Jobs[i].LastRun = now
+// compareInts returns -1, 0, or 1
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
 
-					// Calculate next run
-					nextRun, err := c.parseSchedule(c.Jobs[i].Schedule)
-					if err == nil {
-						c.Jobs[i].NextRun = nextRun
-					}
-				}
-			}
-		}
-	}()
+// Duration represents a duration utility
+type Duration struct {
+	Duration time.Duration
 }
 
-// parseSchedule parses a cron-like schedule (simplified version)
-func (c *Cron) parseSchedule(schedule string) (time.Time, error) {
-	// This is a simplified version - in production you'd want a full cron parser
-	parts := strings.Fields(schedule)
-	if len(parts) != 5 {
-		return time.Time{}, fmt.Errorf("invalid schedule format")
-	}
+// NewDuration creates a new duration
+func NewDuration(d time.Duration) Duration {
+	return Duration{Duration: d}
+}
 
-	// For now, just support "every X minutes" format
-	if parts[0] == "*" && parts[1] == "*" && parts[2] == "*" && parts[3] == "*" {
-		if parts[4] == "*" {
-			return time.Now().Add(1 * time.Minute), nil
-		}
+// Add adds another duration
+func (d Duration) Add(other Duration) Duration {
+	return Duration{Duration: d.Duration + other.Duration}
+}
 
-		// Parse minutes
-		if minutes, err := strconv.Atoi(parts[4]); err == nil {
-			return time.Now().Add(time.Duration(minutes) * time.Minute), nil
-		}
-	}
+// Subtract subtracts another duration
+func (d Duration) Subtract(other Duration) Duration {
+	return Duration{Duration: d.Duration - other.Duration}
+}
 
-	return time.Time{}, fmt.Errorf("unsupported schedule format")
+// Multiply multiplies the duration by a factor
+func (d Duration) Multiply(factor float64) Duration {
+	return Duration{Duration: time.Duration(float64(d.Duration) * factor)}
+}
+
+// String returns the string representation of the duration
+func (d Duration) String() string {
+	return d.Duration.String()
 }
 
 // TimeCalculator provides time calculation utilities
@@ -262,6 +267,22 @@ func (tc *TimeCalculator) EndOfMonth(t time.Time) time.Time {
 	return tc.StartOfMonth(t.AddDate(0, 1, 0)).Add(-time.Nanosecond)
 }
 
+// Quarter returns the calendar quarter of a time, from 1 to 4
+func (tc *TimeCalculator) Quarter(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
+
+// StartOfQuarter returns the start of the calendar quarter
+func (tc *TimeCalculator) StartOfQuarter(t time.Time) time.Time {
+	month := time.Month((tc.Quarter(t)-1)*3 + 1)
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfQuarter returns the end of the calendar quarter
+func (tc *TimeCalculator) EndOfQuarter(t time.Time) time.Time {
+	return tc.StartOfQuarter(t).AddDate(0, 3, 0).Add(-time.Nanosecond)
+}
+
 // StartOfYear returns the start of the year
 func (tc *TimeCalculator) StartOfYear(t time.Time) time.Time {
 	return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
@@ -289,6 +310,21 @@ func (tc *TimeCalculator) MinutesBetween(start, end time.Time) int {
 	return int(end.Sub(start).Minutes())
 }
 
+// SafeSince returns the time elapsed since t, or zero if t is in the future.
+// Times read from this process's clock carry a monotonic reading and never go
+// backwards; times from other machines or storage may be ahead due to skew.
+func (tc *TimeCalculator) SafeSince(t time.Time) time.Duration {
+	return tc.SafeSub(t, time.Now())
+}
+
+// SafeSub returns end minus start, or zero if end is before start
+func (tc *TimeCalculator) SafeSub(start, end time.Time) time.Duration {
+	if d := end.Sub(start); d > 0 {
+		return d
+	}
+	return 0
+}
+
 // IsWeekend checks if a time falls on a weekend
 func (tc *TimeCalculator) IsWeekend(t time.Time) bool {
 	weekday := t.Weekday()
@@ -333,6 +369,16 @@ func (ft *FormatTime) DateTime(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05")
 }
 
+// ToUnixMilli returns the time as a Unix timestamp in milliseconds, as used by JavaScript
+func (ft *FormatTime) ToUnixMilli(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// ToUnixMicro returns the time as a Unix timestamp in microseconds
+func (ft *FormatTime) ToUnixMicro(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
 // HumanReadable formats time in a human-readable format
 func (ft *FormatTime) HumanReadable(t time.Time) string {
 	now := time.Now()
@@ -371,26 +417,32 @@ func NewParseTime() *ParseTime {
 	return &ParseTime{}
 }
 
-// FromString parses time from a string using common formats
-func (pt *ParseTime) FromString(s string) (time.Time, error) {
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		"2006-01-02T15:04:05Z07:00",
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
-		"15:04:05",
-		"Jan 2, 2006",
-		"January 2, 2006",
-	}
+// parseFormats are the layouts tried by FromString and FromStringInLocation
+var parseFormats = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"15:04:05",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
 
-	for _, format := range formats {
+// FromString parses time from a string using common formats, falling back
+// to relative expressions such as "tomorrow" or "2 days ago"
+func (pt *ParseTime) FromString(s string) (time.Time, error) {
+	for _, format := range parseFormats {
 		if t, err := time.Parse(format, s); err == nil {
 			return t, nil
 		}
 	}
 
+	if t, err := pt.Relative(s, time.Now()); err == nil {
+		return t, nil
+	}
+
 	return time.Time{}, fmt.Errorf("unable to parse time: %s", s)
 }
 
@@ -403,3 +455,13 @@ func (pt *ParseTime) FromUnix(timestamp int64) time.Time {
 func (pt *ParseTime) FromUnixNano(timestamp int64) time.Time {
 	return time.Unix(0, timestamp)
 }
+
+// FromUnixMilli parses time from Unix millisecond timestamp, as used by JavaScript
+func (pt *ParseTime) FromUnixMilli(timestamp int64) time.Time {
+	return time.UnixMilli(timestamp)
+}
+
+// FromUnixMicro parses time from Unix microsecond timestamp
+func (pt *ParseTime) FromUnixMicro(timestamp int64) time.Time {
+	return time.UnixMicro(timestamp)
+}
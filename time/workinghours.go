@@ -0,0 +1,219 @@
+package time
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Working Hours
+// WorkingHours describes weekly opening hours in a timezone,
+// e.g. Mon-Fri 09:00-17:00 Europe/Berlin, for SLA timers and
+// support tooling. Windows are wall-clock times, so they
+// follow daylight saving changes.
+// **************************************************
+
+// maxWorkingHoursSearchDays bounds the search for the next opening, which
+// may need to skip long runs of holidays
+const maxWorkingHoursSearchDays = 366
+
+// hoursWindow is a window within a day, as offsets from midnight
+type hoursWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// WorkingHours represents weekly working hours in a location
+type WorkingHours struct {
+	mu       sync.RWMutex
+	location *time.Location
+	windows  map[time.Weekday][]hoursWindow
+	calendar *Calendar
+}
+
+// NewWorkingHours creates working hours in the given location, with no open
+// windows. A nil location means UTC.
+func NewWorkingHours(loc *time.Location) *WorkingHours {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &WorkingHours{
+		location: loc,
+		windows:  make(map[time.Weekday][]hoursWindow),
+	}
+}
+
+// Location returns the location the working hours are evaluated in
+func (wh *WorkingHours) Location() *time.Location {
+	return wh.location
+}
+
+// AddHours adds an open window from start to end ("09:00", "17:30", "24:00")
+// on each of the given days. Windows cannot cross midnight; add one window on
+// each day instead. A day may have several windows, e.g. around a lunch break.
+func (wh *WorkingHours) AddHours(start, end string, days ...time.Weekday) error {
+	from, err := parseClock(start)
+	if err != nil {
+		return err
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return err
+	}
+	if to <= from {
+		return fmt.Errorf("working hours end %s must be after start %s", end, start)
+	}
+
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+
+	for _, day := range days {
+		windows := append(wh.windows[day], hoursWindow{start: from, end: to})
+		sort.Slice(windows, func(i, j int) bool {
+			return windows[i].start < windows[j].start
+		})
+		wh.windows[day] = windows
+	}
+	return nil
+}
+
+// ClearDay removes all open windows on a day
+func (wh *WorkingHours) ClearDay(day time.Weekday) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	delete(wh.windows, day)
+}
+
+// SetCalendar sets a calendar whose holidays are treated as closed days
+func (wh *WorkingHours) SetCalendar(c *Calendar) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	wh.calendar = c
+}
+
+// IsOpen checks if t falls within the working hours
+func (wh *WorkingHours) IsOpen(t time.Time) bool {
+	wh.mu.RLock()
+	defer wh.mu.RUnlock()
+
+	_, ok := wh.windowAt(t.In(wh.location))
+	return ok
+}
+
+// NextOpen returns t if the working hours are open at t, or else the next
+// time they open. It returns the zero time if they never open.
+func (wh *WorkingHours) NextOpen(t time.Time) time.Time {
+	wh.mu.RLock()
+	defer wh.mu.RUnlock()
+
+	local := t.In(wh.location)
+	if _, ok := wh.windowAt(local); ok {
+		return t
+	}
+	if len(wh.windows) == 0 {
+		return time.Time{}
+	}
+
+	for i := 0; i <= maxWorkingHoursSearchDays; i++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+i, 0, 0, 0, 0, wh.location)
+		if wh.isHoliday(day) {
+			continue
+		}
+		for _, w := range wh.windows[day.Weekday()] {
+			if opens := atOffset(day, w.start); opens.After(local) {
+				return opens
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// DurationUntilClose returns how long the working hours stay open from t,
+// following windows that run back to back, or zero if closed at t
+func (wh *WorkingHours) DurationUntilClose(t time.Time) time.Duration {
+	wh.mu.RLock()
+	defer wh.mu.RUnlock()
+
+	local := t.In(wh.location)
+	closes, ok := wh.windowAt(local)
+	if !ok {
+		return 0
+	}
+
+	// A window ending at midnight may continue into the next day's window
+	for i := 0; i < 7*maxWorkingHoursSearchDays; i++ {
+		next, ok := wh.windowAt(closes)
+		if !ok {
+			break
+		}
+		closes = next
+	}
+	return closes.Sub(t)
+}
+
+// windowAt returns the end of the window containing the local time t.
+// Callers must hold wh.mu.
+func (wh *WorkingHours) windowAt(t time.Time) (time.Time, bool) {
+	if wh.isHoliday(t) {
+		return time.Time{}, false
+	}
+
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, wh.location)
+	offset := clockOffset(t)
+	for _, w := range wh.windows[t.Weekday()] {
+		if offset >= w.start && offset < w.end {
+			return atOffset(day, w.end), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isHoliday checks the calendar, if any. Callers must hold wh.mu.
+func (wh *WorkingHours) isHoliday(t time.Time) bool {
+	return wh.calendar != nil && wh.calendar.IsHoliday(t)
+}
+
+// clockOffset returns the wall-clock time of day as an offset from midnight
+func clockOffset(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+}
+
+// atOffset returns the wall-clock time offset from midnight on day
+func atOffset(day time.Time, offset time.Duration) time.Time {
+	hours := int(offset / time.Hour)
+	minutes := int(offset % time.Hour / time.Minute)
+	seconds := int(offset % time.Minute / time.Second)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, seconds, 0, day.Location())
+}
+
+// parseClock parses a time of day in "HH:MM" or "HH:MM:SS" form, up to "24:00"
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time of day: %q", s)
+	}
+
+	limits := []int{24, 59, 59}
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+
+	var offset time.Duration
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 || value > limits[i] {
+			return 0, fmt.Errorf("invalid time of day: %q", s)
+		}
+		offset += time.Duration(value) * units[i]
+	}
+
+	if offset > 24*time.Hour {
+		return 0, fmt.Errorf("invalid time of day: %q", s)
+	}
+	return offset, nil
+}
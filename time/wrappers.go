@@ -0,0 +1,79 @@
+package time
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Function Wrappers
+// Debounce, Throttle and WithTimeout wrap callbacks and
+// operations with timing behavior. All wrappers are safe
+// for concurrent use.
+// **************************************************
+
+// ErrTimeout is returned by WithTimeout when the operation does not finish in time
+var ErrTimeout = errors.New("operation timed out")
+
+// Debounce returns a function that delays calling fn until d has passed
+// since the last call. Bursts of calls result in a single call of fn.
+func Debounce(d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var timer *time.Timer
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, fn)
+	}
+}
+
+// Throttle returns a function that calls fn at most once every d. The first
+// call runs immediately; further calls within d are dropped.
+func Throttle(d time.Duration, fn func()) func() {
+	var mu sync.Mutex
+	var last time.Time
+
+	return func() {
+		mu.Lock()
+		now := time.Now()
+		if !last.IsZero() && now.Sub(last) < d {
+			mu.Unlock()
+			return
+		}
+		last = now
+		mu.Unlock()
+
+		fn()
+	}
+}
+
+// WithTimeout runs fn with a context that is cancelled after d. It returns
+// as soon as fn finishes, the timeout passes, or ctx is done, whichever comes
+// first; fn should observe its context so it stops after a timeout.
+func WithTimeout(ctx context.Context, d time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrTimeout, d)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,150 @@
+package uuid
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// **************************************************
+// Encoding
+// UUID implements sql.Scanner, driver.Valuer and the JSON, text and binary
+// marshaling interfaces, so it can be used directly in models and payloads.
+// Compact base64 and base32 forms suit bandwidth-sensitive payloads.
+// **************************************************
+
+// Value implements driver.Valuer, storing the UUID as its string form
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts the string form, and 16 raw bytes
+// as stored in binary or native UUID columns. NULL scans as the zero UUID.
+func (u *UUID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*u = UUID{}
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*u = parsed
+		return nil
+	case []byte:
+		if len(v) == len(u) {
+			return u.UnmarshalBinary(v)
+		}
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*u = parsed
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into UUID", src)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the UUID as a string
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A JSON null leaves the UUID
+// unchanged.
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("UUID must be a JSON string: %w", err)
+	}
+	return u.UnmarshalText([]byte(s))
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the 16 bytes
+func (u UUID) MarshalBinary() ([]byte, error) {
+	return u.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (u *UUID) UnmarshalBinary(data []byte) error {
+	parsed, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Bytes returns a copy of the 16 bytes of the UUID, for binary columns
+func (u UUID) Bytes() []byte {
+	b := make([]byte, len(u))
+	copy(b, u[:])
+	return b
+}
+
+// FromBytes creates a UUID from 16 bytes
+func FromBytes(b []byte) (UUID, error) {
+	var uuid UUID
+	if len(b) != len(uuid) {
+		return UUID{}, fmt.Errorf("invalid UUID length %d, expected 16 bytes", len(b))
+	}
+	copy(uuid[:], b)
+	return uuid, nil
+}
+
+// Base64 returns the UUID as 22 URL-safe base64 characters without padding
+func (u UUID) Base64() string {
+	return base64.RawURLEncoding.EncodeToString(u[:])
+}
+
+// FromBase64 parses a UUID from the 22 character form returned by Base64
+func FromBase64(s string) (UUID, error) {
+	if len(s) != 22 {
+		return UUID{}, fmt.Errorf("invalid base64 UUID length")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return UUID{}, fmt.Errorf("invalid base64 UUID: %w", err)
+	}
+	return FromBytes(b)
+}
+
+// Base32 returns the UUID as 26 lowercase Crockford base32 characters, for
+// case-insensitive contexts such as hostnames and file names
+func (u UUID) Base32() string {
+	return crockford.EncodeToString(u[:])
+}
+
+// FromBase32 parses a UUID from the 26 character form returned by Base32,
+// in either case
+func FromBase32(s string) (UUID, error) {
+	if len(s) != 26 {
+		return UUID{}, fmt.Errorf("invalid base32 UUID length")
+	}
+	b, err := crockford.DecodeString(strings.ToLower(s))
+	if err != nil {
+		return UUID{}, fmt.Errorf("invalid base32 UUID: %w", err)
+	}
+	return FromBytes(b)
+}
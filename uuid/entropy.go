@@ -0,0 +1,74 @@
+package uuid
+
+import (
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// **************************************************
+// Entropy
+// All random IDs read from crypto/rand through readRandom, which can serve
+// small reads from pooled buffers to reduce syscall overhead when
+// generating millions of IDs
+// **************************************************
+
+// EntropyMode selects how random IDs read from crypto/rand
+type EntropyMode int32
+
+// Entropy modes
+const (
+	// EntropyDirect reads crypto/rand for every ID. This is the default.
+	EntropyDirect EntropyMode = iota
+	// EntropyPooled serves reads from pooled 4 KiB buffers filled from
+	// crypto/rand. Random bytes are held in memory until used, so prefer
+	// EntropyDirect where that matters.
+	EntropyPooled
+)
+
+// entropyBufferSize is the size of pooled buffers; larger reads go direct
+const entropyBufferSize = 4096
+
+var (
+	entropyMode atomic.Int32
+	entropyPool = sync.Pool{
+		New: func() any {
+			return &entropyBuffer{pos: entropyBufferSize}
+		},
+	}
+)
+
+// entropyBuffer holds random bytes not yet handed out
+type entropyBuffer struct {
+	buf [entropyBufferSize]byte
+	pos int
+}
+
+// SetEntropyMode sets how random IDs read from crypto/rand, e.g.
+// SetEntropyMode(EntropyPooled) at startup in high-throughput services
+func SetEntropyMode(mode EntropyMode) {
+	entropyMode.Store(int32(mode))
+}
+
+// readRandom fills p with random bytes
+func readRandom(p []byte) error {
+	if EntropyMode(entropyMode.Load()) != EntropyPooled || len(p) > entropyBufferSize {
+		_, err := rand.Read(p)
+		return err
+	}
+
+	b := entropyPool.Get().(*entropyBuffer)
+	defer entropyPool.Put(b)
+
+	if entropyBufferSize-b.pos < len(p) {
+		if _, err := rand.Read(b.buf[:]); err != nil {
+			return err
+		}
+		b.pos = 0
+	}
+	n := copy(p, b.buf[b.pos:])
+	// Clear handed-out bytes so they are never reused
+	clear(b.buf[b.pos : b.pos+n])
+	b.pos += n
+	return nil
+}
@@ -0,0 +1,50 @@
+package uuid
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// **************************************************
+// Formatting
+// Formatting writes UUIDs in the alternative forms accepted by Parse
+// **************************************************
+
+// Format selects how Formatted writes a UUID. Options can be combined,
+// e.g. FormatBraced|FormatUpper.
+type Format uint8
+
+// Format options
+const (
+	// FormatCanonical is the lowercase hyphenated form returned by String
+	FormatCanonical Format = 0
+	// FormatSimple omits hyphens: 32 hex characters
+	FormatSimple Format = 1 << iota
+	// FormatBraced wraps the UUID in braces, as used by Microsoft tools
+	FormatBraced
+	// FormatURN prefixes the UUID with "urn:uuid:"
+	FormatURN
+	// FormatUpper uses uppercase hex digits
+	FormatUpper
+)
+
+// Formatted returns the UUID written in the given format
+func (u UUID) Formatted(format Format) string {
+	var s string
+	if format&FormatSimple != 0 {
+		s = hex.EncodeToString(u[:])
+	} else {
+		s = u.String()
+	}
+	if format&FormatUpper != 0 {
+		s = strings.ToUpper(s)
+	}
+
+	switch {
+	case format&FormatURN != 0:
+		return "urn:uuid:" + s
+	case format&FormatBraced != 0:
+		return "{" + s + "}"
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package uuid
+
+import "bytes"
+
+// **************************************************
+// Inspection
+// Inspection reads the version and variant fields of a UUID and orders
+// UUIDs deterministically
+// **************************************************
+
+// Variant is the layout of a UUID, from its variant bits
+type Variant int
+
+// UUID variants
+const (
+	// VariantNCS is reserved for NCS backward compatibility
+	VariantNCS Variant = iota
+	// VariantRFC4122 is the standard layout of RFC 4122 and RFC 9562
+	VariantRFC4122
+	// VariantMicrosoft is reserved for Microsoft backward compatibility
+	VariantMicrosoft
+	// VariantFuture is reserved for future definition
+	VariantFuture
+)
+
+var (
+	// Nil is the UUID with all bits zero
+	Nil UUID
+	// Max is the UUID with all bits set
+	Max = UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+)
+
+// String returns the name of the variant
+func (v Variant) String() string {
+	switch v {
+	case VariantNCS:
+		return "NCS"
+	case VariantRFC4122:
+		return "RFC 4122"
+	case VariantMicrosoft:
+		return "Microsoft"
+	}
+	return "Future"
+}
+
+// Version returns the version of the UUID, e.g. 4 for a random UUID. The
+// version is only meaningful for the VariantRFC4122 variant.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Variant returns the variant of the UUID
+func (u UUID) Variant() Variant {
+	switch {
+	case u[8]&0x80 == 0:
+		return VariantNCS
+	case u[8]&0xc0 == 0x80:
+		return VariantRFC4122
+	case u[8]&0xe0 == 0xc0:
+		return VariantMicrosoft
+	}
+	return VariantFuture
+}
+
+// IsNil checks if the UUID is the Nil UUID
+func (u UUID) IsNil() bool {
+	return u == Nil
+}
+
+// Compare orders two UUIDs by their bytes, returning -1, 0 or +1. Version
+// 7 UUIDs compare in creation order.
+func Compare(a, b UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// Equal checks if two UUIDs are equal
+func Equal(a, b UUID) bool {
+	return a == b
+}
@@ -0,0 +1,101 @@
+package uuid
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// **************************************************
+// Short IDs
+// Short IDs are random URL-safe strings in the style of NanoID, for
+// public-facing slugs where a full UUID is overkill
+// **************************************************
+
+const (
+	// ShortIDAlphabet is the default URL-safe alphabet
+	ShortIDAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz_-"
+	// DefaultShortIDLength gives about the same collision resistance as a
+	// random UUID with the default alphabet
+	DefaultShortIDLength = 21
+)
+
+// NewShortID generates a random ID of length characters from alphabet.
+// Characters are chosen uniformly, without modulo bias. An empty alphabet
+// uses ShortIDAlphabet.
+func NewShortID(length int, alphabet string) (string, error) {
+	if alphabet == "" {
+		alphabet = ShortIDAlphabet
+	}
+	if length <= 0 {
+		return "", fmt.Errorf("short ID length must be positive")
+	}
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		return "", fmt.Errorf("short ID alphabet must have 2 to 256 characters")
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if r > 127 || seen[r] {
+			return "", fmt.Errorf("short ID alphabet must be unique ASCII characters")
+		}
+		seen[r] = true
+	}
+
+	// Mask random bytes to the smallest power of two covering the alphabet
+	// and reject values outside it
+	mask := byte(1<<bits.Len(uint(len(alphabet)-1)) - 1)
+	id := make([]byte, 0, length)
+	buf := make([]byte, length+length/2)
+	for len(id) < length {
+		if err := readRandom(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if i := int(b & mask); i < len(alphabet) {
+				id = append(id, alphabet[i])
+				if len(id) == length {
+					break
+				}
+			}
+		}
+	}
+	return string(id), nil
+}
+
+// MustNewShortID generates a short ID with the default length and alphabet
+// or panics
+func MustNewShortID() string {
+	id, err := NewShortID(DefaultShortIDLength, ShortIDAlphabet)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// CollisionProbability estimates the probability that at least two of
+// count random IDs of length characters from an alphabet of alphabetSize
+// characters are equal
+func CollisionProbability(length, alphabetSize int, count float64) float64 {
+	if count < 2 {
+		return 0
+	}
+	// Birthday bound: 1 - e^(-n²/2N), with N = alphabetSize^length
+	logSpace := float64(length) * math.Log(float64(alphabetSize))
+	exponent := 2*math.Log(count) - math.Log(2) - logSpace
+	return -math.Expm1(-math.Exp(exponent))
+}
+
+// IDsForCollisionProbability estimates how many random IDs of length
+// characters from an alphabet of alphabetSize characters can be generated
+// before the probability of a collision reaches p
+func IDsForCollisionProbability(length, alphabetSize int, p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+	// n = sqrt(2N * ln(1/(1-p)))
+	logSpace := float64(length) * math.Log(float64(alphabetSize))
+	return math.Exp((math.Log(2) + logSpace + math.Log(-math.Log1p(-p))) / 2)
+}
@@ -0,0 +1,170 @@
+package uuid
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// **************************************************
+// Snowflake IDs
+// Snowflake IDs are K-sortable 63-bit integers made of a millisecond
+// timestamp, a node ID and a per-millisecond sequence, for services that
+// need ordered int64 IDs rather than 128-bit UUIDs
+// **************************************************
+
+const (
+	// DefaultSnowflakeNodeBits allows 1024 nodes
+	DefaultSnowflakeNodeBits = 10
+	// DefaultSnowflakeSequenceBits allows 4096 IDs per millisecond per node
+	DefaultSnowflakeSequenceBits = 12
+	// DefaultMaxClockDrift is how far the clock may move backwards before
+	// Next fails instead of waiting for it to catch up
+	DefaultMaxClockDrift = 10 * time.Millisecond
+)
+
+// DefaultSnowflakeEpoch is the default start of Snowflake time
+var DefaultSnowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrClockMovedBackwards is returned when the clock moves backwards by more
+// than the allowed drift
+var ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+// SnowflakeConfig configures a Snowflake generator
+type SnowflakeConfig struct {
+	// NodeID identifies this generator; IDs are unique across generators
+	// with different node IDs
+	NodeID int64
+	// Epoch is the start of Snowflake time. Defaults to DefaultSnowflakeEpoch.
+	Epoch time.Time
+	// NodeBits and SequenceBits split the 22 low bits between the node ID
+	// and the sequence. Default to 10 and 12 when both are zero.
+	NodeBits     uint8
+	SequenceBits uint8
+	// MaxClockDrift is how far the clock may move backwards before Next
+	// fails. Defaults to DefaultMaxClockDrift.
+	MaxClockDrift time.Duration
+}
+
+// SnowflakeParts are the components of a Snowflake ID
+type SnowflakeParts struct {
+	Time     time.Time
+	NodeID   int64
+	Sequence int64
+}
+
+// Snowflake generates Snowflake IDs. It is safe for concurrent use.
+type Snowflake struct {
+	mu            sync.Mutex
+	epoch         time.Time
+	nodeID        int64
+	nodeBits      uint8
+	sequenceBits  uint8
+	maxClockDrift time.Duration
+	lastMillis    int64
+	sequence      int64
+	now           func() time.Time
+}
+
+// NewSnowflake creates a new Snowflake generator
+func NewSnowflake(config SnowflakeConfig) (*Snowflake, error) {
+	if config.Epoch.IsZero() {
+		config.Epoch = DefaultSnowflakeEpoch
+	}
+	if config.NodeBits == 0 && config.SequenceBits == 0 {
+		config.NodeBits = DefaultSnowflakeNodeBits
+		config.SequenceBits = DefaultSnowflakeSequenceBits
+	}
+	if config.MaxClockDrift == 0 {
+		config.MaxClockDrift = DefaultMaxClockDrift
+	}
+
+	if int(config.NodeBits)+int(config.SequenceBits) > 22 {
+		return nil, fmt.Errorf("node and sequence bits must total at most 22, got %d", config.NodeBits+config.SequenceBits)
+	}
+	if config.NodeID < 0 || config.NodeID >= 1<<config.NodeBits {
+		return nil, fmt.Errorf("node ID %d must be between 0 and %d", config.NodeID, 1<<config.NodeBits-1)
+	}
+	if config.Epoch.After(time.Now()) {
+		return nil, fmt.Errorf("epoch %v is in the future", config.Epoch)
+	}
+
+	return &Snowflake{
+		epoch:         config.Epoch,
+		nodeID:        config.NodeID,
+		nodeBits:      config.NodeBits,
+		sequenceBits:  config.SequenceBits,
+		maxClockDrift: config.MaxClockDrift,
+		lastMillis:    -1,
+		now:           time.Now,
+	}, nil
+}
+
+// Next generates a new ID. IDs from one generator are strictly increasing.
+// If the clock moves backwards by up to MaxClockDrift, Next waits for it
+// to catch up; beyond that it returns ErrClockMovedBackwards.
+func (s *Snowflake) Next() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	millis := s.millis()
+	if millis < s.lastMillis {
+		drift := time.Duration(s.lastMillis-millis) * time.Millisecond
+		if drift > s.maxClockDrift {
+			return 0, fmt.Errorf("%w by %v", ErrClockMovedBackwards, drift)
+		}
+		millis = s.waitUntil(s.lastMillis)
+	}
+
+	if millis == s.lastMillis {
+		s.sequence = (s.sequence + 1) & (1<<s.sequenceBits - 1)
+		if s.sequence == 0 {
+			// Sequence exhausted for this millisecond
+			millis = s.waitUntil(s.lastMillis + 1)
+		}
+	} else {
+		s.sequence = 0
+	}
+
+	if millis >= 1<<(63-s.nodeBits-s.sequenceBits) {
+		return 0, errors.New("snowflake timestamp overflow")
+	}
+	s.lastMillis = millis
+
+	return millis<<(s.nodeBits+s.sequenceBits) | s.nodeID<<s.sequenceBits | s.sequence, nil
+}
+
+// MustNext generates a new ID or panics
+func (s *Snowflake) MustNext() int64 {
+	id, err := s.Next()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Decompose splits an ID from this generator into its components
+func (s *Snowflake) Decompose(id int64) SnowflakeParts {
+	shift := s.nodeBits + s.sequenceBits
+	return SnowflakeParts{
+		Time:     s.epoch.Add(time.Duration(id>>shift) * time.Millisecond),
+		NodeID:   (id >> s.sequenceBits) & (1<<s.nodeBits - 1),
+		Sequence: id & (1<<s.sequenceBits - 1),
+	}
+}
+
+// millis returns the milliseconds since the epoch
+func (s *Snowflake) millis() int64 {
+	return s.now().Sub(s.epoch).Milliseconds()
+}
+
+// waitUntil sleeps until the clock reaches target milliseconds
+func (s *Snowflake) waitUntil(target int64) int64 {
+	millis := s.millis()
+	for millis < target {
+		time.Sleep(time.Duration(target-millis) * time.Millisecond)
+		millis = s.millis()
+	}
+	return millis
+}
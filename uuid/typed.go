@@ -0,0 +1,240 @@
+package uuid
+
+import (
+	"database/sql/driver"
+	"encoding/base32"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// **************************************************
+// Typed IDs
+// Typed IDs are prefixed, compact encodings of a UUID such as
+// "usr_2ZkLbMwV9dDkT0aGQ1f3xE", which show the kind of object an ID
+// refers to and are rejected when used for the wrong kind
+// **************************************************
+
+// IDEncoding selects how the UUID part of a typed ID is encoded
+type IDEncoding int
+
+// Typed ID encodings
+const (
+	// Base62 encodes the UUID in 22 characters of 0-9, A-Z and a-z
+	Base62 IDEncoding = iota
+	// Base32 encodes the UUID in 26 lowercase Crockford base32 characters,
+	// for case-insensitive contexts
+	Base32
+)
+
+const (
+	base62Alphabet   = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	base62Length     = 22
+	base32Alphabet   = "0123456789abcdefghjkmnpqrstvwxyz"
+	base32Length     = 26
+	idChecksumLength = 2
+)
+
+var (
+	idPrefixRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	crockford     = base32.NewEncoding(base32Alphabet).WithPadding(base32.NoPadding)
+	maxUUID       = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// TypedID is a prefixed identifier such as "usr_2ZkLbMwV9dDkT0aGQ1f3xE".
+// It is stored as its string form, so it can be used as a model field.
+type TypedID string
+
+// IDFormat describes a kind of typed ID
+type IDFormat struct {
+	// Prefix names the kind of object, e.g. "usr" or "inv"
+	Prefix string
+	// Encoding of the UUID part, Base62 by default
+	Encoding IDEncoding
+	// Checksum appends two check characters, so mistyped IDs are rejected
+	// without a database lookup
+	Checksum bool
+}
+
+// NewPrefixedID generates a base62 typed ID from a random UUID, e.g.
+// NewPrefixedID("usr") returns "usr_2ZkLbMwV9dDkT0aGQ1f3xE"
+func NewPrefixedID(prefix string) (TypedID, error) {
+	return IDFormat{Prefix: prefix}.New()
+}
+
+// ParsePrefixedID parses a base62 typed ID, checking it has the expected prefix
+func ParsePrefixedID(s, prefix string) (UUID, error) {
+	return IDFormat{Prefix: prefix}.Parse(s)
+}
+
+// New generates a typed ID from a random UUID
+func (f IDFormat) New() (TypedID, error) {
+	uuid, err := NewV4()
+	if err != nil {
+		return "", err
+	}
+	return f.Format(uuid)
+}
+
+// Format encodes a UUID as a typed ID
+func (f IDFormat) Format(uuid UUID) (TypedID, error) {
+	if !idPrefixRegex.MatchString(f.Prefix) {
+		return "", fmt.Errorf("invalid ID prefix %q", f.Prefix)
+	}
+
+	var body string
+	switch f.Encoding {
+	case Base62:
+		body = encodeBase62(uuid)
+	case Base32:
+		body = uuid.Base32()
+	default:
+		return "", fmt.Errorf("unknown ID encoding %d", f.Encoding)
+	}
+
+	if f.Checksum {
+		body += f.checksum(body)
+	}
+	return TypedID(f.Prefix + "_" + body), nil
+}
+
+// Parse decodes a typed ID, checking its prefix, encoding and checksum
+func (f IDFormat) Parse(s string) (UUID, error) {
+	prefix, body, ok := splitTypedID(s)
+	if !ok {
+		return UUID{}, fmt.Errorf("invalid typed ID format")
+	}
+	if prefix != f.Prefix {
+		return UUID{}, fmt.Errorf("typed ID has prefix %q, expected %q", prefix, f.Prefix)
+	}
+
+	if f.Checksum {
+		if len(body) <= idChecksumLength {
+			return UUID{}, fmt.Errorf("invalid typed ID length")
+		}
+		sum := body[len(body)-idChecksumLength:]
+		body = body[:len(body)-idChecksumLength]
+		if f.checksum(body) != sum {
+			return UUID{}, fmt.Errorf("invalid typed ID checksum")
+		}
+	}
+	return f.decode(body)
+}
+
+// decode decodes the encoded UUID part of a typed ID
+func (f IDFormat) decode(body string) (UUID, error) {
+	switch f.Encoding {
+	case Base62:
+		if len(body) != base62Length {
+			return UUID{}, fmt.Errorf("invalid typed ID length")
+		}
+		return decodeBase62(body)
+	case Base32:
+		if len(body) != base32Length {
+			return UUID{}, fmt.Errorf("invalid typed ID length")
+		}
+		uuid, err := FromBase32(body)
+		if err != nil {
+			return UUID{}, fmt.Errorf("invalid typed ID character")
+		}
+		return uuid, nil
+	}
+	return UUID{}, fmt.Errorf("unknown ID encoding %d", f.Encoding)
+}
+
+// checksum computes the check characters of a typed ID body
+func (f IDFormat) checksum(body string) string {
+	alphabet := base62Alphabet
+	if f.Encoding == Base32 {
+		alphabet = base32Alphabet
+	}
+	sum := crc32.ChecksumIEEE([]byte(f.Prefix + "_" + body))
+	n := uint32(len(alphabet))
+	return string([]byte{alphabet[sum%n], alphabet[(sum/n)%n]})
+}
+
+// Prefix returns the prefix of a typed ID, or "" if it is malformed
+func (id TypedID) Prefix() string {
+	prefix, _, _ := splitTypedID(string(id))
+	return prefix
+}
+
+// String returns the typed ID
+func (id TypedID) String() string {
+	return string(id)
+}
+
+// Value implements driver.Valuer. An empty ID is stored as NULL.
+func (id TypedID) Value() (driver.Value, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return string(id), nil
+}
+
+// Scan implements sql.Scanner, checking the value looks like a typed ID.
+// NULL scans as an empty ID.
+func (id *TypedID) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*id = ""
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TypedID", src)
+	}
+	if _, _, ok := splitTypedID(s); !ok {
+		return fmt.Errorf("invalid typed ID format")
+	}
+	*id = TypedID(s)
+	return nil
+}
+
+// splitTypedID splits a typed ID at its last underscore
+func splitTypedID(s string) (string, string, bool) {
+	i := strings.LastIndexByte(s, '_')
+	if i < 0 || i == len(s)-1 || !idPrefixRegex.MatchString(s[:i]) {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
+}
+
+// encodeBase62 encodes a UUID in a fixed width of base62 characters
+func encodeBase62(uuid UUID) string {
+	n := new(big.Int).SetBytes(uuid[:])
+	base := big.NewInt(int64(len(base62Alphabet)))
+	mod := new(big.Int)
+
+	out := make([]byte, base62Length)
+	for i := base62Length - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out)
+}
+
+// decodeBase62 decodes a fixed width base62 string into a UUID
+func decodeBase62(s string) (UUID, error) {
+	n := new(big.Int)
+	base := big.NewInt(int64(len(base62Alphabet)))
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base62Alphabet, s[i])
+		if digit < 0 {
+			return UUID{}, fmt.Errorf("invalid typed ID character")
+		}
+		n.Mul(n, base).Add(n, big.NewInt(int64(digit)))
+	}
+	if n.Cmp(maxUUID) >= 0 {
+		return UUID{}, fmt.Errorf("typed ID out of range")
+	}
+
+	var uuid UUID
+	n.FillBytes(uuid[:])
+	return uuid, nil
+}
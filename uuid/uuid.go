@@ -1,9 +1,8 @@
 package uuid
 
 import (
-	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
@@ -26,7 +25,7 @@ func NewV4() (UUID, error) {
 	var uuid UUID
 
 	// Generate 16 random bytes
-	_, err := rand.Read(uuid[:])
+	err := readRandom(uuid[:])
 	if err != nil {
 		return UUID{}, err
 	}
@@ -50,6 +49,9 @@ func NewUUIDString() (string, error) {
 }
 
 // NewWithNamespace generates a new UUID string with a namespace
+//
+// Deprecated: the result is a random UUID with the namespace appended, not a
+// UUID. Use NewV5 to derive a UUID from a namespace and name.
 func NewWithNamespace(namespace string) (string, error) {
 	uuid, err := NewV4()
 	if err != nil {
@@ -68,39 +70,58 @@ func MustNewUUIDString() string {
 	return uuid
 }
 
-// Parse parses a UUID string into a 16 byte UUID struct
+// Parse parses a UUID string of any version into a 16 byte UUID struct. It
+// accepts the canonical hyphenated form in either case, braced
+// "{...}", URN "urn:uuid:..." and 32 character unhyphenated forms.
 func Parse(s string) (UUID, error) {
-	var uuid UUID
-
-	// Validate format
-	matched, err := regexp.MatchString(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, s)
-	if err != nil || !matched {
-		return UUID{}, fmt.Errorf("invalid UUID format")
+	switch {
+	case len(s) > 9 && strings.EqualFold(s[:9], "urn:uuid:"):
+		s = s[9:]
+	case len(s) > 2 && s[0] == '{' && s[len(s)-1] == '}':
+		s = s[1 : len(s)-1]
 	}
 
-	// Remove hyphens and parse
-	s = strings.ReplaceAll(s, "-", "")
-	if len(s) != 32 {
-		return UUID{}, fmt.Errorf("invalid UUID length")
-	}
-
-	// Convert hex string to bytes
-	for i := 0; i < 16; i++ {
-		var b byte
-		_, err := fmt.Sscanf(s[i*2:i*2+2], "%02x", &b)
-		if err != nil {
+	switch len(s) {
+	case 36:
+		return parseHex(s)
+	case 32:
+		var uuid UUID
+		if _, err := hex.Decode(uuid[:], []byte(s)); err != nil {
 			return UUID{}, fmt.Errorf("invalid hex character")
 		}
-		uuid[i] = b
+		return uuid, nil
 	}
+	return UUID{}, fmt.Errorf("invalid UUID format")
+}
 
-	return uuid, nil
+// MustParse parses a UUID string or panics, for UUID constants
+func MustParse(s string) UUID {
+	uuid, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("uuid: cannot parse %q: %v", s, err))
+	}
+	return uuid
 }
 
-// IsValid checks if a string is a valid UUID format
+// IsValid checks if a string is a valid UUID in the canonical hyphenated
+// form, of any version, with the standard variant. The Nil and Max UUIDs
+// are also valid.
 func IsValid(s string) bool {
-	matched, _ := regexp.MatchString(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, s)
-	return matched
+	uuid, err := parseHex(s)
+	if err != nil {
+		return false
+	}
+	if uuid == Nil || uuid == Max {
+		return true
+	}
+	return uuid.Variant() == VariantRFC4122 && uuid.Version() >= 1 && uuid.Version() <= 8
+}
+
+// IsValidVersion checks if a string is a valid UUID of a specific version,
+// e.g. IsValidVersion(s, 4) for random UUIDs
+func IsValidVersion(s string, version int) bool {
+	uuid, err := parseHex(s)
+	return err == nil && uuid.Variant() == VariantRFC4122 && uuid.Version() == version
 }
 
 // MustNewV4 generates a new UUID or panics
@@ -111,3 +132,17 @@ func MustNewV4() UUID {
 	}
 	return uuid
 }
+
+// parseHex parses a hyphenated UUID string of any version
+func parseHex(s string) (UUID, error) {
+	var uuid UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return UUID{}, fmt.Errorf("invalid UUID format")
+	}
+	b, err := hex.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+	if err != nil {
+		return UUID{}, fmt.Errorf("invalid hex character")
+	}
+	copy(uuid[:], b)
+	return uuid, nil
+}
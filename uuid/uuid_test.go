@@ -0,0 +1,43 @@
+package uuid
+
+import "testing"
+
+// batchSize is the number of UUIDs per benchmark iteration, a typical
+// gq.BatchInsert batch
+const batchSize = 1000
+
+func BenchmarkNewV4Loop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < batchSize; j++ {
+			if _, err := NewV4(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkNewV4Batch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := NewV4Batch(batchSize); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewV7Loop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < batchSize; j++ {
+			if _, err := NewV7(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkNewV7Batch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := NewV7Batch(batchSize); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package uuid
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// **************************************************
+// Time-Ordered UUIDs and Batches
+// Version 7 UUIDs start with a millisecond timestamp, so they sort by
+// creation time and index well as primary keys. Batch constructors read
+// all the randomness for a batch at once.
+// **************************************************
+
+// NewV7 generates a new time-ordered UUID (version 7). UUIDs from
+// different milliseconds sort by time; within a millisecond the order is
+// random. Use NewV7Batch for strictly increasing UUIDs.
+func NewV7() (UUID, error) {
+	var uuid UUID
+	if err := readRandom(uuid[6:]); err != nil {
+		return UUID{}, err
+	}
+	setV7(&uuid, time.Now().UnixMilli(), -1)
+	return uuid, nil
+}
+
+// MustNewV7 generates a new version 7 UUID or panics
+func MustNewV7() UUID {
+	uuid, err := NewV7()
+	if err != nil {
+		panic(err)
+	}
+	return uuid
+}
+
+// NewV4Batch generates n random UUIDs (version 4) from a single read of
+// crypto/rand, for high-throughput insert paths such as gq.BatchInsert
+func NewV4Batch(n int) ([]UUID, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	uuids := make([]UUID, n)
+	buf := make([]byte, 16*n)
+	if err := readRandom(buf); err != nil {
+		return nil, err
+	}
+	for i := range uuids {
+		copy(uuids[i][:], buf[i*16:])
+		uuids[i][6] = (uuids[i][6] & 0x0f) | 0x40
+		uuids[i][8] = (uuids[i][8] & 0x3f) | 0x80
+	}
+	return uuids, nil
+}
+
+// NewV7Batch generates n time-ordered UUIDs (version 7) from a single read
+// of crypto/rand. The UUIDs are strictly increasing: a 12-bit counter
+// orders them within a millisecond, moving to the next millisecond when it
+// runs out.
+func NewV7Batch(n int) ([]UUID, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	uuids := make([]UUID, n)
+	buf := make([]byte, 10*n+2)
+	if err := readRandom(buf); err != nil {
+		return nil, err
+	}
+
+	millis := time.Now().UnixMilli()
+	// Start the counter in the lower half so a batch rarely overflows it
+	counter := int(binary.BigEndian.Uint16(buf[10*n:]) & 0x7ff)
+	for i := range uuids {
+		if counter > 0xfff {
+			millis++
+			counter = 0
+		}
+		copy(uuids[i][6:], buf[i*10:(i+1)*10])
+		setV7(&uuids[i], millis, counter)
+		counter++
+	}
+	return uuids, nil
+}
+
+// setV7 writes the timestamp, version and variant of a version 7 UUID
+// whose bytes 6-15 are random. A counter of zero or more replaces the
+// 12 random bits after the version.
+func setV7(uuid *UUID, millis int64, counter int) {
+	uuid[0] = byte(millis >> 40)
+	uuid[1] = byte(millis >> 32)
+	uuid[2] = byte(millis >> 24)
+	uuid[3] = byte(millis >> 16)
+	uuid[4] = byte(millis >> 8)
+	uuid[5] = byte(millis)
+
+	if counter >= 0 {
+		uuid[6] = byte(counter >> 8)
+		uuid[7] = byte(counter)
+	}
+
+	// Set version (7) in the 7th byte
+	uuid[6] = (uuid[6] & 0x0f) | 0x70
+
+	// Set variant bits in the 9th byte
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+}
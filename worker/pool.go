@@ -0,0 +1,315 @@
+// Package worker runs on-demand background jobs on a bounded pool of
+// goroutines, optionally fed by a persistent queue. It complements the
+// time.Cron scheduler, which runs jobs on a schedule.
+package worker
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/arbenlabs/stoner/logger"
+)
+
+// **************************************************
+// Pool
+// Pool runs submitted jobs on a fixed number of goroutines,
+// highest priority first and in submission order within a
+// priority. Panics are recovered and reported as errors.
+// **************************************************
+
+var (
+	// ErrPoolClosed is returned when submitting to a pool that is shutting down
+	ErrPoolClosed = errors.New("worker pool is closed")
+	// ErrQueueFull is returned when the pool's queue has reached its size limit
+	ErrQueueFull = errors.New("worker queue is full")
+)
+
+// Job is a unit of work run by a Pool
+type Job interface {
+	Run(ctx context.Context) error
+}
+
+// JobFunc adapts a function to Job
+type JobFunc func(ctx context.Context) error
+
+// Run calls f
+func (f JobFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Priority orders queued jobs; higher priorities run first
+type Priority int
+
+// Common priorities; any int value may be used
+const (
+	PriorityLow    Priority = -10
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 10
+)
+
+// PanicError is reported when a job panics
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v\n%s", e.Value, e.Stack)
+}
+
+// PoolConfig configures a Pool
+type PoolConfig struct {
+	Workers   int // number of goroutines; 0 uses runtime.NumCPU
+	QueueSize int // maximum number of queued jobs; 0 means unbounded
+}
+
+// PoolStats is a snapshot of a pool's activity
+type PoolStats struct {
+	Workers   int
+	Queued    int
+	Running   int
+	Completed uint64
+	Failed    uint64
+}
+
+// queuedJob is a job waiting in the pool's priority queue
+type queuedJob struct {
+	job      Job
+	priority Priority
+	seq      uint64
+}
+
+// jobQueue is a max-heap on priority, then FIFO on submission order
+type jobQueue []queuedJob
+
+func (q jobQueue) Len() int { return len(q) }
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q jobQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *jobQueue) Push(x any)   { *q = append(*q, x.(queuedJob)) }
+func (q *jobQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	old[len(old)-1] = queuedJob{}
+	*q = old[:len(old)-1]
+	return item
+}
+
+// Pool is a bounded worker pool
+type Pool struct {
+	config PoolConfig
+
+	mu        sync.Mutex
+	ready     *sync.Cond
+	queue     jobQueue
+	seq       uint64
+	closed    bool
+	running   int
+	completed uint64
+	failed    uint64
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	errorHandler func(job Job, err error)
+	logger       *logger.Logger
+}
+
+// NewPool creates a pool and starts its workers
+func NewPool(config PoolConfig) *Pool {
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	p.ready = sync.NewCond(&p.mu)
+
+	p.workers.Add(config.Workers)
+	for i := 0; i < config.Workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// SetErrorHandler sets a function called with every job error, including
+// recovered panics. Queue errors of a Processor are reported with a nil job.
+// Without a handler, errors are logged if a logger is set.
+func (p *Pool) SetErrorHandler(handler func(job Job, err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.errorHandler = handler
+}
+
+// SetLogger sets the logger used to report job errors when no error handler is set
+func (p *Pool) SetLogger(l *logger.Logger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logger = l
+}
+
+// Submit queues a job at normal priority
+func (p *Pool) Submit(job Job) error {
+	return p.SubmitPriority(job, PriorityNormal)
+}
+
+// SubmitFunc queues a function at normal priority
+func (p *Pool) SubmitFunc(fn func(ctx context.Context) error) error {
+	return p.SubmitPriority(JobFunc(fn), PriorityNormal)
+}
+
+// SubmitPriority queues a job at the given priority. It returns ErrQueueFull
+// when the queue is at its size limit and ErrPoolClosed after Shutdown.
+func (p *Pool) SubmitPriority(job Job, priority Priority) error {
+	if job == nil {
+		return fmt.Errorf("job cannot be nil")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+	if p.config.QueueSize > 0 && len(p.queue) >= p.config.QueueSize {
+		return ErrQueueFull
+	}
+
+	p.seq++
+	heap.Push(&p.queue, queuedJob{job: job, priority: priority, seq: p.seq})
+	p.ready.Signal()
+	return nil
+}
+
+// Idle returns the number of workers that could start a job right now
+func (p *Pool) Idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idle := p.config.Workers - p.running - len(p.queue)
+	if idle < 0 {
+		return 0
+	}
+	return idle
+}
+
+// Stats returns a snapshot of the pool's activity
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return PoolStats{
+		Workers:   p.config.Workers,
+		Queued:    len(p.queue),
+		Running:   p.running,
+		Completed: p.completed,
+		Failed:    p.failed,
+	}
+}
+
+// Shutdown stops accepting jobs and waits for queued and running jobs to
+// finish. If ctx is done first, the contexts of running jobs are cancelled,
+// queued jobs are dropped, and ctx's error is returned once the running
+// jobs have returned.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.ready.Broadcast()
+	p.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	p.queue = nil
+	p.mu.Unlock()
+	p.cancel()
+	<-drained
+	return ctx.Err()
+}
+
+// work runs queued jobs until the pool is closed and drained
+func (p *Pool) work() {
+	defer p.workers.Done()
+
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.ready.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		item := heap.Pop(&p.queue).(queuedJob)
+		p.running++
+		p.mu.Unlock()
+
+		err := p.run(item.job)
+
+		p.mu.Lock()
+		p.running--
+		if err != nil {
+			p.failed++
+		} else {
+			p.completed++
+		}
+		p.mu.Unlock()
+
+		if err != nil {
+			p.reportError(item.job, err)
+		}
+	}
+}
+
+// run runs a single job, recovering panics
+func (p *Pool) run(job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := make([]byte, 4096)
+			length := runtime.Stack(stack, false)
+			err = &PanicError{Value: r, Stack: stack[:length]}
+		}
+	}()
+
+	return job.Run(p.ctx)
+}
+
+// reportError forwards a job error to the error handler, or the logger
+func (p *Pool) reportError(job Job, err error) {
+	p.mu.Lock()
+	handler := p.errorHandler
+	l := p.logger
+	p.mu.Unlock()
+
+	if handler != nil {
+		handler(job, err)
+		return
+	}
+	if l != nil {
+		l.Error("Worker job failed", "job", fmt.Sprintf("%T", job), "error", err.Error())
+	}
+}
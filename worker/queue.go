@@ -0,0 +1,432 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arbenlabs/stoner/uuid"
+)
+
+// **************************************************
+// Persistent Queue
+// A Queue persists jobs so that they survive restarts and can be
+// shared by several instances. A Processor claims due jobs from
+// the queue, runs them on a Pool through named handlers, and
+// records the outcome with retries and backoff.
+// **************************************************
+
+// Default processor settings
+const (
+	DefaultPollInterval = time.Second
+	DefaultJobLease     = 5 * time.Minute
+	DefaultMaxAttempts  = 5
+)
+
+// QueueRecord is a persisted job
+type QueueRecord struct {
+	ID          string
+	Name        string // handler name
+	Payload     []byte
+	Priority    Priority
+	Attempts    int // attempts started, including the current one once claimed
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   string
+}
+
+// Queue persists jobs and arbitrates which instance runs each one
+type Queue interface {
+	// Enqueue persists a new job
+	Enqueue(ctx context.Context, record QueueRecord) error
+
+	// Claim locks the highest-priority job whose RunAt has passed for owner
+	// until the lease expires, increments its attempts and returns it. It
+	// returns nil when no job is due. A job whose lease expired without
+	// being completed can be claimed again, unless it has used its
+	// MaxAttempts: it is then marked dead, since its runs keep crashing.
+	Claim(ctx context.Context, owner string, lease time.Duration) (*QueueRecord, error)
+
+	// Complete marks a job claimed by owner as done
+	Complete(ctx context.Context, id, owner string) error
+
+	// Fail records a failed attempt of a job claimed by owner and releases
+	// it. When retryAt is zero the job is marked dead and never runs again;
+	// otherwise it becomes due again at retryAt.
+	Fail(ctx context.Context, id, owner string, runErr error, retryAt time.Time) error
+}
+
+// Handler runs a persisted job's payload
+type Handler func(ctx context.Context, payload []byte) error
+
+// EnqueueOptions configures a persisted job
+type EnqueueOptions struct {
+	Priority    Priority
+	RunAt       time.Time // zero runs as soon as possible
+	MaxAttempts int       // 0 uses DefaultMaxAttempts
+}
+
+// ProcessorConfig configures a Processor
+type ProcessorConfig struct {
+	Owner        string        // identifies this instance; defaults to hostname and process ID
+	PollInterval time.Duration // how often to poll an empty queue; 0 uses DefaultPollInterval
+	Lease        time.Duration // how long a claimed job stays locked; 0 uses DefaultJobLease
+
+	// RetryDelay returns the delay before retrying a job that failed its
+	// attempts-th attempt; nil doubles from 1s up to 1h
+	RetryDelay func(attempts int) time.Duration
+}
+
+// Processor moves jobs from a Queue to a Pool
+type Processor struct {
+	queue  Queue
+	pool   *Pool
+	config ProcessorConfig
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	running  bool
+	stop     chan struct{}
+	done     chan struct{}
+	wake     chan struct{}
+}
+
+// NewProcessor creates a processor that runs jobs from queue on pool
+func NewProcessor(queue Queue, pool *Pool, config ProcessorConfig) *Processor {
+	if config.Owner == "" {
+		config.Owner = defaultOwner()
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultJobLease
+	}
+	if config.RetryDelay == nil {
+		config.RetryDelay = defaultRetryDelay
+	}
+
+	return &Processor{
+		queue:    queue,
+		pool:     pool,
+		config:   config,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler for jobs named name
+func (p *Processor) Register(name string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[name] = handler
+}
+
+// Enqueue persists a job for the handler registered as name. A []byte
+// payload is stored as is; any other payload is encoded as JSON.
+func (p *Processor) Enqueue(ctx context.Context, name string, payload any, opts EnqueueOptions) (string, error) {
+	var data []byte
+	switch v := payload.(type) {
+	case []byte:
+		data = v
+	default:
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode payload of job %s: %w", name, err)
+		}
+		data = encoded
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	record := QueueRecord{
+		ID:          id.String(),
+		Name:        name,
+		Payload:     data,
+		Priority:    opts.Priority,
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt,
+	}
+	if record.MaxAttempts <= 0 {
+		record.MaxAttempts = DefaultMaxAttempts
+	}
+	if record.RunAt.IsZero() {
+		record.RunAt = time.Now()
+	}
+
+	if err := p.queue.Enqueue(ctx, record); err != nil {
+		return "", fmt.Errorf("failed to enqueue job %s: %w", name, err)
+	}
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return record.ID, nil
+}
+
+// Start starts polling the queue
+func (p *Processor) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return
+	}
+	p.running = true
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+
+	go p.poll(p.stop, p.done)
+}
+
+// Stop stops polling and waits for the polling loop to exit. Jobs already
+// handed to the pool keep running; shut the pool down to wait for them.
+func (p *Processor) Stop() {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return
+	}
+	p.running = false
+	close(p.stop)
+	done := p.done
+	p.mu.Unlock()
+
+	<-done
+}
+
+// poll claims due jobs while the pool has idle workers
+func (p *Processor) poll(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for p.pool.Idle() > 0 {
+			record, err := p.queue.Claim(context.Background(), p.config.Owner, p.config.Lease)
+			if err != nil {
+				p.pool.reportError(nil, fmt.Errorf("failed to claim job: %w", err))
+				break
+			}
+			if record == nil {
+				break
+			}
+			if err := p.pool.Submit(p.job(*record)); err != nil {
+				p.fail(*record, err, time.Now())
+				break
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-p.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// job wraps a claimed record as a pool job
+func (p *Processor) job(record QueueRecord) Job {
+	return JobFunc(func(ctx context.Context) error {
+		p.mu.Lock()
+		handler, ok := p.handlers[record.Name]
+		p.mu.Unlock()
+
+		if !ok {
+			err := fmt.Errorf("no handler registered for job %s", record.Name)
+			p.fail(record, err, time.Time{})
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, p.config.Lease)
+		defer cancel()
+
+		err := runHandler(ctx, handler, record.Payload)
+		if err == nil {
+			if err := p.queue.Complete(context.Background(), record.ID, p.config.Owner); err != nil {
+				return fmt.Errorf("failed to complete job %s: %w", record.ID, err)
+			}
+			return nil
+		}
+
+		retryAt := time.Time{}
+		if record.Attempts < record.MaxAttempts {
+			retryAt = time.Now().Add(p.config.RetryDelay(record.Attempts))
+		}
+		p.fail(record, err, retryAt)
+		return fmt.Errorf("job %s (%s) attempt %d failed: %w", record.ID, record.Name, record.Attempts, err)
+	})
+}
+
+// fail records a failed attempt, reporting store errors to the pool
+func (p *Processor) fail(record QueueRecord, runErr error, retryAt time.Time) {
+	if err := p.queue.Fail(context.Background(), record.ID, p.config.Owner, runErr, retryAt); err != nil {
+		p.pool.reportError(nil, fmt.Errorf("failed to record failure of job %s: %w", record.ID, err))
+	}
+}
+
+// runHandler runs a handler, recovering panics so the failure is recorded
+func runHandler(ctx context.Context, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// defaultRetryDelay doubles from 1s up to 1h
+func defaultRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 13 {
+		return time.Hour
+	}
+	delay := time.Second << (attempts - 1)
+	if delay > time.Hour {
+		return time.Hour
+	}
+	return delay
+}
+
+// defaultOwner identifies this process by hostname and process ID
+func defaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// **************************************************
+// Memory Queue
+// **************************************************
+
+// memoryJob is a queued job and its lock
+type memoryJob struct {
+	record      QueueRecord
+	lockedBy    string
+	lockedUntil time.Time
+	dead        bool
+}
+
+// MemoryQueue is an in-process Queue, useful for tests and for
+// single-instance services that want the same code path as distributed ones
+type MemoryQueue struct {
+	mu   sync.Mutex
+	jobs map[string]*memoryJob
+}
+
+// NewMemoryQueue creates a new in-process queue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{jobs: make(map[string]*memoryJob)}
+}
+
+// Enqueue stores a new job
+func (q *MemoryQueue) Enqueue(ctx context.Context, record QueueRecord) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.jobs[record.ID]; exists {
+		return fmt.Errorf("job %s already exists", record.ID)
+	}
+	q.jobs[record.ID] = &memoryJob{record: record}
+	return nil
+}
+
+// Claim locks the highest-priority due job for owner
+func (q *MemoryQueue) Claim(ctx context.Context, owner string, lease time.Duration) (*QueueRecord, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	due := make([]*memoryJob, 0)
+	for _, job := range q.jobs {
+		if job.dead || job.record.RunAt.After(now) || now.Before(job.lockedUntil) {
+			continue
+		}
+		if job.lockedBy != "" && job.record.Attempts >= job.record.MaxAttempts {
+			job.record.LastError = fmt.Sprintf("lease expired on attempt %d of %d", job.record.Attempts, job.record.MaxAttempts)
+			job.lockedBy = ""
+			job.dead = true
+			continue
+		}
+		due = append(due, job)
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].record.Priority != due[j].record.Priority {
+			return due[i].record.Priority > due[j].record.Priority
+		}
+		return due[i].record.RunAt.Before(due[j].record.RunAt)
+	})
+
+	job := due[0]
+	job.lockedBy = owner
+	job.lockedUntil = now.Add(lease)
+	job.record.Attempts++
+	record := job.record
+	return &record, nil
+}
+
+// Complete removes a job claimed by owner
+func (q *MemoryQueue) Complete(ctx context.Context, id, owner string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job, ok := q.jobs[id]; ok && job.lockedBy == owner {
+		delete(q.jobs, id)
+	}
+	return nil
+}
+
+// Fail records a failed attempt of a job claimed by owner
+func (q *MemoryQueue) Fail(ctx context.Context, id, owner string, runErr error, retryAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok || job.lockedBy != owner {
+		return nil
+	}
+
+	if runErr != nil {
+		job.record.LastError = runErr.Error()
+	}
+	job.lockedBy = ""
+	job.lockedUntil = time.Time{}
+	if retryAt.IsZero() {
+		job.dead = true
+	} else {
+		job.record.RunAt = retryAt
+	}
+	return nil
+}
+
+// Dead returns the jobs that exhausted their attempts
+func (q *MemoryQueue) Dead() []QueueRecord {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	dead := make([]QueueRecord, 0)
+	for _, job := range q.jobs {
+		if job.dead {
+			dead = append(dead, job.record)
+		}
+	}
+	return dead
+}